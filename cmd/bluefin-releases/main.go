@@ -1,39 +1,124 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/castrojo/bluefin-releases/internal/activity"
+	"github.com/castrojo/bluefin-releases/internal/archive"
 	"github.com/castrojo/bluefin-releases/internal/bluefin"
+	"github.com/castrojo/bluefin-releases/internal/cache"
+	"github.com/castrojo/bluefin-releases/internal/category"
+	"github.com/castrojo/bluefin-releases/internal/dashboard"
+	"github.com/castrojo/bluefin-releases/internal/dates"
+	"github.com/castrojo/bluefin-releases/internal/dedupe"
+	"github.com/castrojo/bluefin-releases/internal/diff"
+	"github.com/castrojo/bluefin-releases/internal/feed"
 	"github.com/castrojo/bluefin-releases/internal/flathub"
 	"github.com/castrojo/bluefin-releases/internal/github"
 	"github.com/castrojo/bluefin-releases/internal/gitlab"
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/icons"
+	"github.com/castrojo/bluefin-releases/internal/markdown"
 	"github.com/castrojo/bluefin-releases/internal/models"
 	"github.com/castrojo/bluefin-releases/internal/mozilla"
+	"github.com/castrojo/bluefin-releases/internal/noise"
+	"github.com/castrojo/bluefin-releases/internal/render"
+	"github.com/castrojo/bluefin-releases/internal/repolist"
+	"github.com/castrojo/bluefin-releases/internal/search"
+	"github.com/castrojo/bluefin-releases/internal/skips"
+	"github.com/castrojo/bluefin-releases/internal/title"
+	relversion "github.com/castrojo/bluefin-releases/internal/version"
 )
 
 const version = "1.0.0"
 
+// defaultCacheTTL is how long cached API responses stay fresh by default (-cache-ttl)
+const defaultCacheTTL = 24 * time.Hour
+
+// Exit codes let CI tell transient failures worth retrying from broken configuration
+const (
+	exitFailure     = 1 // Uncategorized failure
+	exitConfig      = 2 // Invalid flags or input files
+	exitUpstream    = 3 // A required upstream source was unavailable
+	exitRateLimited = 4 // An upstream API rate limit was hit
+	exitOutput      = 5 // Writing output failed
+	exitTimeout     = 6 // -max-runtime was exceeded; partial output was written
+)
+
+// exitError attaches an exit code to a fatal error
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+func configError(err error) error { return &exitError{code: exitConfig, err: err} }
+func outputError(err error) error { return &exitError{code: exitOutput, err: err} }
+
+// upstreamError categorizes a failed fetch, singling out rate limits
+func upstreamError(err error) error {
+	if errors.Is(err, httpx.ErrRateLimited) {
+		return &exitError{code: exitRateLimited, err: err}
+	}
+	return &exitError{code: exitUpstream, err: err}
+}
+
+// exitCode maps an error returned by run to the process exit code
+func exitCode(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return exitFailure
+}
+
 // normalizeReleaseDates propagates the latest release date to top-level app fields
-// Ensures app.ReleaseDate and app.UpdatedAt are populated from the actual latest release
+// Ensures app.ReleaseDate and app.UpdatedAt are populated from the actual latest release.
+// Draft releases are never treated as the latest.
 func normalizeReleaseDates(apps []models.App) []models.App {
 	for i := range apps {
 		app := &apps[i]
 
-		if len(app.Releases) > 0 {
-			latest := app.Releases[0]
+		latestIndex := -1
+		for j, release := range app.Releases {
+			if !release.Draft {
+				latestIndex = j
+				break
+			}
+		}
 
-			// Always update ReleaseDate from latest release
-			app.ReleaseDate = latest.Date.Format(time.RFC3339)
+		if latestIndex >= 0 {
+			latest := app.Releases[latestIndex]
 
 			// Update Version from latest release if present
 			if latest.Version != "" {
 				app.Version = latest.Version
 			}
 
+			// An undated release (-date-fallback=zero) keeps the app's own dates
+			if latest.Date.IsZero() {
+				continue
+			}
+
+			// Always update ReleaseDate from latest release
+			app.ReleaseDate = latest.Date.Format(time.RFC3339)
+
 			// Update UpdatedAt if empty
 			if app.UpdatedAt == "" {
 				app.UpdatedAt = latest.Date.Format(time.RFC3339)
@@ -43,9 +128,181 @@ func normalizeReleaseDates(apps []models.App) []models.App {
 	return apps
 }
 
+// sortReleases orders each app's releases newest first. Releases on the same
+// date rank by version precedence, so "1.2.3" comes before "1.2.3-rc1".
+func sortReleases(apps []models.App) []models.App {
+	for i := range apps {
+		releases := apps[i].Releases
+		sort.SliceStable(releases, func(a, b int) bool {
+			if !releases[a].Date.Equal(releases[b].Date) {
+				return releases[a].Date.After(releases[b].Date)
+			}
+			return relversion.Compare(releases[a].Version, releases[b].Version) > 0
+		})
+	}
+	return apps
+}
+
+// dropPrereleases removes releases whose version carries a prerelease
+// qualifier such as -rc1, -beta or .dev
+func dropPrereleases(apps []models.App) []models.App {
+	for i := range apps {
+		var stable []models.Release
+		for _, release := range apps[i].Releases {
+			if v, ok := relversion.Parse(release.Version); ok && v.IsPrerelease() {
+				continue
+			}
+			stable = append(stable, release)
+		}
+		apps[i].Releases = stable
+	}
+	return apps
+}
+
+// readPrevious loads the apps of the previous run's output at path. A missing
+// file is a first run and yields no apps; an unreadable one is an error.
+func readPrevious(path string) ([]models.App, error) {
+	previous, err := diff.LoadApps(path)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("No previous output at %s; treating this as the first run", path)
+		return nil, nil
+	}
+	return previous, err
+}
+
+// computeDiff compares apps with the previous run's apps
+func computeDiff(previous, apps []models.App) diff.DiffResult {
+	result := diff.Compare(previous, apps)
+	log.Printf("🔀 Diff: %d added, %d updated, %d removed", len(result.Added), len(result.Updated), len(result.Removed))
+	return result
+}
+
+// checkAppIDs warns about curated app IDs Flathub doesn't list. Under strict
+// they're a configuration error, and failing to list Flathub's apps is fatal.
+func checkAppIDs(appIDs []string, strict bool) error {
+	log.Printf("Validating %d curated app IDs against Flathub...", len(appIDs))
+	invalid, err := flathub.ValidateAppIDs(appIDs)
+	if err != nil {
+		if strict {
+			return upstreamError(fmt.Errorf("validate app IDs: %w", err))
+		}
+		log.Printf("⚠️  Skipping app ID validation: %v", err)
+		return nil
+	}
+
+	for _, id := range invalid {
+		log.Printf("⚠️  Curated app ID not on Flathub: %s", id)
+	}
+	if len(invalid) > 0 && strict {
+		return configError(fmt.Errorf("%d curated app ID(s) not on Flathub", len(invalid)))
+	}
+	return nil
+}
+
+// preferredReleaseTypes maps a source name, as used by an override's preferred
+// source and -source-priority, to its release type
+var preferredReleaseTypes = map[string]string{
+	"github":    "github-release",
+	"gitlab":    "gitlab-release",
+	"mozilla":   "mozilla-release",
+	"rss":       "rss-release",
+	"appstream": "appstream",
+}
+
+// sourcesWithoutReleases are accepted in -source-priority but have no release
+// type to rank: Homebrew packages take their releases from the GitHub repo
+// their formula links to, so those releases rank as github
+var sourcesWithoutReleases = map[string]bool{"homebrew": true}
+
+// defaultSourcePriority is the order in which the same version's release from
+// one source wins over another's: upstream repos before the Flathub appstream
+var defaultSourcePriority = []string{"github", "gitlab", "mozilla", "rss", "appstream", "homebrew"}
+
+// sourcePriority ranks release types for deduplication; lower wins. Types
+// missing from the list rank after every listed one.
+type sourcePriority map[string]int
+
+// parseSourcePriority builds a sourcePriority from source names in precedence order
+func parseSourcePriority(sources []string) (sourcePriority, error) {
+	priority := make(sourcePriority, len(sources))
+	for i, source := range sources {
+		if sourcesWithoutReleases[source] {
+			continue
+		}
+		releaseType, ok := preferredReleaseTypes[source]
+		if !ok {
+			return nil, fmt.Errorf("unknown source %q in -source-priority: must be one of github, gitlab, mozilla, rss, appstream, homebrew", source)
+		}
+		if _, dup := priority[releaseType]; !dup {
+			priority[releaseType] = i
+		}
+	}
+	return priority, nil
+}
+
+// rank returns a release type's position in the priority
+func (p sourcePriority) rank(releaseType string) int {
+	if rank, ok := p[releaseType]; ok {
+		return rank
+	}
+	return len(preferredReleaseTypes)
+}
+
+// preferReleaseSources keeps only releases from the source an app's override
+// prefers (see flathub.PreferredSources). Appstream releases survive a repo
+// preference so deduplicateReleases can still borrow their notes before
+// dropping them. Apps whose preferred source has no releases keep the normal
+// merge.
+func preferReleaseSources(apps []models.App, preferred map[string]string) []models.App {
+	for i := range apps {
+		app := &apps[i]
+		source, ok := preferred[app.ID]
+		if !ok {
+			continue
+		}
+		releaseType, ok := preferredReleaseTypes[source]
+		if !ok {
+			log.Printf("⚠️  Ignoring unknown preferred source %q for %s", source, app.ID)
+			continue
+		}
+
+		var kept []models.Release
+		found := false
+		for _, release := range app.Releases {
+			if release.Type == releaseType {
+				found = true
+			} else if releaseType == "appstream" || release.Type != "appstream" {
+				continue
+			}
+			kept = append(kept, release)
+		}
+		if !found {
+			app.Debug.Record(models.DebugStep{
+				Stage: "dedupe",
+				Note:  fmt.Sprintf("preferred source %s has no releases; kept all sources", source),
+			})
+			continue
+		}
+
+		if removed := len(app.Releases) - len(kept); removed > 0 {
+			log.Printf("Removed %d release(s) from %s (prefers %s)", removed, app.ID, source)
+			app.Debug.Record(models.DebugStep{
+				Stage:   "dedupe",
+				Matched: true,
+				Note:    fmt.Sprintf("removed %d release(s) not from preferred source %s", removed, source),
+			})
+		}
+		app.Releases = kept
+	}
+	return apps
+}
+
 // deduplicateReleases removes appstream releases when actual repo releases (GitHub/GitLab/Mozilla) exist
-// This prevents duplicate entries for the same version showing different dates
-func deduplicateReleases(apps []models.App) []models.App {
+// This prevents duplicate entries for the same version showing different dates.
+// Where versions collide, priority decides which release survives: an appstream
+// release that outranks the repo release of its version is kept for
+// mergeDuplicateVersions to pick over the repo one.
+func deduplicateReleases(apps []models.App, priority sourcePriority) []models.App {
 	for i := range apps {
 		app := &apps[i]
 
@@ -65,34 +322,885 @@ func deduplicateReleases(apps []models.App) []models.App {
 
 		// If we have repo releases, filter out appstream releases
 		if hasRepoReleases {
+			mergeAppstreamNotes(app, priority)
+
+			// Best rank among the repo releases of each version
+			repoRanks := make(map[string]int)
+			for _, release := range app.Releases {
+				if release.Type == "appstream" {
+					continue
+				}
+				key := duplicateVersionKey(release.Version)
+				if rank, ok := repoRanks[key]; !ok || priority.rank(release.Type) < rank {
+					repoRanks[key] = priority.rank(release.Type)
+				}
+			}
+
 			filteredReleases := []models.Release{}
 			removedCount := 0
 			for _, release := range app.Releases {
 				if release.Type == "appstream" {
-					removedCount++
-					continue
+					rank, ok := repoRanks[duplicateVersionKey(release.Version)]
+					if !ok || priority.rank("appstream") >= rank {
+						removedCount++
+						continue
+					}
 				}
 				filteredReleases = append(filteredReleases, release)
 			}
 			app.Releases = filteredReleases
 			if removedCount > 0 {
 				log.Printf("Removed %d appstream release(s) from %s (has repo releases)", removedCount, app.ID)
+				app.Debug.Record(models.DebugStep{
+					Stage:   "dedupe",
+					Matched: true,
+					Note:    fmt.Sprintf("removed %d appstream release(s) in favor of repo releases", removedCount),
+				})
+			}
+		}
+
+		mergeDuplicateVersions(app, priority)
+	}
+	return apps
+}
+
+// mergeDuplicateVersions collapses releases of the same version, keyed on the
+// version alone so entries whose dates were parsed at different precision
+// don't survive as duplicates. The entry from the highest-priority source wins
+// (the first on a tie) and keeps the group's position; it takes the best date
+// of the group and fills empty notes or URL from the others.
+func mergeDuplicateVersions(app *models.App, priority sourcePriority) {
+	byKey := make(map[string]int, len(app.Releases))
+	merged := make([]models.Release, 0, len(app.Releases))
+	for _, release := range app.Releases {
+		key := duplicateVersionKey(release.Version)
+		i, ok := byKey[key]
+		if !ok {
+			byKey[key] = len(merged)
+			merged = append(merged, release)
+			continue
+		}
+
+		kept := &merged[i]
+		if priority.rank(release.Type) < priority.rank(kept.Type) {
+			*kept, release = release, *kept
+		}
+		if betterDate(release.Date, kept.Date) {
+			kept.Date = release.Date
+		}
+		if strings.TrimSpace(kept.Description) == "" {
+			kept.Description = release.Description
+			kept.DescriptionSource = release.DescriptionSource
+		}
+		if kept.URL == "" {
+			kept.URL = release.URL
+		}
+	}
+
+	if removed := len(app.Releases) - len(merged); removed > 0 {
+		app.Releases = merged
+		app.Debug.Record(models.DebugStep{
+			Stage:   "dedupe",
+			Matched: true,
+			Note:    fmt.Sprintf("merged %d release(s) duplicating another version", removed),
+		})
+	}
+}
+
+// duplicateVersionKey normalizes a version for duplicate detection: "v1.2.0"
+// and "1.2.0" match, but unlike releaseVersionKey a prerelease keeps its
+// qualifier so "1.2.0-rc1" stays distinct from "1.2.0"
+func duplicateVersionKey(v string) string {
+	parsed, ok := relversion.Parse(v)
+	if !ok {
+		return strings.ToLower(strings.TrimSpace(v))
+	}
+	parts := make([]string, len(parsed.Core))
+	for i, n := range parsed.Core {
+		parts[i] = strconv.Itoa(n)
+	}
+	key := strings.Join(parts, ".")
+	if parsed.IsPrerelease() {
+		key += "-" + strings.Join(parsed.Prerelease, ".")
+	}
+	return key
+}
+
+// betterDate reports whether candidate should replace current as a merged
+// release's date: any valid date beats a missing one, a timestamp with a time
+// of day beats a bare date (midnight UTC), and otherwise the earlier one wins
+func betterDate(candidate, current time.Time) bool {
+	switch {
+	case candidate.IsZero():
+		return false
+	case current.IsZero():
+		return true
+	case hasTimeOfDay(candidate) != hasTimeOfDay(current):
+		return hasTimeOfDay(candidate)
+	default:
+		return candidate.Before(current)
+	}
+}
+
+// hasTimeOfDay reports whether t carries more than a calendar date
+func hasTimeOfDay(t time.Time) bool {
+	return !t.UTC().Truncate(24 * time.Hour).Equal(t)
+}
+
+// mergeAppstreamNotes fills empty repo release notes from the appstream entry
+// for the same version before appstream releases are dropped, so a tag pushed
+// without notes still shows the Flathub changelog. Repo notes win when present
+// unless priority ranks appstream above the repo's source; the app-level
+// description is left alone.
+func mergeAppstreamNotes(app *models.App, priority sourcePriority) {
+	appstreamByVersion := make(map[string]models.Release)
+	for _, release := range app.Releases {
+		if release.Type == "appstream" && release.Description != "" {
+			appstreamByVersion[relversion.Key(release.Version)] = release
+		}
+	}
+	if len(appstreamByVersion) == 0 {
+		return
+	}
+
+	for i := range app.Releases {
+		release := &app.Releases[i]
+		if release.Type == "appstream" {
+			continue
+		}
+		empty := strings.TrimSpace(release.Description) == ""
+		if !empty && priority.rank("appstream") >= priority.rank(release.Type) {
+			continue
+		}
+		appstream, ok := appstreamByVersion[relversion.Key(release.Version)]
+		if !ok {
+			continue
+		}
+		release.Description = appstream.Description
+		release.DescriptionSource = appstream.DescriptionSource
+		reason := "repo release had none"
+		if !empty {
+			reason = "appstream has priority"
+		}
+		app.Debug.Record(models.DebugStep{
+			Stage:   "dedupe",
+			Matched: true,
+			Note:    fmt.Sprintf("used appstream notes for %s (%s)", release.Version, reason),
+		})
+	}
+}
+
+// enricher is a release source that adds releases to apps with a matching source repository
+type enricher struct {
+	Key    string // Name accepted by -enrichers
+	Name   string // Display name used in logs and performance metadata
+	Enrich func([]models.App) []models.App
+}
+
+// enrichers is the registry of release enrichers, run in order
+var enrichers = []enricher{
+	{Key: "github", Name: "GitHub", Enrich: github.EnrichWithGitHubReleases},
+	{Key: "gitlab", Name: "GitLab", Enrich: gitlab.EnrichWithGitLabReleases},
+	{Key: "mozilla", Name: "Mozilla", Enrich: mozilla.EnrichWithMozillaReleases},
+}
+
+// enricherKeys lists every -enrichers name, in run order
+func enricherKeys() []string {
+	keys := make([]string, len(enrichers))
+	for i, e := range enrichers {
+		keys[i] = e.Key
+	}
+	return keys
+}
+
+// Source names accepted by -sources
+const (
+	sourceFlathub  = "flathub"  // Flatpak apps and their Flathub details
+	sourceHomebrew = "homebrew" // Homebrew packages from Bluefin's Brewfiles
+	sourceTaps     = "taps"     // ublue-os tap packages
+	sourceOS       = "os"       // Bluefin OS stream releases
+	sourceLTS      = "lts"      // Bluefin LTS releases
+)
+
+// allSources lists every -sources name, in pipeline order
+var allSources = []string{sourceFlathub, sourceHomebrew, sourceTaps, sourceOS, sourceLTS}
+
+// selection is the set of sources or enrichers enabled for a run
+type selection map[string]bool
+
+// parseSelection checks names given to -flagName against known
+func parseSelection(flagName string, names, known []string) (selection, error) {
+	valid := make(map[string]bool, len(known))
+	for _, name := range known {
+		valid[name] = true
+	}
+	selected := make(selection, len(names))
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if !valid[name] {
+			return nil, fmt.Errorf("unknown name %q in -%s: must be one of %s", name, flagName, strings.Join(known, ", "))
+		}
+		selected[name] = true
+	}
+	return selected, nil
+}
+
+// loadRepoList reads a -repos-file into minimal apps for the enrichers
+func loadRepoList(path string) ([]models.App, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open repos file: %w", err)
+	}
+	defer file.Close()
+
+	return repolist.Parse(file)
+}
+
+// parseIndent converts an -indent value ("tab" or a number of spaces) to the
+// indentation string
+func parseIndent(value string) (string, error) {
+	if value == "tab" {
+		return "\t", nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > 8 {
+		return "", fmt.Errorf("invalid -indent %q: must be \"tab\" or 1-8 spaces", value)
+	}
+	return strings.Repeat(" ", n), nil
+}
+
+// readAppIDs reads app IDs one per line, trimming whitespace and skipping
+// blank lines, "#" comments, and repeats
+func readAppIDs(r io.Reader) ([]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		id := strings.TrimSpace(scanner.Text())
+		if id == "" || strings.HasPrefix(id, "#") || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// markNewApps flags apps first published on Flathub within the given window
+func markNewApps(apps []models.App, window time.Duration, now time.Time) []models.App {
+	for i := range apps {
+		app := &apps[i]
+		if app.AddedAt == "" || window <= 0 {
+			continue
+		}
+
+		addedAt, err := time.Parse(time.RFC3339, app.AddedAt)
+		if err != nil {
+			continue
+		}
+		app.NewApp = now.Sub(addedAt) <= window
+	}
+	return apps
+}
+
+// maintenanceWindow is how recent an app's last release or commit must be
+// for it to count as actively maintained
+const maintenanceWindow = 365 * 24 * time.Hour
+
+// setMaintenanceStatus marks apps "active" when they released or, with
+// -last-commit, committed within maintenanceWindow, and "inactive" otherwise.
+// Apps with neither date are left blank.
+func setMaintenanceStatus(apps []models.App, now time.Time) []models.App {
+	for i := range apps {
+		app := &apps[i]
+
+		var latest time.Time
+		for _, date := range []string{app.ReleaseDate, app.LastCommitDate} {
+			if parsed, err := time.Parse(time.RFC3339, date); err == nil && parsed.After(latest) {
+				latest = parsed
+			}
+		}
+		switch {
+		case latest.IsZero():
+			app.MaintenanceStatus = ""
+		case now.Sub(latest) <= maintenanceWindow:
+			app.MaintenanceStatus = "active"
+		default:
+			app.MaintenanceStatus = "inactive"
+		}
+	}
+	return apps
+}
+
+// defaultHomebrewIcon is the generic package icon for Homebrew apps without one
+const defaultHomebrewIcon = "https://brew.sh/assets/img/homebrew.svg"
+
+// applyFallbackIcons fills empty icons so the dashboard never shows a broken
+// image: Homebrew packages get homebrewIcon and everything else gets fallback.
+// An empty URL leaves those apps untouched.
+func applyFallbackIcons(apps []models.App, fallback, homebrewIcon string) []models.App {
+	for i := range apps {
+		if apps[i].Icon != "" {
+			continue
+		}
+		if apps[i].PackageType == "homebrew" && homebrewIcon != "" {
+			apps[i].Icon = homebrewIcon
+			continue
+		}
+		apps[i].Icon = fallback
+	}
+	return apps
+}
+
+// setPreviousVersions records the version a release upgraded from when its
+// title spells out the transition ("from 1.2 to 1.3", "1.2 ➡️ 1.3"). The new
+// side must match the release's own version so unrelated ranges in a title
+// aren't mistaken for it.
+func setPreviousVersions(apps []models.App) []models.App {
+	for i := range apps {
+		for j := range apps[i].Releases {
+			release := &apps[i].Releases[j]
+			from, to, ok := relversion.ParseTransition(release.Title)
+			if !ok || relversion.Key(to) != relversion.Key(release.Version) {
+				continue
 			}
+			release.PreviousVersion = from
 		}
 	}
 	return apps
 }
 
+// setPreviews records the first lines of each release's notes as plain text
+// for card previews; the full Description is kept
+func setPreviews(apps []models.App, lines int) []models.App {
+	for i := range apps {
+		for j := range apps[i].Releases {
+			release := &apps[i].Releases[j]
+			release.Preview = markdown.Preview(release.Description, lines)
+		}
+	}
+	return apps
+}
+
+// setPreviewImages records the first image in each release's notes so cards
+// can show a visual without loading the full description
+func setPreviewImages(apps []models.App) []models.App {
+	for i := range apps {
+		app := &apps[i]
+		for j := range app.Releases {
+			release := &app.Releases[j]
+			release.PreviewImage = markdown.FirstImage(release.Description, release.URL, app.SourceRepo)
+		}
+	}
+	return apps
+}
+
+// filterAppSet keeps only apps listed in the given app set ("core" or "dx").
+// An app listed in both sets is kept whichever one is selected. An empty set keeps everything.
+func filterAppSet(infos []bluefin.AppSetInfo, appSet string) []bluefin.AppSetInfo {
+	if appSet == "" {
+		return infos
+	}
+
+	var filtered []bluefin.AppSetInfo
+	for _, info := range infos {
+		if info.InSet(appSet) {
+			filtered = append(filtered, info)
+		}
+	}
+	return filtered
+}
+
+// runSummary is the machine-readable run report for CI
+type runSummary struct {
+	Success           bool           `json:"success"`
+	Changed           bool           `json:"changed"`           // False when -skip-unchanged left the output as is
+	Partial           bool           `json:"partial,omitempty"` // -max-runtime cut fetching short
+	SchemaVersion     string         `json:"schema_version"`
+	Duration          string         `json:"duration"`
+	AppsTotal         int            `json:"apps_total"`
+	FlatpakCount      int            `json:"flatpak_count"`
+	HomebrewCount     int            `json:"homebrew_count"`
+	OSCount           int            `json:"os_count"`
+	AppsWithGitHub    int            `json:"apps_with_github"`
+	AppsWithGitLab    int            `json:"apps_with_gitlab"`
+	AppsWithChangelog int            `json:"apps_with_changelog"`
+	TotalReleases     int            `json:"total_releases"`
+	ReleasesBySource  map[string]int `json:"releases_by_source"` // Keyed by Release.Type
+	ErrorCount        int            `json:"error_count"`
+	ErrorsBySource    map[string]int `json:"errors_by_source"`
+}
+
+// buildSummary derives the run summary from the final output and per-source fetch errors
+func buildSummary(output *models.OutputData, sourceErrors map[string]int) runSummary {
+	stats := output.Metadata.Stats
+	summary := runSummary{
+		Success:           true,
+		SchemaVersion:     output.Metadata.SchemaVersion,
+		Duration:          output.Metadata.BuildDuration,
+		AppsTotal:         stats.AppsTotal,
+		AppsWithGitHub:    stats.AppsWithGitHubRepo,
+		AppsWithGitLab:    stats.AppsWithGitLabRepo,
+		AppsWithChangelog: stats.AppsWithChangelogs,
+		TotalReleases:     stats.TotalReleases,
+		ReleasesBySource:  make(map[string]int),
+		ErrorsBySource:    make(map[string]int),
+	}
+
+	for _, app := range output.Apps {
+		switch app.PackageType {
+		case "flatpak":
+			summary.FlatpakCount++
+		case "homebrew":
+			summary.HomebrewCount++
+		case "os":
+			summary.OSCount++
+		}
+		for _, release := range app.Releases {
+			summary.ReleasesBySource[release.Type]++
+		}
+	}
+
+	for source, count := range sourceErrors {
+		summary.ErrorsBySource[source] = count
+		summary.ErrorCount += count
+	}
+
+	return summary
+}
+
+// stdout is where run prints the summary and -preview diff; tests swap it
+var stdout io.Writer = os.Stdout
+
+// markPartial flags summary as a failed, partial run when -max-runtime cut
+// fetching short, returning the error the run exits with
+func markPartial(summary *runSummary, partial bool, maxRuntime time.Duration) error {
+	if !partial {
+		return nil
+	}
+	summary.Success = false
+	summary.Partial = true
+	return &exitError{code: exitTimeout, err: fmt.Errorf("exceeded -max-runtime of %s; output is partial", maxRuntime)}
+}
+
+// summaryLine is the one-line form of the run summary printed with -quiet
+func summaryLine(summary runSummary) string {
+	return fmt.Sprintf("apps=%d flatpak=%d homebrew=%d os=%d changelogs=%d releases=%d errors=%d",
+		summary.AppsTotal, summary.FlatpakCount, summary.HomebrewCount, summary.OSCount,
+		summary.AppsWithChangelog, summary.TotalReleases, summary.ErrorCount)
+}
+
+// printSummary prints the run summary to w as indented JSON, or as a single
+// line when quiet
+func printSummary(w io.Writer, summary runSummary, quiet bool) {
+	if quiet {
+		fmt.Fprintln(w, summaryLine(summary))
+		return
+	}
+	summaryJSON, _ := json.MarshalIndent(summary, "", "  ")
+	fmt.Fprintln(w, string(summaryJSON))
+}
+
+// writeSummary writes the run summary as indented JSON
+func writeSummary(summary runSummary, path string) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode summary: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("write summary: %w", err)
+	}
+	return nil
+}
+
+// appendGitHubOutput sets a step output via $GITHUB_OUTPUT so later workflow
+// steps can branch on it (e.g. skip committing when nothing changed)
+func appendGitHubOutput(path, name, value string) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open step output: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintf(file, "%s=%s\n", name, value); err != nil {
+		return fmt.Errorf("write step output: %w", err)
+	}
+	return nil
+}
+
+// stampFetchedAt gives every app the same UTC run timestamp, so output doesn't
+// depend on which source finished first or on the runner's local timezone
+func stampFetchedAt(apps []models.App, runTime time.Time) []models.App {
+	runTime = runTime.UTC()
+	for i := range apps {
+		apps[i].FetchedAt = runTime
+	}
+	return apps
+}
+
+// sourceDateEpoch returns the SOURCE_DATE_EPOCH timestamp when set, so repeated
+// builds from the same inputs produce identical output; otherwise fallback.
+func sourceDateEpoch(fallback time.Time) time.Time {
+	value := os.Getenv("SOURCE_DATE_EPOCH")
+	if value == "" {
+		return fallback
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Printf("⚠️  Ignoring invalid SOURCE_DATE_EPOCH %q: %v", value, err)
+		return fallback
+	}
+	return time.Unix(seconds, 0).UTC()
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// stripDescriptionSources drops the pre-render release notes so the output
+// stays compact unless -keep-source is set
+func stripDescriptionSources(apps []models.App) []models.App {
+	for i := range apps {
+		for j := range apps[i].Releases {
+			apps[i].Releases[j].DescriptionSource = ""
+		}
+	}
+	return apps
+}
+
+// initExplain attaches an empty enrichment trace to every app and records
+// what the package metadata stage found, so enrichers can append their outcomes
+func initExplain(apps []models.App) {
+	for i := range apps {
+		app := &apps[i]
+		app.Debug = &models.DebugInfo{}
+		if app.SourceRepo != nil {
+			app.Debug.SourceRepo = app.SourceRepo.URL
+		}
+
+		note := "no source repository detected"
+		if app.SourceRepo != nil {
+			note = fmt.Sprintf("detected %s repository", app.SourceRepo.Type)
+		}
+		app.Debug.Record(models.DebugStep{
+			Stage:         "source",
+			Matched:       app.SourceRepo != nil,
+			ReleasesAdded: len(app.Releases),
+			Note:          note,
+		})
+	}
+}
+
+// listAppSets prints the curated Flatpak app set membership (core/dx) and exits
+// without running the pipeline
+func listAppSets(asJSON bool) error {
+	appSetInfos, err := bluefin.FetchFlatpakListWithAppSets()
+	if err != nil {
+		return fmt.Errorf("fetch app sets: %w", err)
+	}
+
+	sort.Slice(appSetInfos, func(i, j int) bool {
+		if appSetInfos[i].AppID != appSetInfos[j].AppID {
+			return appSetInfos[i].AppID < appSetInfos[j].AppID
+		}
+		return appSetInfos[i].AppSet < appSetInfos[j].AppSet
+	})
+
+	counts := make(map[string]int)
+	for _, info := range appSetInfos {
+		counts[info.AppSet]++
+	}
+
+	if asJSON {
+		output := map[string]interface{}{
+			"apps":   appSetInfos,
+			"counts": counts,
+			"total":  len(appSetInfos),
+		}
+		outputJSON, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode app sets: %w", err)
+		}
+		fmt.Println(string(outputJSON))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "APP ID\tAPP SET")
+	for _, info := range appSetInfos {
+		fmt.Fprintf(w, "%s\t%s\n", info.AppID, info.AppSet)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nTotal: %d (Core: %d, DX: %d)\n", len(appSetInfos), counts["core"], counts["dx"])
+	return nil
+}
+
 func main() {
+	if err := run(os.Args[1:]); err != nil {
+		log.Printf("❌ %v", err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// run executes the pipeline with the given command-line arguments. Fatal
+// errors carry an exit code (see exitCode) describing their category.
+func run(args []string) error {
 	// Parse command-line flags
-	legacyMode := flag.Bool("legacy", false, "Use legacy mode (fetch recently updated apps instead of Bluefin list)")
-	flag.Parse()
+	fs := flag.NewFlagSet("bluefin-releases", flag.ContinueOnError)
+	legacyMode := fs.Bool("legacy", false, "Use legacy mode (fetch apps from a Flathub feed, see -feed, instead of Bluefin list)")
+	flathubBudget := fs.Duration("flathub-budget", 0, "Wall-clock cap for Flathub enrichment, split across apps; apps out of time keep base data (0 = no cap)")
+	feedName := fs.String("feed", flathub.DefaultFeed, "Flathub feed to list apps from in legacy mode: "+strings.Join(flathub.Feeds(), ", "))
+	appSetFilter := fs.String("app-set", "", "Only include Flatpaks from this app set in Bluefin mode: core or dx (default all)")
+	validateIDs := fs.Bool("validate-app-ids", false, "Check curated Flatpak IDs against Flathub before enrichment and warn about typos and delisted apps")
+	strict := fs.Bool("strict", false, "Fail instead of warning when -validate-app-ids finds IDs Flathub doesn't list (implies -validate-app-ids)")
+	appIDsStdin := fs.Bool("app-ids-stdin", false, "Enrich exactly the Flathub app IDs read from stdin, one per line (blank lines and # comments are skipped)")
+	reposFile := fs.String("repos-file", "", "Enrich a file of github.com/owner/repo or gitlab host/group/project lines instead of Bluefin apps")
+	newAppWindow := fs.Duration("new-app-window", 30*24*time.Hour, "Mark apps first published on Flathub within this window as new (0 disables)")
+	outputPath := fs.String("output", "src/data/apps.json", "Path to write the output to")
+	templatePath := fs.String("template", "", "Render the output through this Go text/template file instead of writing JSON")
+	perAppFeeds := fs.String("per-app-feeds", "", "Also write one Atom feed per app with releases into this directory, named by app ID")
+	maxReleases := fs.Int("max-releases", 20, "Maximum releases per app in -per-app-feeds feeds (0 = all)")
+	keepDeprecated := fs.Bool("keep-deprecated", false, "Keep deprecated and disabled Homebrew formulae, flagged as such, instead of dropping them")
+	archiveDir := fs.String("archive-dir", "", "Also write a timestamped snapshot of the output (e.g. apps-20260203-150405.json) into this directory")
+	archiveRetention := fs.Duration("archive-retention", 0, "Delete -archive-dir snapshots older than this (0 keeps all)")
+	skippedOutput := fs.String("skipped-output", "", "Also write the apps, packages, and OS releases skipped this run, with reasons, to this JSON file (e.g. skipped.json)")
+	releasesOutput := fs.String("releases-output", "", "Also write every release flattened into one newest-first list (with metadata) to this JSON file")
+	releasesLimit := fs.Int("releases-limit", 100, "Maximum releases in -releases-output (0 = all)")
+	opmlPath := fs.String("opml", "", "Also write an OPML file of every app's release feed to this path")
+	searchIndexPath := fs.String("search-index", "", "Also write a compact search index (id, name, summary, keywords, category per app) to this path")
+	dashboardIndexPath := fs.String("dashboard-index", "", "Also write an ordered dashboard index (OS streams, then core, dx, Homebrew and other apps, each sorted by name) to this path")
+	sitemapPath := fs.String("sitemap", "", "Also write a sitemap.xml of app pages to this path (requires -sitemap-base-url)")
+	sitemapBaseURL := fs.String("sitemap-base-url", "", "Base URL of app pages in -sitemap; each page is <base>/<app ID>")
+	sitemapAll := fs.Bool("sitemap-all", false, "Include apps without releases in -sitemap")
+	diffOutput := fs.String("diff-output", "", "Write the apps added, updated, and removed since the previous run to this file")
+	diffAgainst := fs.String("diff-against", "", "Previous run's JSON output to diff against for -diff-output and -mark-new (default the existing -output file)")
+	markNew := fs.Bool("mark-new", false, "Flag releases that weren't in the previous run's output as isNew")
+	diffFormat := fs.String("diff-format", diff.FormatJSON, "Format of -diff-output: "+strings.Join(diff.Formats(), ", "))
+	duplicatesReport := fs.String("duplicates-report", "", "Write apps that look like the same software under two IDs (same normalized name and developer) to this JSON file")
+	mergeDuplicates := fs.Float64("merge-duplicates", 0, "Merge likely duplicate apps whose confidence is at least this (0-1; 0 only reports)")
+	explain := fs.Bool("explain", false, "Annotate each app with a trace of why it got (or didn't get) releases")
+	previewLines := fs.Int("preview-lines", 2, "Lines of plain-text release notes kept in each release's preview, skipping headings and badges (0 = no previews)")
+	keepSource := fs.Bool("keep-source", false, "Include the original markdown/text of each release alongside the rendered HTML")
+	listAppSetsMode := fs.Bool("list-app-sets", false, "Print the core/dx app set membership from the Brewfiles and exit")
+	jsonOutput := fs.Bool("json", false, "Print -list-app-sets output as JSON")
+	maxInFlight := fs.Int("max-in-flight", httpx.DefaultLimits.MaxInFlight, "Maximum concurrent HTTP requests across all sources (0 = unlimited)")
+	rps := fs.Float64("rps", httpx.DefaultLimits.RequestsPerSecond, "Maximum HTTP requests per second across all sources (0 = unlimited)")
+	hostMaxInFlight := fs.Int("host-max-in-flight", httpx.DefaultLimits.PerHostMaxInFlight, "Maximum concurrent HTTP requests per host (0 = unlimited)")
+	hostRPS := fs.Float64("host-rps", httpx.DefaultLimits.PerHostRequestsPerSecond, "Maximum HTTP requests per second per host (0 = unlimited)")
+	minify := fs.Bool("minify", false, "Also write a compact <output>.min.json alongside the pretty-printed output")
+	skipUnchanged := fs.Bool("skip-unchanged", false, "Leave the JSON output untouched when only timestamps and durations would change; reported as changed=false in the summary and $GITHUB_OUTPUT")
+	indent := fs.String("indent", "2", "Indentation of the JSON output: a number of spaces, or \"tab\"")
+	escapeHTML := fs.Bool("escape-html", false, "Escape <, > and & in JSON output for safe inlining into HTML pages")
+	fallbackIcon := fs.String("fallback-icon", "", "Icon URL for apps that have none after enrichment (empty leaves them blank)")
+	homebrewIcon := fs.String("homebrew-icon", defaultHomebrewIcon, "Icon URL for Homebrew packages that have none (empty uses -fallback-icon)")
+	iconsDir := fs.String("download-icons", "", "Download app icons into this directory and rewrite icon URLs to relative paths")
+	tagMessages := fs.Bool("tag-messages", false, "Use annotated tag messages as release notes when a GitHub/GitLab release has no body (extra API calls)")
+	reactions := fs.Bool("reactions", false, "Capture total GitHub reaction counts per release")
+	lastCommit := fs.Bool("last-commit", false, "Record each GitHub/GitLab repo's last commit date on its default branch (one extra request per repo)")
+	conserveQuota := fs.Bool("conserve-quota", false, "Fetch GitHub releases from Atom feeds instead of the REST API when /rate_limit shows too little quota for the run")
+	keepNewContributors := fs.Bool("keep-new-contributors", false, "Keep the autogenerated \"New Contributors\" section in GitHub release notes (its logins are recorded in each release's contributors either way)")
+	latestOnly := fs.Bool("latest-only", false, "Fetch only the newest GitHub release per app (one request each, no history)")
+	diagnostics := fs.Bool("diagnostics", false, "Include per-host HTTP response times in output metadata")
+	osStreams := fs.String("os-streams", "", "Comma-separated Bluefin OS streams to include: "+strings.Join(bluefin.KnownOSStreams, ", ")+" (default all)")
+	sourcePriorityList := fs.String("source-priority", strings.Join(defaultSourcePriority, ","), "Comma-separated sources in precedence order for the same version found in several: github, gitlab, mozilla, rss, appstream, homebrew (homebrew is accepted but ranks nothing, as its packages' releases come from GitHub; per-app preferred sources still win)")
+	osPackages := fs.String("os-packages", strings.Join(bluefin.DefaultMajorPackages, ","), "Comma-separated packages whose versions are read from Bluefin OS changelogs, named as in the changelog tables")
+	includeLTS := fs.Bool("include-lts", true, "Include Bluefin LTS releases")
+	sourceList := fs.String("sources", strings.Join(allSources, ","), "Comma-separated sources to fetch apps from: "+strings.Join(allSources, ", ")+" (Homebrew, taps and OS sources only run in Bluefin mode)")
+	enricherList := fs.String("enrichers", strings.Join(enricherKeys(), ","), "Comma-separated release enrichers to run: "+strings.Join(enricherKeys(), ", "))
+	cacheDir := fs.String("cache-dir", "", "Directory for caching API responses between runs (empty disables caching)")
+	cacheBackend := fs.String("cache-backend", cache.BackendDisk, "Where API responses are cached: disk (under -cache-dir, persisted across runs), memory (this run only), or none")
+	cacheTTL := fs.Duration("cache-ttl", defaultCacheTTL, "How long cached API responses stay fresh (sources with their own freshness, such as Brewfiles, Flathub and GitHub releases, ignore this)")
+	refreshCache := fs.Bool("refresh-cache", false, "Fetch everything again instead of serving fresh entries from -cache-dir (the cache is still updated)")
+	dateFallback := fs.String("date-fallback", string(dates.DefaultPolicy), "What to do with a release whose date is missing or unparseable: zero (sorts oldest), now (sorts newest), or skip (drop it)")
+	collapseWindow := fs.Duration("collapse-window", 0, "Keep only the latest of releases published within this window of each other, e.g. 24h (0 = off)")
+	noisePattern := fs.String("noise-pattern", "", "Drop releases whose title or version matches this regular expression, e.g. '(?i)nightly|^ci-'")
+	detectBreaking := fs.Bool("detect-breaking", false, "Flag releases that call out breaking changes or bump the major version")
+	preview := fs.Bool("preview", false, "Run the full pipeline and print what would change against the existing output (in -diff-format) without writing any files")
+	countOnly := fs.Bool("count-only", false, "Fetch and enrich as usual but write no files; only print the run summary")
+	quiet := fs.Bool("quiet", false, "Silence progress logs and print the summary as a single line")
+	summaryPath := fs.String("summary", "", "Write the run summary JSON to this file instead of stdout")
+	categoriesFile := fs.String("categories-file", "", "JSON file of Bluefin category rules layered over the built-in defaults")
+	maxRuntime := fs.Duration("max-runtime", 0, "Absolute ceiling for the run: when exceeded, in-flight fetches are cancelled and whatever was collected is written, flagged partial, exiting 6 (0 = no limit)")
+	offline := fs.Bool("offline", false, "Forbid network access and serve every request from -cache-dir (fails if data isn't cached)")
+	releaseTitle := fs.String("release-title", title.Default, "Go template for every release title, e.g. '{{.AppName}} {{.Version}}'")
+	stableOnly := fs.Bool("stable-versions-only", false, "Drop releases whose version is a prerelease (rc, beta, dev, ...)")
+	osCommits := fs.Bool("os-commits", false, "Attach the commit log between consecutive Bluefin OS releases (one extra request per stream)")
+	includeDrafts := fs.Bool("include-drafts", false, "Keep draft Bluefin OS releases for QA (requires GITHUB_TOKEN; drafts never become a stream's latest)")
+	topN := fs.Int("topn", 0, "Only output the N apps with the most releases in the last 90 days (0 = all)")
+	homebrewConcurrency := fs.Int("homebrew-concurrency", bluefin.DefaultOptions().HomebrewConcurrency, "Maximum concurrent Homebrew API requests for packages missing from the bulk list")
+	tapConcurrency := fs.Int("tap-concurrency", bluefin.DefaultOptions().TapConcurrency, "Maximum concurrent Homebrew tap file fetches across all taps")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return configError(err)
+	}
+
+	// -count-only and -preview run everything up to the write step, but write nothing
+	dryRun := *countOnly || *preview
+	if *quiet {
+		defer log.SetOutput(log.Writer())
+		log.SetOutput(io.Discard)
+	}
+
+	flathub.Configure(flathub.Options{
+		Feed:   *feedName,
+		Budget: *flathubBudget,
+	})
+	github.Configure(github.Options{
+		Reactions:           *reactions,
+		TagMessages:         *tagMessages,
+		LatestOnly:          *latestOnly,
+		LastCommit:          *lastCommit,
+		ConserveQuota:       *conserveQuota,
+		KeepNewContributors: *keepNewContributors,
+	})
+	gitlab.Configure(gitlab.Options{
+		TagMessages: *tagMessages,
+		LastCommit:  *lastCommit,
+	})
+	osStreamList := splitList(strings.ToLower(*osStreams))
+	if _, err := parseSelection("os-streams", osStreamList, bluefin.KnownOSStreams); err != nil {
+		return configError(err)
+	}
+	bluefin.Configure(bluefin.Options{
+		TapConcurrency:      *tapConcurrency,
+		HomebrewConcurrency: *homebrewConcurrency,
+		OSStreams:           osStreamList,
+		OSCommits:           *osCommits,
+		IncludeDrafts:       *includeDrafts,
+		MajorPackages:       splitList(*osPackages),
+		KeepDeprecated:      *keepDeprecated,
+	})
+
+	indentString, err := parseIndent(*indent)
+	if err != nil {
+		return configError(err)
+	}
+	sources, err := parseSelection("sources", splitList(*sourceList), allSources)
+	if err != nil {
+		return configError(err)
+	}
+	enabledEnrichers, err := parseSelection("enrichers", splitList(*enricherList), enricherKeys())
+	if err != nil {
+		return configError(err)
+	}
+	fallbackPolicy, err := dates.ParsePolicy(*dateFallback)
+	if err != nil {
+		return configError(fmt.Errorf("invalid -date-fallback: %w", err))
+	}
+	dates.SetFallback(fallbackPolicy)
+	priority, err := parseSourcePriority(splitList(*sourcePriorityList))
+	if err != nil {
+		return configError(err)
+	}
+	if *archiveRetention < 0 {
+		return configError(fmt.Errorf("invalid -archive-retention %s: must not be negative", *archiveRetention))
+	}
+	if *appIDsStdin && (*legacyMode || *reposFile != "") {
+		return configError(errors.New("-app-ids-stdin can't be combined with -legacy or -repos-file"))
+	}
+	if *appSetFilter != "" && *appSetFilter != "core" && *appSetFilter != "dx" {
+		return configError(fmt.Errorf("invalid -app-set %q: must be core or dx", *appSetFilter))
+	}
+	if !flathub.ValidFeed(*feedName) {
+		return configError(fmt.Errorf("invalid -feed %q: must be one of %s", *feedName, strings.Join(flathub.Feeds(), ", ")))
+	}
+	if *includeDrafts && os.Getenv("GITHUB_TOKEN") == "" {
+		return configError(errors.New("-include-drafts requires GITHUB_TOKEN (drafts are only visible to authenticated users)"))
+	}
+	if *mergeDuplicates < 0 || *mergeDuplicates > 1 {
+		return configError(fmt.Errorf("invalid -merge-duplicates %v: must be between 0 and 1", *mergeDuplicates))
+	}
+	if !diff.ValidFormat(*diffFormat) {
+		return configError(fmt.Errorf("invalid -diff-format %q: must be one of %s", *diffFormat, strings.Join(diff.Formats(), ", ")))
+	}
+	if *sitemapPath != "" && *sitemapBaseURL == "" {
+		return configError(errors.New("-sitemap requires -sitemap-base-url"))
+	}
+	responses, err := cache.New(*cacheBackend, *cacheDir)
+	if err != nil {
+		return configError(fmt.Errorf("invalid -cache-backend: %w", err))
+	}
+	if *offline && (*cacheBackend != cache.BackendDisk || *cacheDir == "") {
+		return configError(errors.New("-offline requires -cache-dir with the disk cache backend"))
+	}
+	// Record every response so a later -offline run can replay it
+	httpx.SetResponseCache(responses, *cacheTTL)
+	httpx.SetRefreshCache(*refreshCache)
+	httpx.SetOffline(*offline)
+
+	// Apply global HTTP limits before any fetcher runs
+	httpx.Configure(httpx.Limits{
+		MaxInFlight:              *maxInFlight,
+		RequestsPerSecond:        *rps,
+		PerHostMaxInFlight:       *hostMaxInFlight,
+		PerHostRequestsPerSecond: *hostRPS,
+	})
+
+	if *listAppSetsMode {
+		if err := listAppSets(*jsonOutput); err != nil {
+			return upstreamError(fmt.Errorf("list app sets: %w", err))
+		}
+		return nil
+	}
+
+	var noiseRe *regexp.Regexp
+	if *noisePattern != "" {
+		var err error
+		if noiseRe, err = regexp.Compile(*noisePattern); err != nil {
+			return configError(fmt.Errorf("invalid -noise-pattern: %w", err))
+		}
+	}
+
+	titleTemplate, err := title.Parse(*releaseTitle)
+	if err != nil {
+		return configError(fmt.Errorf("invalid -release-title: %w", err))
+	}
+
+	categoryRules, err := category.Load(*categoriesFile)
+	if err != nil {
+		return configError(fmt.Errorf("load category rules: %w", err))
+	}
 
 	startTime := time.Now()
+	runTime := sourceDateEpoch(startTime.UTC())
+
+	// Every request goes through httpx, so cancelling there stops all fetching
+	runCtx := context.Background()
+	if *maxRuntime > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, *maxRuntime)
+		defer cancel()
+	}
+	defer httpx.SetRunContext(runCtx)()
+
+	// Failed fetches per source, reported in the run summary
+	sourceErrors := make(map[string]int)
+
+	// Homebrew and OS sources only apply to the curated Bluefin list
+	bluefinMode := !*legacyMode && *reposFile == "" && !*appIDsStdin
 
 	log.Printf("Bluefin Releases Pipeline v%s", version)
-	if *legacyMode {
-		log.Println("Running in LEGACY mode (recently updated apps)")
+	if *reposFile != "" {
+		log.Printf("Running in REPOS mode (repositories from %s)", *reposFile)
+	} else if *appIDsStdin {
+		log.Println("Running in APP IDS mode (Flathub app IDs from stdin)")
+	} else if *legacyMode {
+		log.Printf("Running in LEGACY mode (%s apps)", *feedName)
 	} else {
 		log.Println("Running in BLUEFIN mode (curated app list)")
 	}
@@ -100,19 +1208,74 @@ func main() {
 
 	// Step 1: Fetch Flatpak apps and enrich with details
 	var flatpakApps []models.App
+	var repoApps []models.App
 	flathubStart := time.Now()
 
-	if *legacyMode {
-		// Legacy mode: fetch recently updated apps
-		log.Println("Fetching recently updated Flathub apps...")
-		results := flathub.FetchAllApps()
-		flatpakApps = results.Apps
+	if *reposFile != "" {
+		// Repos mode: build minimal apps from a hand-picked repository list
+		var err error
+		repoApps, err = loadRepoList(*reposFile)
+		if err != nil {
+			return configError(fmt.Errorf("load repos file: %w", err))
+		}
+		log.Printf("Loaded %d repositories from %s", len(repoApps), *reposFile)
+	} else if !sources[sourceFlathub] {
+		log.Println("Skipping Flatpak apps (flathub not in -sources)")
+	} else if *appIDsStdin {
+		// App IDs mode: the explicit-apps path with IDs piped in
+		appIDs, err := readAppIDs(os.Stdin)
+		if err != nil {
+			return configError(fmt.Errorf("read app IDs from stdin: %w", err))
+		}
+		if len(appIDs) == 0 {
+			return configError(errors.New("-app-ids-stdin: no app IDs on stdin"))
+		}
+		if *validateIDs || *strict {
+			if err := checkAppIDs(appIDs, *strict); err != nil {
+				return err
+			}
+		}
+		// A slow stdin producer can use up the budget before anything is fetched
+		if runCtx.Err() != nil {
+			log.Println("⏰ Skipping Flathub apps: -max-runtime exceeded while reading app IDs")
+		} else {
+			log.Printf("Fetching %d Flatpak apps from Flathub...", len(appIDs))
+			results, err := flathub.FetchAllApps(appIDs...)
+			if err != nil && runCtx.Err() != nil {
+				log.Printf("⚠️  Failed to fetch Flathub apps before -max-runtime: %v", err)
+				sourceErrors["flathub"]++
+			} else if err != nil {
+				return upstreamError(fmt.Errorf("fetch Flathub apps: %w", err))
+			} else {
+				flatpakApps = results.Apps
+			}
+		}
+	} else if *legacyMode {
+		// Legacy mode: fetch the apps in a Flathub feed
+		log.Printf("Fetching Flathub %s apps...", *feedName)
+		results, err := flathub.FetchAllApps()
+		if err != nil && runCtx.Err() != nil {
+			log.Printf("⚠️  Failed to fetch Flathub apps before -max-runtime: %v", err)
+			sourceErrors["flathub"]++
+		} else if err != nil {
+			return upstreamError(fmt.Errorf("fetch Flathub apps: %w", err))
+		} else {
+			flatpakApps = results.Apps
+		}
 	} else {
 		// Bluefin mode: fetch specific apps from Bluefin Brewfiles
 		log.Println("Fetching Bluefin app list...")
 		appSetInfos, err := bluefin.FetchFlatpakListWithAppSets()
-		if err != nil {
-			log.Fatalf("Failed to fetch Bluefin app list: %v", err)
+		if err != nil && runCtx.Err() != nil {
+			log.Printf("⚠️  Failed to fetch Bluefin app list before -max-runtime: %v", err)
+			sourceErrors["bluefin-flatpaks"]++
+		} else if err != nil {
+			return upstreamError(fmt.Errorf("fetch Bluefin app list: %w", err))
+		}
+		if *appSetFilter != "" {
+			total := len(appSetInfos)
+			appSetInfos = filterAppSet(appSetInfos, *appSetFilter)
+			log.Printf("App set filter %q kept %d of %d app entries", *appSetFilter, len(appSetInfos), total)
 		}
 
 		// Create app set map for lookup
@@ -123,14 +1286,36 @@ func main() {
 			appSetMap[info.AppID] = info.AppSet
 		}
 
-		log.Printf("Fetching %d Bluefin-curated Flatpak apps from Flathub...", len(appIDs))
-		results := flathub.FetchAllApps(appIDs...)
-		flatpakApps = results.Apps
+		// Out of time already: go straight to writing what other sources
+		// collect, rather than fetching (or falling back to a Flathub feed)
+		if runCtx.Err() != nil {
+			log.Println("⏰ Skipping Flathub apps: -max-runtime exceeded while listing them")
+		} else if len(appIDs) == 0 {
+			// FetchAllApps with no IDs would fall back to the whole Flathub feed
+			log.Printf("⚠️  No Bluefin Flatpak apps to fetch (app set filter %q), skipping Flathub", *appSetFilter)
+		} else {
+			if *validateIDs || *strict {
+				if err := checkAppIDs(appIDs, *strict); err != nil {
+					return err
+				}
+			}
+
+			log.Printf("Fetching %d Bluefin-curated Flatpak apps from Flathub...", len(appIDs))
+			results, err := flathub.FetchAllApps(appIDs...)
+			if err != nil && runCtx.Err() != nil {
+				log.Printf("⚠️  Failed to fetch Flathub apps before -max-runtime: %v", err)
+				sourceErrors["flathub"]++
+			} else if err != nil {
+				return upstreamError(fmt.Errorf("fetch Flathub apps: %w", err))
+			} else {
+				flatpakApps = results.Apps
+			}
 
-		// Add app set information to each app
-		for i := range flatpakApps {
-			if appSet, ok := appSetMap[flatpakApps[i].ID]; ok {
-				flatpakApps[i].AppSet = appSet
+			// Add app set information to each app
+			for i := range flatpakApps {
+				if appSet, ok := appSetMap[flatpakApps[i].ID]; ok {
+					flatpakApps[i].AppSet = appSet
+				}
 			}
 		}
 	}
@@ -142,7 +1327,7 @@ func main() {
 	var homebrewApps []models.App
 	homebrewDuration := time.Duration(0)
 
-	if !*legacyMode {
+	if bluefinMode && sources[sourceHomebrew] {
 		log.Println("Fetching Homebrew packages...")
 		homebrewStart := time.Now()
 
@@ -150,17 +1335,21 @@ func main() {
 		homebrewApps, err = bluefin.FetchHomebrewPackages()
 		if err != nil {
 			log.Printf("⚠️  Failed to fetch Homebrew packages: %v", err)
+			sourceErrors["homebrew"]++
 		} else {
 			homebrewDuration = time.Since(homebrewStart)
 			log.Printf("Fetched %d Homebrew packages in %s", len(homebrewApps), homebrewDuration)
 		}
+	}
 
-		// Step 2b: Fetch ublue-os tap packages
+	// Step 2b: Fetch ublue-os tap packages
+	if bluefinMode && sources[sourceTaps] {
 		log.Println("Fetching ublue-os tap packages...")
 		tapStart := time.Now()
 		tapApps, err := bluefin.FetchUblueOSTapPackages()
 		if err != nil {
 			log.Printf("⚠️  Failed to fetch tap packages: %v", err)
+			sourceErrors["homebrew-taps"]++
 		} else {
 			tapDuration := time.Since(tapStart)
 			log.Printf("Fetched %d tap packages in %s", len(tapApps), tapDuration)
@@ -173,7 +1362,7 @@ func main() {
 	var osApps []models.App
 	osDuration := time.Duration(0)
 
-	if !*legacyMode {
+	if bluefinMode && sources[sourceOS] {
 		log.Println("Fetching Bluefin OS releases...")
 		osStart := time.Now()
 
@@ -181,17 +1370,21 @@ func main() {
 		osApps, err = bluefin.FetchBluefinOSApps()
 		if err != nil {
 			log.Printf("⚠️  Failed to fetch Bluefin OS releases: %v", err)
+			sourceErrors["bluefin-os"]++
 		} else {
 			osDuration = time.Since(osStart)
 			log.Printf("Fetched %d Bluefin OS releases in %s", len(osApps), osDuration)
 		}
+	}
 
-		// Also fetch Bluefin LTS releases
+	// Also fetch Bluefin LTS releases
+	if bluefinMode && *includeLTS && sources[sourceLTS] {
 		log.Println("Fetching Bluefin LTS releases...")
 		ltsStart := time.Now()
 		ltsApps, err := bluefin.FetchBluefinLTSApps()
 		if err != nil {
 			log.Printf("⚠️  Failed to fetch Bluefin LTS releases: %v", err)
+			sourceErrors["bluefin-lts"]++
 		} else {
 			ltsDuration := time.Since(ltsStart)
 			log.Printf("Fetched %d Bluefin LTS releases in %s", len(ltsApps), ltsDuration)
@@ -203,36 +1396,63 @@ func main() {
 	// Step 4: Merge Flatpak, Homebrew, and OS releases
 	allApps := append(flatpakApps, homebrewApps...)
 	allApps = append(allApps, osApps...)
-	log.Printf("Total apps: %d (%d Flatpak + %d Homebrew + %d OS)", len(allApps), len(flatpakApps), len(homebrewApps), len(osApps))
-
-	// Step 5: Enrich with GitHub releases (from actual source repos)
-	log.Println("Enriching with GitHub releases from source repositories...")
-	githubStart := time.Now()
-	enrichedApps := github.EnrichWithGitHubReleases(allApps)
-	githubDuration := time.Since(githubStart)
-	log.Printf("GitHub enrichment complete in %s", githubDuration)
-
-	// Step 5.5: Enrich with GitLab releases (from actual source repos)
-	log.Println("Enriching with GitLab releases from source repositories...")
-	gitlabStart := time.Now()
-	enrichedApps = gitlab.EnrichWithGitLabReleases(enrichedApps)
-	gitlabDuration := time.Since(gitlabStart)
-	log.Printf("GitLab enrichment complete in %s", gitlabDuration)
-
-	// Step 5.6: Enrich with Mozilla release notes (Firefox and Thunderbird)
-	log.Println("Enriching Mozilla products with release notes...")
-	mozillaStart := time.Now()
-	enrichedApps = mozilla.EnrichWithMozillaReleases(enrichedApps)
-	mozillaDuration := time.Since(mozillaStart)
-	log.Printf("Mozilla enrichment complete in %s", mozillaDuration)
+	allApps = append(allApps, repoApps...)
+	log.Printf("Total apps: %d (%d Flatpak + %d Homebrew + %d OS + %d repos)", len(allApps), len(flatpakApps), len(homebrewApps), len(osApps), len(repoApps))
+
+	if *explain {
+		log.Println("Explain mode enabled: recording per-app enrichment traces")
+		initExplain(allApps)
+	}
+
+	// Step 5: Enrich with releases from each registered source (GitHub, GitLab, Mozilla)
+	enrichedApps := allApps
+	enrichDurations := make(map[string]time.Duration)
+	for _, e := range enrichers {
+		if !enabledEnrichers[e.Key] {
+			log.Printf("Skipping %s enrichment (not in -enrichers)", e.Name)
+			continue
+		}
+		log.Printf("Enriching with %s releases...", e.Name)
+		enrichStart := time.Now()
+		enrichedApps = e.Enrich(enrichedApps)
+		enrichDurations[e.Name] = time.Since(enrichStart)
+		log.Printf("%s enrichment complete in %s", e.Name, enrichDurations[e.Name])
+	}
+
+	// Fetching is over; anything cut short by -max-runtime is already reflected in the apps
+	partial := runCtx.Err() != nil
+	if partial {
+		log.Printf("⏰ -max-runtime of %s exceeded: fetches were cancelled, writing partial output", *maxRuntime)
+	}
 
 	// Step 5.7: Deduplicate releases (remove appstream releases when actual repo releases exist)
 	log.Println("Deduplicating releases (removing appstream releases when repo releases exist)...")
 	dedupeStart := time.Now()
-	enrichedApps = deduplicateReleases(enrichedApps)
+	enrichedApps = preferReleaseSources(enrichedApps, flathub.PreferredSources())
+	enrichedApps = deduplicateReleases(enrichedApps, priority)
 	dedupeDuration := time.Since(dedupeStart)
 	log.Printf("Release deduplication complete in %s", dedupeDuration)
 
+	if *duplicatesReport != "" || *mergeDuplicates > 0 {
+		duplicates := dedupe.Find(enrichedApps)
+		log.Printf("Found %d likely duplicate app pair(s) by name", len(duplicates))
+		if *mergeDuplicates > 0 {
+			enrichedApps = dedupe.Merge(enrichedApps, duplicates, *mergeDuplicates)
+		}
+		if *duplicatesReport != "" && !dryRun {
+			if err := dedupe.WriteReport(duplicates, *duplicatesReport); err != nil {
+				log.Printf("⚠️  Failed to write duplicates report: %v", err)
+			} else {
+				log.Printf("🔍 Duplicates report: %s", *duplicatesReport)
+			}
+		}
+	}
+
+	if *stableOnly {
+		enrichedApps = dropPrereleases(enrichedApps)
+	}
+	enrichedApps = sortReleases(enrichedApps)
+
 	// Step 5.8: Normalize top-level fields from latest release
 	log.Println("Normalizing top-level date fields from latest releases...")
 	normalizeStart := time.Now()
@@ -240,6 +1460,44 @@ func main() {
 	normalizeDuration := time.Since(normalizeStart)
 	log.Printf("Date normalization complete in %s", normalizeDuration)
 
+	if *collapseWindow > 0 || noiseRe != nil {
+		for i := range enrichedApps {
+			enrichedApps[i].Releases = noise.Filter(enrichedApps[i].Releases, *collapseWindow, noiseRe)
+		}
+	}
+
+	// Read transitions from the upstream titles before the release title template replaces them
+	enrichedApps = setPreviousVersions(enrichedApps)
+	enrichedApps = title.Apply(enrichedApps, titleTemplate)
+	enrichedApps = setPreviewImages(enrichedApps)
+	enrichedApps = setPreviews(enrichedApps, *previewLines)
+	enrichedApps = markNewApps(enrichedApps, *newAppWindow, runTime)
+	enrichedApps = setMaintenanceStatus(enrichedApps, runTime)
+	if *detectBreaking {
+		for i := range enrichedApps {
+			relversion.MarkBreaking(enrichedApps[i].Releases)
+		}
+	}
+	enrichedApps = categoryRules.Apply(enrichedApps)
+	enrichedApps = applyFallbackIcons(enrichedApps, *fallbackIcon, *homebrewIcon)
+	enrichedApps = stampFetchedAt(enrichedApps, runTime)
+
+	if *topN > 0 {
+		log.Printf("Keeping the %d most active apps...", *topN)
+		enrichedApps = activity.Top(enrichedApps, *topN, runTime)
+	}
+
+	if !*keepSource {
+		enrichedApps = stripDescriptionSources(enrichedApps)
+	}
+
+	if *iconsDir != "" && !dryRun {
+		log.Printf("Downloading icons to %s...", *iconsDir)
+		if err := icons.Download(enrichedApps, *iconsDir); err != nil {
+			log.Printf("⚠️  Failed to download icons: %v", err)
+		}
+	}
+
 	// Step 5: Sort by update date (Flatpak apps have updatedAt, Homebrew may not)
 	// For now, just use the order they come in (Flatpak first, then Homebrew)
 	// Future: could sort by latest release date
@@ -252,6 +1510,7 @@ func main() {
 	flatpakCount := 0
 	homebrewCount := 0
 	osCount := 0
+	eolCount := 0
 
 	for _, app := range enrichedApps {
 		if app.SourceRepo != nil {
@@ -272,68 +1531,252 @@ func main() {
 		} else if app.PackageType == "os" {
 			osCount++
 		}
+		if app.EOL {
+			eolCount++
+		}
 	}
 
 	log.Printf("Apps with GitHub repos: %d", appsWithGitHubRepo)
 	log.Printf("Apps with GitLab repos: %d", appsWithGitLabRepo)
 	log.Printf("Apps with changelogs: %d", appsWithChangelogs)
 	log.Printf("Total releases: %d", totalReleases)
+	if eolCount > 0 {
+		log.Printf("⚠️  Curated apps no longer on Flathub: %d (update the Brewfile list)", eolCount)
+	}
+
+	// Refuse to write partial output when offline mode couldn't serve everything
+	if misses := httpx.OfflineMisses(); len(misses) > 0 {
+		for _, url := range misses {
+			log.Printf("  not cached: %s", url)
+		}
+		return upstreamError(fmt.Errorf("offline mode: %d requests were not in the cache at %s", len(misses), *cacheDir))
+	}
 
 	// Step 7: Build output structure
 	buildDuration := time.Since(startTime)
 	output := &models.OutputData{
 		Metadata: models.Metadata{
 			SchemaVersion: "1.0.0",
-			GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
+			GeneratedAt:   runTime.UTC().Format(time.RFC3339),
 			GeneratedBy:   fmt.Sprintf("bluefin-releases v%s", version),
+			Partial:       partial,
 			BuildDuration: buildDuration.String(),
 			Stats: models.Stats{
 				AppsTotal:          len(enrichedApps),
 				AppsWithGitHubRepo: appsWithGitHubRepo,
 				AppsWithGitLabRepo: appsWithGitLabRepo,
 				AppsWithChangelogs: appsWithChangelogs,
+				AppsEOL:            eolCount,
 				TotalReleases:      totalReleases,
 			},
 			Performance: models.Performance{
 				FlathubFetchDuration: flathubDuration.String(),
 				DetailsFetchDuration: flathubDuration.String(), // Combined in FetchAllApps
-				GitHubFetchDuration:  githubDuration.String(),
-				GitLabFetchDuration:  gitlabDuration.String(),
-				MozillaFetchDuration: mozillaDuration.String(),
+				GitHubFetchDuration:  enrichDurations["GitHub"].String(),
+				GitLabFetchDuration:  enrichDurations["GitLab"].String(),
+				MozillaFetchDuration: enrichDurations["Mozilla"].String(),
 				OutputDuration:       "0s", // Will be updated
 			},
 		},
 		Apps: enrichedApps,
 	}
 
+	if *diagnostics {
+		output.Metadata.HostTimings = httpx.HostTimings()
+		hosts := make([]string, 0, len(output.Metadata.HostTimings))
+		for host := range output.Metadata.HostTimings {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+		for _, host := range hosts {
+			timing := output.Metadata.HostTimings[host]
+			log.Printf("⏱️  %s: %d requests, min %s, avg %s, max %s", host, timing.Count, timing.Min, timing.Avg, timing.Max)
+		}
+
+		output.Metadata.CacheStats = httpx.CacheStats()
+		sources := make([]string, 0, len(output.Metadata.CacheStats))
+		for source := range output.Metadata.CacheStats {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+		for _, source := range sources {
+			stats := output.Metadata.CacheStats[source]
+			log.Printf("🗄️  %s cache: %d hits, %d misses, %d refreshed, %d bypassed", source, stats.Hits, stats.Misses, stats.Refreshes, stats.Bypassed)
+		}
+	}
+
+	// The previous output is read once, before it's overwritten below; every
+	// diff sink then shares the same result
+	var changes *diff.DiffResult
+	if *diffOutput != "" || *markNew || *preview {
+		previousPath := *diffAgainst
+		if previousPath == "" {
+			previousPath = *outputPath
+		}
+		if previous, err := readPrevious(previousPath); err != nil {
+			log.Printf("⚠️  Failed to read previous output %s: %v", previousPath, err)
+		} else {
+			if *markNew {
+				log.Printf("🆕 Flagged %d releases new since the previous run", diff.MarkNew(previous, enrichedApps))
+			}
+			if *diffOutput != "" || *preview {
+				result := computeDiff(previous, enrichedApps)
+				changes = &result
+			}
+		}
+	}
+
+	// -preview prints the pending changes and stops before anything is written
+	if *preview {
+		if changes == nil {
+			// The previous output couldn't be read; everything would be new
+			result := computeDiff(nil, enrichedApps)
+			changes = &result
+		}
+		if err := diff.Write(stdout, *changes, *diffFormat); err != nil {
+			log.Printf("⚠️  Failed to print preview: %v", err)
+		}
+		return nil
+	}
+
+	// -count-only stops before anything is written
+	if *countOnly {
+		summary := buildSummary(output, sourceErrors)
+		summary.Changed = false
+		runErr := markPartial(&summary, partial, *maxRuntime)
+		printSummary(stdout, summary, *quiet)
+		return runErr
+	}
+
 	// Step 8: Write output JSON
-	log.Println("Writing output JSON...")
 	outputStart := time.Now()
-	outputPath := "src/data/apps.json"
-	if err := output.WriteJSON(outputPath); err != nil {
-		log.Fatalf("Failed to write output: %v", err)
+	changed := true
+	if *templatePath != "" {
+		log.Printf("Rendering output with template %s...", *templatePath)
+		if err := render.WriteTemplate(output, *templatePath, *outputPath); err != nil {
+			return outputError(fmt.Errorf("render output template: %w", err))
+		}
+	} else {
+		log.Println("Writing output JSON...")
+		jsonOpts := models.JSONOptions{EscapeHTML: *escapeHTML, Indent: indentString}
+		if *minify {
+			jsonOpts.MinifiedPath = strings.TrimSuffix(*outputPath, ".json") + ".min.json"
+		}
+		if *skipUnchanged {
+			var err error
+			if changed, err = output.WriteJSONIfChanged(*outputPath, jsonOpts); err != nil {
+				return outputError(fmt.Errorf("write output: %w", err))
+			}
+			if !changed {
+				log.Printf("✨ No changes: %s left as is", *outputPath)
+			}
+		} else if err := output.WriteJSONWithOptions(*outputPath, jsonOpts); err != nil {
+			return outputError(fmt.Errorf("write output: %w", err))
+		}
+	}
+	if *archiveDir != "" {
+		if path, err := archive.Write(output, *archiveDir, *outputPath, runTime, models.JSONOptions{EscapeHTML: *escapeHTML, Indent: indentString}); err != nil {
+			log.Printf("⚠️  Failed to archive output: %v", err)
+		} else {
+			log.Printf("🗃️  Archived output to %s", path)
+		}
+		if *archiveRetention > 0 {
+			if removed, err := archive.Prune(*archiveDir, *outputPath, *archiveRetention, runTime); err != nil {
+				log.Printf("⚠️  Failed to prune archives: %v", err)
+			} else if removed > 0 {
+				log.Printf("🗃️  Pruned %d archived snapshot(s) older than %s", removed, *archiveRetention)
+			}
+		}
+	}
+	if *releasesOutput != "" {
+		if written, err := feed.WriteReleases(output, *releasesOutput, *releasesLimit); err != nil {
+			log.Printf("⚠️  Failed to write releases stream: %v", err)
+		} else {
+			log.Printf("🕒 Releases stream: %d releases in %s", written, *releasesOutput)
+		}
+	}
+	if *skippedOutput != "" {
+		report := skips.Collect()
+		if err := skips.WriteFile(report, *skippedOutput); err != nil {
+			log.Printf("⚠️  Failed to write skip report: %v", err)
+		} else {
+			log.Printf("⏭️  Skip report: %d skipped in %s", report.Total, *skippedOutput)
+		}
+	}
+	if *opmlPath != "" {
+		if err := feed.WriteOPML(enrichedApps, *opmlPath); err != nil {
+			log.Printf("⚠️  Failed to write OPML: %v", err)
+		} else {
+			log.Printf("📰 OPML: %s", *opmlPath)
+		}
+	}
+	if *searchIndexPath != "" {
+		if err := search.WriteIndex(enrichedApps, *searchIndexPath); err != nil {
+			log.Printf("⚠️  Failed to write search index: %v", err)
+		} else {
+			log.Printf("🔎 Search index: %s", *searchIndexPath)
+		}
+	}
+	if *dashboardIndexPath != "" {
+		if err := dashboard.WriteIndex(enrichedApps, *dashboardIndexPath); err != nil {
+			log.Printf("⚠️  Failed to write dashboard index: %v", err)
+		} else {
+			log.Printf("🧭 Dashboard index: %s", *dashboardIndexPath)
+		}
+	}
+	if *sitemapPath != "" {
+		if written, err := feed.WriteSitemap(enrichedApps, *sitemapBaseURL, *sitemapPath, *sitemapAll); err != nil {
+			log.Printf("⚠️  Failed to write sitemap: %v", err)
+		} else {
+			log.Printf("🗺️  Sitemap: %d URLs in %s", written, *sitemapPath)
+		}
+	}
+	if *perAppFeeds != "" {
+		if written, err := feed.WriteAppFeeds(enrichedApps, *perAppFeeds, *maxReleases); err != nil {
+			log.Printf("⚠️  Failed to write per-app feeds: %v", err)
+		} else {
+			log.Printf("📰 Per-app feeds: %d in %s", written, *perAppFeeds)
+		}
 	}
 	outputDuration := time.Since(outputStart)
 	output.Metadata.Performance.OutputDuration = outputDuration.String()
 
 	// Log final summary
 	log.Printf("✅ Pipeline complete in %s", buildDuration)
-	log.Printf("📊 Output: %s", outputPath)
+	log.Printf("📊 Output: %s", *outputPath)
 	log.Printf("📦 Packages: %d Flatpak + %d Homebrew + %d OS = %d total", flatpakCount, homebrewCount, osCount, len(enrichedApps))
 
 	// Write summary as JSON for GitHub Actions
-	summary := map[string]interface{}{
-		"success":             true,
-		"duration":            buildDuration.String(),
-		"apps_total":          len(enrichedApps),
-		"flatpak_count":       flatpakCount,
-		"homebrew_count":      homebrewCount,
-		"os_count":            osCount,
-		"apps_with_github":    appsWithGitHubRepo,
-		"apps_with_gitlab":    appsWithGitLabRepo,
-		"apps_with_changelog": appsWithChangelogs,
-		"total_releases":      totalReleases,
+	summary := buildSummary(output, sourceErrors)
+	summary.Changed = changed
+	runErr := markPartial(&summary, partial, *maxRuntime)
+	if githubOutput := os.Getenv("GITHUB_OUTPUT"); githubOutput != "" {
+		if err := appendGitHubOutput(githubOutput, "changed", strconv.FormatBool(changed)); err != nil {
+			log.Printf("⚠️  Failed to write step output: %v", err)
+		}
 	}
-	summaryJSON, _ := json.MarshalIndent(summary, "", "  ")
-	fmt.Println(string(summaryJSON))
+	if stepSummary := os.Getenv("GITHUB_STEP_SUMMARY"); stepSummary != "" {
+		stats := diff.RunStats{
+			AppsTotal:         summary.AppsTotal,
+			AppsWithChangelog: summary.AppsWithChangelog,
+			TotalReleases:     summary.TotalReleases,
+			Duration:          summary.Duration,
+			ErrorsBySource:    summary.ErrorsBySource,
+		}
+		if err := diff.AppendStepSummary(stepSummary, output.Apps, stats); err != nil {
+			log.Printf("⚠️  Failed to write job summary: %v", err)
+		}
+	}
+	if changes != nil {
+		diff.Publish(*changes, []diff.Sink{diff.FileSink(*diffOutput, *diffFormat)})
+	}
+	if *summaryPath != "" {
+		if err := writeSummary(summary, *summaryPath); err != nil {
+			return outputError(fmt.Errorf("write summary: %w", err))
+		}
+		log.Printf("📋 Summary: %s", *summaryPath)
+		return runErr
+	}
+	printSummary(stdout, summary, *quiet)
+	return runErr
 }
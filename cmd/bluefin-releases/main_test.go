@@ -0,0 +1,902 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/bluefin"
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// defaultPriority is the -source-priority default
+var defaultPriority, _ = parseSourcePriority(defaultSourcePriority)
+
+func TestMarkNewApps(t *testing.T) {
+	now := time.Date(2026, 2, 8, 12, 0, 0, 0, time.UTC)
+	window := 30 * 24 * time.Hour
+
+	apps := []models.App{
+		{ID: "recent", AddedAt: now.Add(-5 * 24 * time.Hour).Format(time.RFC3339)},
+		{ID: "old", AddedAt: now.Add(-90 * 24 * time.Hour).Format(time.RFC3339)},
+		{ID: "unknown"},
+		{ID: "malformed", AddedAt: "not-a-date"},
+	}
+
+	apps = markNewApps(apps, window, now)
+
+	want := map[string]bool{"recent": true, "old": false, "unknown": false, "malformed": false}
+	for _, app := range apps {
+		if app.NewApp != want[app.ID] {
+			t.Errorf("Expected NewApp=%v for %s, got %v", want[app.ID], app.ID, app.NewApp)
+		}
+	}
+}
+
+func TestSetMaintenanceStatus(t *testing.T) {
+	now := time.Date(2026, 2, 8, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-30 * 24 * time.Hour).Format(time.RFC3339)
+	old := now.Add(-3 * 365 * 24 * time.Hour).Format(time.RFC3339)
+
+	apps := []models.App{
+		{ID: "releasing", ReleaseDate: recent},
+		{ID: "committing", ReleaseDate: old, LastCommitDate: recent},
+		{ID: "dormant", ReleaseDate: old},
+		{ID: "unknown"},
+	}
+
+	apps = setMaintenanceStatus(apps, now)
+
+	want := map[string]string{"releasing": "active", "committing": "active", "dormant": "inactive", "unknown": ""}
+	for _, app := range apps {
+		if app.MaintenanceStatus != want[app.ID] {
+			t.Errorf("Expected status %q for %s, got %q", want[app.ID], app.ID, app.MaintenanceStatus)
+		}
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	output := &models.OutputData{
+		Metadata: models.Metadata{
+			SchemaVersion: "1.0.0",
+			BuildDuration: "1m2s",
+			Stats: models.Stats{
+				AppsTotal:          3,
+				AppsWithGitHubRepo: 1,
+				AppsWithChangelogs: 2,
+				TotalReleases:      3,
+			},
+		},
+		Apps: []models.App{
+			{ID: "org.example.App", PackageType: "flatpak", Releases: []models.Release{{Type: "github-release"}, {Type: "appstream"}}},
+			{ID: "homebrew-gh", PackageType: "homebrew"},
+			{ID: "bluefin-os-stable", PackageType: "os", Releases: []models.Release{{Type: "bluefin-os-release"}}},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "summary.json")
+	if err := writeSummary(buildSummary(output, map[string]int{"homebrew-taps": 1, "bluefin-lts": 2}), path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read summary: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Summary is not valid JSON: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"success":        true,
+		"schema_version": "1.0.0",
+		"duration":       "1m2s",
+		"apps_total":     float64(3),
+		"flatpak_count":  float64(1),
+		"homebrew_count": float64(1),
+		"os_count":       float64(1),
+		"total_releases": float64(3),
+		"error_count":    float64(3),
+	}
+	for key, want := range expected {
+		if got[key] != want {
+			t.Errorf("Expected %s = %v, got %v", key, want, got[key])
+		}
+	}
+
+	bySource, _ := got["releases_by_source"].(map[string]interface{})
+	if bySource["github-release"] != float64(1) || bySource["bluefin-os-release"] != float64(1) {
+		t.Errorf("Expected per-source release counts, got %v", bySource)
+	}
+	errorsBySource, _ := got["errors_by_source"].(map[string]interface{})
+	if errorsBySource["bluefin-lts"] != float64(2) {
+		t.Errorf("Expected 2 bluefin-lts errors, got %v", errorsBySource["bluefin-lts"])
+	}
+}
+
+func TestStampFetchedAt(t *testing.T) {
+	local := time.FixedZone("CET", 60*60)
+	runTime := time.Date(2026, 2, 8, 13, 0, 0, 0, local)
+
+	apps := stampFetchedAt([]models.App{
+		{ID: "org.example.Flatpak", PackageType: "flatpak", FetchedAt: time.Now().UTC()},
+		{ID: "homebrew-gh", PackageType: "homebrew", FetchedAt: time.Now().In(local)},
+		{ID: "bluefin-os-stable", PackageType: "os"},
+	}, runTime)
+
+	for _, app := range apps {
+		if app.FetchedAt.Location() != time.UTC {
+			t.Errorf("Expected %s FetchedAt in UTC, got %s", app.ID, app.FetchedAt.Location())
+		}
+		if !app.FetchedAt.Equal(runTime) {
+			t.Errorf("Expected %s FetchedAt %s, got %s", app.ID, runTime, app.FetchedAt)
+		}
+	}
+}
+
+func TestSourceDateEpoch(t *testing.T) {
+	fallback := time.Date(2026, 2, 8, 12, 0, 0, 0, time.UTC)
+
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+	if got := sourceDateEpoch(fallback); !got.Equal(fallback) {
+		t.Errorf("Expected fallback %s, got %s", fallback, got)
+	}
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1767225600")
+	got := sourceDateEpoch(fallback)
+	if !got.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || got.Location() != time.UTC {
+		t.Errorf("Expected 2026-01-01T00:00:00Z, got %s", got)
+	}
+}
+
+func TestDeduplicateReleasesMergesAppstreamNotes(t *testing.T) {
+	apps := deduplicateReleases([]models.App{{
+		ID:          "org.example.App",
+		Description: "<p>An example app from Flathub</p>",
+		Releases: []models.Release{
+			{Version: "v2.0.0", Type: "github-release", Description: "<h2>Highlights</h2><ul><li>New UI</li></ul>"},
+			{Version: "v1.9.0", Type: "github-release"},
+			{Version: "2.0.0", Type: "appstream", Description: "<p>Bug fixes</p>"},
+			{Version: "1.9.0", Type: "appstream", Description: "<p>Faster startup</p>"},
+		},
+	}}, defaultPriority)
+
+	app := apps[0]
+	if len(app.Releases) != 2 {
+		t.Fatalf("Expected appstream releases removed, got %d releases", len(app.Releases))
+	}
+	if app.Releases[0].Description != "<h2>Highlights</h2><ul><li>New UI</li></ul>" {
+		t.Errorf("Expected GitHub notes to win for v2.0.0, got %q", app.Releases[0].Description)
+	}
+	if app.Releases[1].Description != "<p>Faster startup</p>" {
+		t.Errorf("Expected appstream notes to fill empty v1.9.0, got %q", app.Releases[1].Description)
+	}
+	if app.Description != "<p>An example app from Flathub</p>" {
+		t.Errorf("Expected app-level description unchanged, got %q", app.Description)
+	}
+}
+
+func TestDeduplicateReleasesMergesDatePrecision(t *testing.T) {
+	precise := time.Date(2026, 3, 15, 14, 30, 0, 0, time.UTC)
+	apps := deduplicateReleases([]models.App{{
+		ID: "org.example.App",
+		Releases: []models.Release{
+			{Version: "v2.0.0", Type: "rss-release", Date: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), URL: "https://example.org/2.0.0"},
+			{Version: "2.0.0", Type: "rss-release", Date: precise, Description: "<p>Notes</p>"},
+			{Version: "2.0.0-rc1", Type: "rss-release", Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}}, defaultPriority)
+
+	releases := apps[0].Releases
+	if len(releases) != 2 {
+		t.Fatalf("Expected 2.0.0 merged and 2.0.0-rc1 kept, got %+v", releases)
+	}
+	merged := releases[0]
+	if merged.Version != "v2.0.0" || merged.URL != "https://example.org/2.0.0" {
+		t.Errorf("Expected the first entry to be kept, got %+v", merged)
+	}
+	if !merged.Date.Equal(precise) {
+		t.Errorf("Expected the more precise date %s, got %s", precise, merged.Date)
+	}
+	if merged.Description != "<p>Notes</p>" {
+		t.Errorf("Expected notes filled from the duplicate, got %q", merged.Description)
+	}
+	if releases[1].Version != "2.0.0-rc1" {
+		t.Errorf("Expected the prerelease to stay distinct, got %s", releases[1].Version)
+	}
+}
+
+func TestSourcePriorityPicksSurvivingNotes(t *testing.T) {
+	releases := func() []models.Release {
+		return []models.Release{
+			{Version: "v2.0.0", Type: "github-release", Description: "<p>GitHub notes</p>"},
+			{Version: "2.0.0", Type: "rss-release", Description: "<p>Blog notes</p>"},
+			{Version: "2.0.0", Type: "appstream", Description: "<p>Appstream notes</p>"},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		priority []string
+		want     string
+		wantType string
+	}{
+		{name: "default prefers GitHub", priority: defaultSourcePriority, want: "<p>GitHub notes</p>", wantType: "github-release"},
+		{name: "rss first", priority: []string{"rss", "github", "appstream"}, want: "<p>Blog notes</p>", wantType: "rss-release"},
+		{name: "appstream first", priority: []string{"appstream", "github", "rss"}, want: "<p>Appstream notes</p>", wantType: "appstream"},
+		{name: "homebrew accepted", priority: []string{"github", "gitlab", "appstream", "homebrew"}, want: "<p>GitHub notes</p>", wantType: "github-release"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priority, err := parseSourcePriority(tt.priority)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			apps := deduplicateReleases([]models.App{{ID: "org.example.App", Releases: releases()}}, priority)
+			got := apps[0].Releases
+			if len(got) != 1 {
+				t.Fatalf("Expected one release for the colliding version, got %+v", got)
+			}
+			if got[0].Description != tt.want {
+				t.Errorf("Expected %q to survive, got %q", tt.want, got[0].Description)
+			}
+			if got[0].Type != tt.wantType {
+				t.Errorf("Expected the %s release to survive, got %s", tt.wantType, got[0].Type)
+			}
+		})
+	}
+
+	if _, err := parseSourcePriority([]string{"github", "sourceforge"}); err == nil {
+		t.Error("Expected an unknown source to be rejected")
+	}
+}
+
+func TestBetterDate(t *testing.T) {
+	day := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	morning := time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC)
+	evening := time.Date(2026, 3, 15, 21, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		candidate time.Time
+		current   time.Time
+		want      bool
+	}{
+		{name: "valid beats missing", candidate: day, current: time.Time{}, want: true},
+		{name: "missing never wins", candidate: time.Time{}, current: day, want: false},
+		{name: "time of day beats bare date", candidate: evening, current: day, want: true},
+		{name: "bare date loses to time of day", candidate: day.Add(-24 * time.Hour), current: evening, want: false},
+		{name: "earlier wins at equal precision", candidate: morning, current: evening, want: true},
+		{name: "later loses at equal precision", candidate: evening, current: morning, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := betterDate(tt.candidate, tt.current); got != tt.want {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestReadAppIDs(t *testing.T) {
+	input := "org.gnome.Loupe\n\n  org.mozilla.firefox  \n# Games\ncom.valvesoftware.Steam\r\norg.gnome.Loupe\n"
+	got, err := readAppIDs(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{"org.gnome.Loupe", "org.mozilla.firefox", "com.valvesoftware.Steam"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if got, err := readAppIDs(strings.NewReader("# nothing here\n\n")); err != nil || len(got) != 0 {
+		t.Errorf("Expected no IDs, got %v (%v)", got, err)
+	}
+}
+
+func TestPreferReleaseSources(t *testing.T) {
+	apps := []models.App{
+		{
+			ID: "org.example.Noisy",
+			Releases: []models.Release{
+				{Version: "v2.0.0", Type: "github-release", Description: "<p>Autogenerated: 40 commits</p>"},
+				{Version: "2.0.0", Type: "appstream", Description: "<p>Curated changelog</p>"},
+				{Version: "1.9.0", Type: "appstream", Description: "<p>Faster startup</p>"},
+			},
+		},
+		{
+			ID: "org.example.Default",
+			Releases: []models.Release{
+				{Version: "v1.0.0", Type: "github-release"},
+				{Version: "1.0.0", Type: "appstream"},
+			},
+		},
+		{
+			ID:       "org.example.Fallback",
+			Releases: []models.Release{{Version: "v3.0.0", Type: "github-release"}},
+		},
+	}
+	preferred := map[string]string{
+		"org.example.Noisy":    "appstream",
+		"org.example.Fallback": "appstream",
+	}
+
+	apps = deduplicateReleases(preferReleaseSources(apps, preferred), defaultPriority)
+
+	tests := []struct {
+		id   string
+		want []string // type@version
+	}{
+		{id: "org.example.Noisy", want: []string{"appstream@2.0.0", "appstream@1.9.0"}},
+		{id: "org.example.Default", want: []string{"github-release@v1.0.0"}},
+		{id: "org.example.Fallback", want: []string{"github-release@v3.0.0"}},
+	}
+	for i, tt := range tests {
+		var got []string
+		for _, release := range apps[i].Releases {
+			got = append(got, release.Type+"@"+release.Version)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected releases %v, got %v", tt.id, tt.want, got)
+		}
+	}
+	if apps[0].Releases[0].Description != "<p>Curated changelog</p>" {
+		t.Errorf("Expected appstream notes kept, got %q", apps[0].Releases[0].Description)
+	}
+}
+
+func TestFilterAppSet(t *testing.T) {
+	infos := []bluefin.AppSetInfo{
+		{AppID: "org.mozilla.firefox", AppSet: "core"},
+		{AppID: "org.gnome.Loupe", AppSet: "core", AppSets: []string{"core", "dx"}}, // Listed in both sets
+		{AppID: "com.visualstudio.code", AppSet: "dx", AppSets: []string{"dx"}},
+		{AppID: "io.podman_desktop.PodmanDesktop", AppSet: "dx"},
+	}
+
+	tests := []struct {
+		appSet string
+		want   []string
+	}{
+		{appSet: "", want: []string{"org.mozilla.firefox", "org.gnome.Loupe", "com.visualstudio.code", "io.podman_desktop.PodmanDesktop"}},
+		{appSet: "core", want: []string{"org.mozilla.firefox", "org.gnome.Loupe"}},
+		{appSet: "dx", want: []string{"org.gnome.Loupe", "com.visualstudio.code", "io.podman_desktop.PodmanDesktop"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("set="+tt.appSet, func(t *testing.T) {
+			var got []string
+			for _, info := range filterAppSet(infos, tt.appSet) {
+				got = append(got, info.AppID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFilterAppSetFetchedList(t *testing.T) {
+	defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{
+		"/system-flatpaks.Brewfile":    "flatpak \"org.mozilla.firefox\"\nflatpak \"org.gnome.Loupe\"\n",
+		"/system-dx-flatpaks.Brewfile": "flatpak \"org.gnome.Loupe\"\nflatpak \"com.visualstudio.code\"\n",
+	}))()
+
+	infos, err := bluefin.FetchFlatpakListWithAppSets()
+	if err != nil {
+		t.Fatalf("Failed to fetch app sets: %v", err)
+	}
+
+	tests := []struct {
+		appSet string
+		want   []string
+	}{
+		{appSet: "core", want: []string{"org.mozilla.firefox", "org.gnome.Loupe"}},
+		{appSet: "dx", want: []string{"org.gnome.Loupe", "com.visualstudio.code"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("set="+tt.appSet, func(t *testing.T) {
+			var got []string
+			for _, info := range filterAppSet(infos, tt.appSet) {
+				got = append(got, info.AppID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSetPreviousVersions(t *testing.T) {
+	apps := []models.App{{
+		ID: "org.example.App",
+		Releases: []models.Release{
+			{Version: "v1.3.0", Title: "Updated from 1.2.0 to 1.3.0"},
+			{Version: "25.2.8", Title: "Mesa 25.2.7 ➡️ 25.2.8"},
+			{Version: "2.0.0", Title: "Migrate settings from 1.5 to 1.6"},
+			{Version: "1.1.0", Title: "Release 1.1.0"},
+		},
+	}}
+
+	want := []string{"1.2.0", "25.2.7", "", ""}
+	apps = setPreviousVersions(apps)
+	for i, release := range apps[0].Releases {
+		if release.PreviousVersion != want[i] {
+			t.Errorf("Release %s: expected previous version %q, got %q", release.Version, want[i], release.PreviousVersion)
+		}
+	}
+}
+
+func TestSortReleasesAndDropPrereleases(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	newApps := func() []models.App {
+		return []models.App{{
+			ID: "org.example.App",
+			Releases: []models.Release{
+				{Version: "1.2.3-rc1", Date: day},
+				{Version: "1.2.2", Date: day.AddDate(0, 0, -10)},
+				{Version: "1.2.3", Date: day},
+				{Version: "1.2.3-beta", Date: day.AddDate(0, 0, -3)},
+			},
+		}}
+	}
+
+	var got []string
+	for _, release := range sortReleases(newApps())[0].Releases {
+		got = append(got, release.Version)
+	}
+	if want := "1.2.3,1.2.3-rc1,1.2.3-beta,1.2.2"; strings.Join(got, ",") != want {
+		t.Errorf("Expected %s, got %v", want, got)
+	}
+
+	got = nil
+	for _, release := range sortReleases(dropPrereleases(newApps()))[0].Releases {
+		got = append(got, release.Version)
+	}
+	if want := "1.2.3,1.2.2"; strings.Join(got, ",") != want {
+		t.Errorf("Expected %s, got %v", want, got)
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	emptyRepos := filepath.Join(dir, "repos.txt")
+	if err := os.WriteFile(emptyRepos, nil, 0644); err != nil {
+		t.Fatalf("Failed to write repos file: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		status int
+		args   []string
+		want   int
+	}{
+		{name: "unknown flag", args: []string{"-no-such-flag"}, want: exitConfig},
+		{name: "invalid app set", args: []string{"-app-set", "gaming"}, want: exitConfig},
+		{name: "offline without cache", args: []string{"-offline"}, want: exitConfig},
+		{name: "merge threshold out of range", args: []string{"-merge-duplicates", "1.5"}, want: exitConfig},
+		{name: "sitemap without base URL", args: []string{"-sitemap", filepath.Join(dir, "sitemap.xml")}, want: exitConfig},
+		{name: "stdin IDs with legacy mode", args: []string{"-app-ids-stdin", "-legacy"}, want: exitConfig},
+		{name: "invalid indent", args: []string{"-indent", "0"}, want: exitConfig},
+		{name: "unknown diff format", args: []string{"-diff-format", "yaml"}, want: exitConfig},
+		{name: "unknown source priority", args: []string{"-source-priority", "github,sourceforge"}, want: exitConfig},
+		{name: "unknown source", args: []string{"-sources", "flathub,codeberg"}, want: exitConfig},
+		{name: "unknown enricher", args: []string{"-enrichers", "sourcehut"}, want: exitConfig},
+		{name: "unknown OS stream", args: []string{"-os-streams", "stabel"}, want: exitConfig},
+		{name: "unknown date fallback", args: []string{"-date-fallback", "newest"}, want: exitConfig},
+		{name: "unknown cache backend", args: []string{"-cache-backend", "redis"}, want: exitConfig},
+		{name: "offline with memory cache", args: []string{"-offline", "-cache-backend", "memory", "-cache-dir", dir}, want: exitConfig},
+		{name: "negative archive retention", args: []string{"-archive-retention", "-1h"}, want: exitConfig},
+		{name: "missing repos file", args: []string{"-repos-file", filepath.Join(dir, "missing.txt")}, want: exitConfig},
+		{name: "rate limited", status: http.StatusForbidden, want: exitRateLimited},
+		{name: "upstream unavailable", status: http.StatusServiceUnavailable, want: exitUpstream},
+		{name: "legacy feed unavailable", status: http.StatusServiceUnavailable, args: []string{"-legacy"}, want: exitUpstream},
+		{name: "output write", args: []string{"-repos-file", emptyRepos, "-output", filepath.Join(dir, "missing", "apps.json")}, want: exitOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			if status == 0 {
+				status = http.StatusNotFound
+			}
+			defer httpx.SetBaseTransport(httpxtest.Status(status))()
+
+			err := run(tt.args)
+			if err == nil {
+				t.Fatal("Expected run to fail")
+			}
+			if got := exitCode(err); got != tt.want {
+				t.Errorf("Expected exit code %d, got %d (%v)", tt.want, got, err)
+			}
+		})
+	}
+
+	if got := exitCode(fmt.Errorf("boom")); got != exitFailure {
+		t.Errorf("Expected uncategorized errors to exit %d, got %d", exitFailure, got)
+	}
+}
+
+func TestRunMaxRuntimeWritesPartialOutput(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	dir := t.TempDir()
+	reposFile := filepath.Join(dir, "repos.txt")
+	if err := os.WriteFile(reposFile, []byte("github.com/cli/cli\n"), 0644); err != nil {
+		t.Fatalf("Failed to write repos file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		wantIDs []string
+	}{
+		{name: "repos file", args: []string{"-repos-file", reposFile}, wantIDs: []string{"github.com/cli/cli"}},
+		{name: "bluefin mode", args: []string{"-include-lts=false"}},
+		{name: "app IDs from stdin", args: []string{"-app-ids-stdin"}, stdin: "org.mozilla.firefox\n", wantIDs: []string{"org.mozilla.firefox"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "apps.json")
+			defer httpx.SetBaseTransport(httpxtest.Hang())()
+			if tt.stdin != "" {
+				stdinPath := filepath.Join(t.TempDir(), "stdin.txt")
+				if err := os.WriteFile(stdinPath, []byte(tt.stdin), 0644); err != nil {
+					t.Fatalf("Failed to write stdin: %v", err)
+				}
+				stdin, err := os.Open(stdinPath)
+				if err != nil {
+					t.Fatalf("Failed to open stdin: %v", err)
+				}
+				defer stdin.Close()
+				origStdin := os.Stdin
+				defer func() { os.Stdin = origStdin }()
+				os.Stdin = stdin
+			}
+
+			start := time.Now()
+			args := append(tt.args, "-output", outputPath, "-summary", filepath.Join(t.TempDir(), "summary.json"), "-max-runtime", "200ms")
+			err := run(args)
+			if elapsed := time.Since(start); elapsed > 10*time.Second {
+				t.Errorf("Expected the run to stop shortly after -max-runtime, took %s", elapsed)
+			}
+			if got := exitCode(err); got != exitTimeout {
+				t.Fatalf("Expected exit code %d, got %d (%v)", exitTimeout, got, err)
+			}
+
+			data, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("Expected partial output to be written: %v", err)
+			}
+			var output models.OutputData
+			if err := json.Unmarshal(data, &output); err != nil {
+				t.Fatalf("Partial output is not valid JSON: %v", err)
+			}
+			if !output.Metadata.Partial {
+				t.Error("Expected output to be flagged partial")
+			}
+			var ids []string
+			for _, app := range output.Apps {
+				ids = append(ids, app.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("Expected apps %v in partial output, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+}
+
+func TestRunEmptyAppSetSkipsFlathub(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "apps.json")
+	// Everything but the Brewfiles 404s, so falling back to the Flathub feed fails the run
+	defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{
+		"/system-flatpaks.Brewfile":    "flatpak \"org.mozilla.firefox\"\n",
+		"/system-dx-flatpaks.Brewfile": "# No dx apps\n",
+	}))()
+
+	err := run([]string{"-sources", "flathub", "-app-set", "dx", "-output", outputPath, "-summary", filepath.Join(t.TempDir(), "summary.json")})
+	if err != nil {
+		t.Fatalf("Expected an empty app set to succeed, got %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	var output models.OutputData
+	if err := json.Unmarshal(data, &output); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(output.Apps) != 0 {
+		t.Errorf("Expected no apps, got %d", len(output.Apps))
+	}
+}
+
+func TestRunExplainRecordsSteps(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	dir := t.TempDir()
+	reposFile := filepath.Join(dir, "repos.txt")
+	if err := os.WriteFile(reposFile, []byte("github.com/cli/cli\ngitlab.com/gnome/loupe\n"), 0644); err != nil {
+		t.Fatalf("Failed to write repos file: %v", err)
+	}
+	// One GitHub and one GitLab release; everything else 404s
+	defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{
+		"/repos/cli/cli/releases":          `[{"tag_name": "v2.0.0", "name": "v2.0.0", "body": "Notes", "published_at": "2026-02-01T10:00:00Z", "html_url": "https://github.com/cli/cli/releases/tag/v2.0.0"}]`,
+		"/projects/gnome%2Floupe/releases": `[{"tag_name": "48.0", "name": "48.0", "description": "Notes", "released_at": "2026-02-02T10:00:00Z"}]`,
+	}))()
+
+	readOutput := func(t *testing.T, args ...string) (models.OutputData, string) {
+		outputPath := filepath.Join(t.TempDir(), "apps.json")
+		args = append(args, "-repos-file", reposFile, "-enrichers", "github,gitlab", "-output", outputPath, "-summary", filepath.Join(t.TempDir(), "summary.json"))
+		if err := run(args); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		data, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to read output: %v", err)
+		}
+		var output models.OutputData
+		if err := json.Unmarshal(data, &output); err != nil {
+			t.Fatalf("Output is not valid JSON: %v", err)
+		}
+		return output, string(data)
+	}
+
+	t.Run("explain", func(t *testing.T) {
+		output, _ := readOutput(t, "-explain")
+
+		want := map[string][]models.DebugStep{
+			"github.com/cli/cli": {
+				{Stage: "source", Matched: true, Note: "detected github repository"},
+				{Stage: "github", Matched: true, Status: http.StatusOK, ReleasesAdded: 1},
+				{Stage: "gitlab", Matched: false},
+			},
+			"gitlab.com/gnome/loupe": {
+				{Stage: "source", Matched: true, Note: "detected gitlab repository"},
+				{Stage: "github", Matched: false},
+				{Stage: "gitlab", Matched: true, Status: http.StatusOK, ReleasesAdded: 1},
+			},
+		}
+		if len(output.Apps) != len(want) {
+			t.Fatalf("Expected %d apps, got %d", len(want), len(output.Apps))
+		}
+		for _, app := range output.Apps {
+			if app.Debug == nil {
+				t.Errorf("Expected a debug trace for %s", app.ID)
+				continue
+			}
+			var got []models.DebugStep
+			for _, step := range app.Debug.Steps {
+				if step.Stage != "source" {
+					step.Note = "" // Enricher notes vary with upstream details
+				}
+				got = append(got, step)
+			}
+			if !reflect.DeepEqual(got, want[app.ID]) {
+				t.Errorf("Expected %s steps %+v, got %+v", app.ID, want[app.ID], got)
+			}
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		output, raw := readOutput(t)
+		for _, app := range output.Apps {
+			if app.Debug != nil {
+				t.Errorf("Expected no debug trace for %s without -explain", app.ID)
+			}
+		}
+		if strings.Contains(raw, `"debug"`) {
+			t.Error("Expected no debug field in the output without -explain")
+		}
+	})
+}
+
+func TestRunCountOnlyWritesNothing(t *testing.T) {
+	dir := t.TempDir()
+	reposFile := filepath.Join(dir, "repos.txt")
+	if err := os.WriteFile(reposFile, nil, 0644); err != nil {
+		t.Fatalf("Failed to write repos file: %v", err)
+	}
+	outDir := filepath.Join(dir, "out")
+	if err := os.Mkdir(outDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer httpx.SetBaseTransport(httpxtest.Status(http.StatusNotFound))()
+
+	err := run([]string{
+		"-count-only", "-quiet",
+		"-repos-file", reposFile,
+		"-output", filepath.Join(outDir, "apps.json"),
+		"-summary", filepath.Join(outDir, "summary.json"),
+		"-search-index", filepath.Join(outDir, "search.json"),
+		"-dashboard-index", filepath.Join(outDir, "index.json"),
+		"-archive-dir", filepath.Join(outDir, "archive"),
+		"-duplicates-report", filepath.Join(outDir, "duplicates.json"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		t.Errorf("Expected no files written with -count-only, found %s", entry.Name())
+	}
+}
+
+func TestRunPreviewPrintsDiffWithoutWriting(t *testing.T) {
+	dir := t.TempDir()
+	reposFile := filepath.Join(dir, "repos.txt")
+	if err := os.WriteFile(reposFile, nil, 0644); err != nil {
+		t.Fatalf("Failed to write repos file: %v", err)
+	}
+	outDir := filepath.Join(dir, "out")
+	if err := os.Mkdir(outDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	outputPath := filepath.Join(outDir, "apps.json")
+	existing := `{"apps": [{"id": "org.example.Gone", "name": "Gone", "currentReleaseVersion": "1.0"}]}`
+	if err := os.WriteFile(outputPath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer httpx.SetBaseTransport(httpxtest.Status(http.StatusNotFound))()
+
+	var printed strings.Builder
+	defer func(w io.Writer) { stdout = w }(stdout)
+	stdout = &printed
+
+	err := run([]string{
+		"-preview",
+		"-repos-file", reposFile,
+		"-output", outputPath,
+		"-diff-format", "text",
+		"-diff-output", filepath.Join(outDir, "diff.txt"),
+		"-summary", filepath.Join(outDir, "summary.json"),
+	})
+	if err != nil {
+		t.Fatalf("Expected preview to succeed, got %v", err)
+	}
+
+	if want := "0 added, 0 updated, 1 removed\n- org.example.Gone 1.0\n"; printed.String() != want {
+		t.Errorf("Expected preview %q, got %q", want, printed.String())
+	}
+	if data, _ := os.ReadFile(outputPath); string(data) != existing {
+		t.Errorf("Expected the existing output untouched, got %s", data)
+	}
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected no files written besides the existing output, found %d entries", len(entries))
+	}
+}
+
+func TestRunDisabledSourcesDontRun(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	dir := t.TempDir()
+	reposFile := filepath.Join(dir, "repos.txt")
+	if err := os.WriteFile(reposFile, []byte("github.com/cli/cli\ngitlab.com/gnome/loupe\n"), 0644); err != nil {
+		t.Fatalf("Failed to write repos file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		allowed string // Every request URL must contain this
+	}{
+		{name: "only LTS", args: []string{"-sources", "lts", "-enrichers", "github"}, allowed: "bluefin-lts"},
+		{name: "only GitLab enrichment", args: []string{"-repos-file", reposFile, "-enrichers", "gitlab"}, allowed: "gitlab.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &httpxtest.Transport{}
+			defer httpx.SetBaseTransport(transport)()
+
+			args := append(tt.args, "-output", filepath.Join(t.TempDir(), "apps.json"), "-summary", filepath.Join(t.TempDir(), "summary.json"))
+			if err := run(args); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(transport.URLs()) == 0 {
+				t.Fatal("Expected the enabled source to run")
+			}
+			for _, url := range transport.URLs() {
+				if !strings.Contains(url, tt.allowed) {
+					t.Errorf("Expected only %s requests, got %s", tt.allowed, url)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintSummaryQuiet(t *testing.T) {
+	summary := runSummary{AppsTotal: 3, FlatpakCount: 2, OSCount: 1, AppsWithChangelog: 2, TotalReleases: 7, ErrorCount: 1}
+
+	var buf strings.Builder
+	printSummary(&buf, summary, true)
+	want := "apps=3 flatpak=2 homebrew=0 os=1 changelogs=2 releases=7 errors=1\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+
+	buf.Reset()
+	printSummary(&buf, summary, false)
+	var decoded runSummary
+	if err := json.Unmarshal([]byte(buf.String()), &decoded); err != nil || decoded.AppsTotal != 3 {
+		t.Errorf("Expected JSON summary, got %q (%v)", buf.String(), err)
+	}
+}
+
+func TestNormalizeReleaseDatesSkipsDrafts(t *testing.T) {
+	published := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	apps := normalizeReleaseDates([]models.App{{
+		ID: "bluefin-os-stable",
+		Releases: []models.Release{
+			{Version: "stable-20260303", Date: published.AddDate(0, 0, 2), Draft: true},
+			{Version: "stable-20260301", Date: published},
+		},
+	}})
+
+	if apps[0].Version != "stable-20260301" || apps[0].ReleaseDate != published.Format(time.RFC3339) {
+		t.Errorf("Expected the published release as latest, got %s at %s", apps[0].Version, apps[0].ReleaseDate)
+	}
+}
+
+func TestNormalizeReleaseDatesSkipsZeroDates(t *testing.T) {
+	apps := normalizeReleaseDates([]models.App{
+		{ID: "org.example.Dated", ReleaseDate: "2026-02-01T00:00:00Z", Releases: []models.Release{{Version: "1.1.0"}}},
+		{ID: "org.example.Undated", Releases: []models.Release{{Version: "2.0.0"}}},
+	})
+
+	if apps[0].ReleaseDate != "2026-02-01T00:00:00Z" || apps[0].UpdatedAt != "" {
+		t.Errorf("Expected the existing dates to be kept, got %q and %q", apps[0].ReleaseDate, apps[0].UpdatedAt)
+	}
+	if apps[1].ReleaseDate != "" || apps[1].UpdatedAt != "" {
+		t.Errorf("Expected no dates for an undated release, got %q and %q", apps[1].ReleaseDate, apps[1].UpdatedAt)
+	}
+	if apps[1].Version != "2.0.0" {
+		t.Errorf("Expected version 2.0.0, got %s", apps[1].Version)
+	}
+}
+
+func TestApplyFallbackIcons(t *testing.T) {
+	apps := applyFallbackIcons([]models.App{
+		{ID: "org.example.NoIcon", PackageType: "flatpak"},
+		{ID: "org.example.HasIcon", PackageType: "flatpak", Icon: "https://dl.flathub.org/icon.png"},
+		{ID: "homebrew-foo", PackageType: "homebrew"},
+		{ID: "bluefin-os-stable", PackageType: "os"},
+	}, "https://example.org/fallback.svg", defaultHomebrewIcon)
+
+	want := map[string]string{
+		"org.example.NoIcon":  "https://example.org/fallback.svg",
+		"org.example.HasIcon": "https://dl.flathub.org/icon.png",
+		"homebrew-foo":        defaultHomebrewIcon,
+		"bluefin-os-stable":   "https://example.org/fallback.svg",
+	}
+	for _, app := range apps {
+		if app.Icon != want[app.ID] {
+			t.Errorf("Expected %s icon %q, got %q", app.ID, want[app.ID], app.Icon)
+		}
+	}
+
+	// Without a Homebrew icon, packages use the general fallback
+	apps = applyFallbackIcons([]models.App{{ID: "homebrew-bar", PackageType: "homebrew"}}, "https://example.org/fallback.svg", "")
+	if apps[0].Icon != "https://example.org/fallback.svg" {
+		t.Errorf("Expected general fallback, got %q", apps[0].Icon)
+	}
+}
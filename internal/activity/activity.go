@@ -0,0 +1,53 @@
+// Package activity ranks apps by how often they have released recently
+package activity
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// Window is how far back Top looks when counting releases
+const Window = 90 * 24 * time.Hour
+
+// Top keeps the n apps with the most releases in the window before now,
+// most active first. Ties go to the app with the newest release.
+func Top(apps []models.App, n int, now time.Time) []models.App {
+	if n <= 0 || n >= len(apps) {
+		return apps
+	}
+
+	since := now.Add(-Window)
+	recent := make([]int, len(apps))
+	newest := make([]time.Time, len(apps))
+	for i, app := range apps {
+		for _, release := range app.Releases {
+			if release.Date.After(since) && !release.Date.After(now) {
+				recent[i]++
+			}
+			if release.Date.After(newest[i]) {
+				newest[i] = release.Date
+			}
+		}
+	}
+
+	order := make([]int, len(apps))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		if recent[order[a]] != recent[order[b]] {
+			return recent[order[a]] > recent[order[b]]
+		}
+		return newest[order[a]].After(newest[order[b]])
+	})
+
+	kept := make([]models.App, 0, n)
+	for _, i := range order[:n] {
+		log.Printf("  🏆 %s: %d release(s) in the last %d days", apps[i].ID, recent[i], int(Window.Hours()/24))
+		kept = append(kept, apps[i])
+	}
+	return kept
+}
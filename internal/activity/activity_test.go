@@ -0,0 +1,51 @@
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestTop(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	releasesAt := func(daysAgo ...int) []models.Release {
+		var releases []models.Release
+		for _, d := range daysAgo {
+			releases = append(releases, models.Release{Date: now.AddDate(0, 0, -d)})
+		}
+		return releases
+	}
+
+	apps := []models.App{
+		{ID: "quiet", Releases: releasesAt(200, 300)},
+		{ID: "busy", Releases: releasesAt(1, 10, 20, 30)},
+		{ID: "steady-old", Releases: releasesAt(40, 80)},
+		{ID: "steady-new", Releases: releasesAt(5, 60, 400)},
+		{ID: "none"},
+	}
+
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{n: 0, want: "quiet,busy,steady-old,steady-new,none"},
+		{n: 1, want: "busy"},
+		{n: 3, want: "busy,steady-new,steady-old"},
+		{n: 10, want: "quiet,busy,steady-old,steady-new,none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("n=%d", tt.n), func(t *testing.T) {
+			var got []string
+			for _, app := range Top(append([]models.App(nil), apps...), tt.n, now) {
+				got = append(got, app.ID)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("Expected %s, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Package archive keeps timestamped snapshots of each run's output for
+// historical analysis
+package archive
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// timestampLayout is the snapshot name suffix, e.g. "20260203-150405"
+const timestampLayout = "20060102-150405"
+
+// Name returns the snapshot file name for an output file written at runTime:
+// "apps.json" becomes "apps-20260203-150405.json" (UTC)
+func Name(outputPath string, runTime time.Time) string {
+	base := strings.TrimSuffix(filepath.Base(outputPath), ".json")
+	return fmt.Sprintf("%s-%s.json", base, runTime.UTC().Format(timestampLayout))
+}
+
+// Write saves a snapshot of output into dir, creating it if needed, and
+// returns the snapshot's path. It encodes output itself, so it doesn't
+// depend on how (or whether) the canonical file was written.
+func Write(output *models.OutputData, dir, outputPath string, runTime time.Time, opts models.JSONOptions) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create archive dir: %w", err)
+	}
+	path := filepath.Join(dir, Name(outputPath, runTime))
+	// The snapshot is a single pretty-printed file
+	opts.MinifiedPath = ""
+	if err := output.WriteJSONWithOptions(path, opts); err != nil {
+		return "", fmt.Errorf("write archive: %w", err)
+	}
+	return path, nil
+}
+
+// Prune removes snapshots of outputPath in dir taken more than retention
+// before now, going by the timestamp in their names. Other files are left
+// alone. Returns how many snapshots were removed.
+func Prune(dir, outputPath string, retention time.Duration, now time.Time) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("read archive dir: %w", err)
+	}
+
+	prefix := strings.TrimSuffix(filepath.Base(outputPath), ".json") + "-"
+	cutoff := now.Add(-retention)
+	removed := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		taken, err := time.Parse(timestampLayout, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".json"))
+		if err != nil || !taken.Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return removed, fmt.Errorf("remove %s: %w", name, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
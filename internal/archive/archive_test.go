@@ -0,0 +1,75 @@
+package archive
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestWriteSnapshotName(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archives")
+	runTime := time.Date(2026, 2, 3, 15, 4, 5, 0, time.FixedZone("CET", 3600))
+	output := &models.OutputData{Apps: []models.App{{ID: "org.gnome.Loupe"}}}
+
+	path, err := Write(output, dir, "src/data/apps.json", runTime, models.JSONOptions{MinifiedPath: "apps.min.json"})
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	if !regexp.MustCompile(`^apps-\d{8}-\d{6}\.json$`).MatchString(filepath.Base(path)) {
+		t.Errorf("Expected apps-YYYYMMDD-HHMMSS.json, got %s", filepath.Base(path))
+	}
+	if want := filepath.Join(dir, "apps-20260203-140405.json"); path != want {
+		t.Errorf("Expected %s (UTC), got %s", want, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read snapshot: %v", err)
+	}
+	var snapshot models.OutputData
+	if err := json.Unmarshal(data, &snapshot); err != nil || len(snapshot.Apps) != 1 {
+		t.Errorf("Expected the output in the snapshot, got %s (err %v)", data, err)
+	}
+	if _, err := os.Stat("apps.min.json"); !os.IsNotExist(err) {
+		t.Error("Expected no minified copy next to the snapshot")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC)
+	names := []string{
+		"apps-20260101-000000.json", // Older than retention
+		"apps-20260209-120000.json", // Recent
+		"apps.json",                 // Not a snapshot
+		"other-20260101-000000.json",
+		"apps-notes.json",
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := Prune(dir, "apps.json", 7*24*time.Hour, now)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 snapshot removed, got %d", removed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "apps-20260101-000000.json")); !os.IsNotExist(err) {
+		t.Error("Expected the old snapshot to be removed")
+	}
+	for _, name := range names[1:] {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be kept: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Package assets classifies the files attached to a release
+package assets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// Asset is a downloadable file attached to a release
+type Asset struct {
+	Name string
+	URL  string
+	Size int64 // Bytes; zero when unknown
+}
+
+var (
+	// signatureSuffixes mark detached signatures and sigstore bundles or
+	// in-toto attestations (both signed statements about the artifacts)
+	signatureSuffixes = []string{".sig", ".asc", ".sigstore", ".sigstore.json", ".intoto.jsonl"}
+
+	// checksumSuffixes mark per-file digests
+	checksumSuffixes = []string{".sha256", ".sha256sum", ".sha512", ".sha512sum"}
+
+	// checksumNames mark digest lists covering every asset
+	checksumNames = []string{"sha256sums", "sha512sums", "checksums"}
+)
+
+// IsSignature reports whether an asset name looks like a signature or attestation
+func IsSignature(name string) bool {
+	return hasAnySuffix(strings.ToLower(name), signatureSuffixes)
+}
+
+// IsChecksum reports whether an asset name looks like a checksum file.
+// A signed checksum list ("SHA256SUMS.sig") is a signature, not a checksum.
+func IsChecksum(name string) bool {
+	lower := strings.ToLower(name)
+	if IsSignature(lower) {
+		return false
+	}
+	if hasAnySuffix(lower, checksumSuffixes) {
+		return true
+	}
+	base := strings.TrimSuffix(lower, ".txt")
+	for _, n := range checksumNames {
+		if base == n || strings.HasSuffix(base, "_"+n) || strings.HasSuffix(base, "-"+n) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkVerification records which of a release's assets are signatures and
+// checksums, leaving releases without such assets untouched
+func MarkVerification(release *models.Release, list []Asset) {
+	for _, asset := range list {
+		switch {
+		case IsSignature(asset.Name):
+			release.HasSignature = true
+			release.SignatureURLs = append(release.SignatureURLs, asset.URL)
+		case IsChecksum(asset.Name):
+			release.HasChecksums = true
+			release.ChecksumURLs = append(release.ChecksumURLs, asset.URL)
+		}
+	}
+}
+
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatSize renders a byte count with binary units ("512 B", "1.5 GiB"),
+// or "" for unknown (zero or negative) sizes
+func FormatSize(bytes int64) string {
+	if bytes <= 0 {
+		return ""
+	}
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit && exp < 4; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTP"[exp])
+}
@@ -0,0 +1,78 @@
+package assets
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestMarkVerification(t *testing.T) {
+	const base = "https://github.com/cli/cli/releases/download/v2.70.0/"
+	list := []Asset{
+		{Name: "gh_2.70.0_linux_amd64.tar.gz", URL: base + "gh_2.70.0_linux_amd64.tar.gz"},
+		{Name: "gh_2.70.0_checksums.txt", URL: base + "gh_2.70.0_checksums.txt"},
+		{Name: "gh_2.70.0_checksums.txt.sig", URL: base + "gh_2.70.0_checksums.txt.sig"},
+		{Name: "gh_2.70.0_linux_amd64.tar.gz.sha256", URL: base + "gh_2.70.0_linux_amd64.tar.gz.sha256"},
+		{Name: "multiple.intoto.jsonl", URL: base + "multiple.intoto.jsonl"},
+	}
+
+	var release models.Release
+	MarkVerification(&release, list)
+
+	if !release.HasSignature || !release.HasChecksums {
+		t.Errorf("Expected signature and checksums, got signature=%v checksums=%v", release.HasSignature, release.HasChecksums)
+	}
+	wantSignatures := []string{base + "gh_2.70.0_checksums.txt.sig", base + "multiple.intoto.jsonl"}
+	if !reflect.DeepEqual(release.SignatureURLs, wantSignatures) {
+		t.Errorf("Expected signature URLs %v, got %v", wantSignatures, release.SignatureURLs)
+	}
+	wantChecksums := []string{base + "gh_2.70.0_checksums.txt", base + "gh_2.70.0_linux_amd64.tar.gz.sha256"}
+	if !reflect.DeepEqual(release.ChecksumURLs, wantChecksums) {
+		t.Errorf("Expected checksum URLs %v, got %v", wantChecksums, release.ChecksumURLs)
+	}
+}
+
+func TestMarkVerificationWithoutSecurityAssets(t *testing.T) {
+	var release models.Release
+	MarkVerification(&release, []Asset{{Name: "app.flatpak", URL: "https://example.com/app.flatpak"}})
+	MarkVerification(&release, nil)
+
+	if release.HasSignature || release.HasChecksums || release.SignatureURLs != nil || release.ChecksumURLs != nil {
+		t.Errorf("Expected release without security assets to be untouched, got %+v", release)
+	}
+}
+
+func TestIsChecksum(t *testing.T) {
+	tests := map[string]bool{
+		"SHA256SUMS":             true,
+		"sha256sums.txt":         true,
+		"checksums.txt":          true,
+		"app-1.0-checksums.txt":  true,
+		"app.AppImage.sha512sum": true,
+		"SHA256SUMS.asc":         false,
+		"checksum-tool.tar.gz":   false,
+		"app.AppImage":           false,
+	}
+	for name, want := range tests {
+		if got := IsChecksum(name); got != want {
+			t.Errorf("IsChecksum(%q): expected %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		0:                             "",
+		512:                           "512 B",
+		1536:                          "1.5 KiB",
+		5 * 1024 * 1024:               "5.0 MiB",
+		2254857830:                    "2.1 GiB",
+		3 * 1024 * 1024 * 1024 * 1024: "3.0 TiB",
+	}
+	for bytes, want := range tests {
+		if got := FormatSize(bytes); got != want {
+			t.Errorf("FormatSize(%d): expected %q, got %q", bytes, want, got)
+		}
+	}
+}
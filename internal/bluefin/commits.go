@@ -0,0 +1,105 @@
+package bluefin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// maxOSCommits caps how many commits are listed per OS release
+const maxOSCommits = 50
+
+// githubCompare is the subset of the GitHub compare API response we use
+type githubCompare struct {
+	TotalCommits int `json:"total_commits"`
+	Commits      []struct {
+		SHA    string `json:"sha"`
+		Commit struct {
+			Message string `json:"message"`
+			Author  struct {
+				Name string `json:"name"`
+			} `json:"author"`
+		} `json:"commit"`
+		Author *GitHubUser `json:"author"` // Nil when the commit email isn't linked to an account
+	} `json:"commits"`
+}
+
+// attachCommits fills release.Commits from the compare range recorded for its
+// tag. Releases without a predecessor have no range and are left alone.
+func attachCommits(repo string, release *models.Release, ranges map[string]compareRange) {
+	r, ok := ranges[release.Version]
+	if !ok {
+		return
+	}
+
+	commits, omitted, err := fetchCompareCommits(repo, r)
+	if err != nil {
+		log.Printf("⚠️  Failed to fetch commits for %s %s: %v", repo, release.Version, err)
+		return
+	}
+	release.Commits = commits
+	release.CommitsOmitted = omitted
+}
+
+// fetchCompareCommits lists the commits in a range newest first, keeping at
+// most maxOSCommits. It also returns how many commits were left out.
+func fetchCompareCommits(repo string, r compareRange) ([]models.CommitSummary, int, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/compare/%s...%s", BluefinOSOwner, repo, r.Base, r.Head)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("create request: %w", err)
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := githubRetryClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("fetch compare: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read response body: %w", err)
+	}
+
+	var compare githubCompare
+	if err := json.Unmarshal(body, &compare); err != nil {
+		return nil, 0, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	// The API lists commits oldest first
+	var commits []models.CommitSummary
+	for i := len(compare.Commits) - 1; i >= 0 && len(commits) < maxOSCommits; i-- {
+		c := compare.Commits[i]
+		author := c.Author.GetLogin()
+		if author == "" {
+			author = c.Commit.Author.Name
+		}
+		commits = append(commits, models.CommitSummary{
+			SHA:     c.SHA,
+			Message: strings.TrimSpace(strings.SplitN(c.Commit.Message, "\n", 2)[0]),
+			Author:  author,
+		})
+	}
+
+	// total_commits counts the whole range, even past what the API returned
+	total := compare.TotalCommits
+	if total < len(compare.Commits) {
+		total = len(compare.Commits)
+	}
+	return commits, total - len(commits), nil
+}
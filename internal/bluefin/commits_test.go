@@ -0,0 +1,78 @@
+package bluefin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// compareBody is a compare response with n commits (oldest first) claiming
+// total commits in the range
+func compareBody(n, total int) string {
+	var commits []string
+	for i := 1; i <= n; i++ {
+		author := fmt.Sprintf(`{"login": "dev%d"}`, i)
+		if i == n {
+			author = "null" // Unlinked commit email
+		}
+		commits = append(commits, fmt.Sprintf(`{"sha": "sha%d", "commit": {"message": "commit %d\n\nDetails", "author": {"name": "Dev %d"}}, "author": %s}`, i, i, i, author))
+	}
+	return fmt.Sprintf(`{"total_commits": %d, "commits": [%s]}`, total, strings.Join(commits, ","))
+}
+
+func TestAttachCommits(t *testing.T) {
+	ranges := map[string]compareRange{"stable-20260301": {Base: "aaa1111", Head: "bbb2222"}}
+
+	t.Run("summaries newest first", func(t *testing.T) {
+		transport := httpxtest.Bodies(map[string]string{"": compareBody(3, 3)})
+		defer httpx.SetBaseTransport(transport)()
+
+		release := models.Release{Version: "stable-20260301"}
+		attachCommits(BluefinOSRepo, &release, ranges)
+
+		if paths := transport.Paths(); len(paths) != 1 || paths[0] != "/repos/ublue-os/bluefin/compare/aaa1111...bbb2222" {
+			t.Errorf("Unexpected compare paths %q", paths)
+		}
+		want := []models.CommitSummary{
+			{SHA: "sha3", Message: "commit 3", Author: "Dev 3"},
+			{SHA: "sha2", Message: "commit 2", Author: "dev2"},
+			{SHA: "sha1", Message: "commit 1", Author: "dev1"},
+		}
+		if fmt.Sprint(release.Commits) != fmt.Sprint(want) {
+			t.Errorf("Expected %v, got %v", want, release.Commits)
+		}
+		if release.CommitsOmitted != 0 {
+			t.Errorf("Expected no omitted commits, got %d", release.CommitsOmitted)
+		}
+	})
+
+	t.Run("large range truncated", func(t *testing.T) {
+		defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{"": compareBody(250, 400)}))()
+
+		release := models.Release{Version: "stable-20260301"}
+		attachCommits(BluefinOSRepo, &release, ranges)
+
+		if len(release.Commits) != maxOSCommits || release.Commits[0].SHA != "sha250" {
+			t.Errorf("Expected the newest %d commits, got %d starting at %v", maxOSCommits, len(release.Commits), release.Commits[0])
+		}
+		if release.CommitsOmitted != 400-maxOSCommits {
+			t.Errorf("Expected %d omitted commits, got %d", 400-maxOSCommits, release.CommitsOmitted)
+		}
+	})
+
+	t.Run("first release has no predecessor", func(t *testing.T) {
+		transport := httpxtest.Bodies(map[string]string{"": compareBody(1, 1)})
+		defer httpx.SetBaseTransport(transport)()
+
+		release := models.Release{Version: "stable-20260201"}
+		attachCommits(BluefinOSRepo, &release, ranges)
+
+		if release.Commits != nil || len(transport.URLs()) != 0 {
+			t.Errorf("Expected no compare request or commits, got %v (requests %q)", release.Commits, transport.URLs())
+		}
+	})
+}
@@ -7,6 +7,9 @@ import (
 	"net/http"
 	"os"
 	"regexp"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/httpx"
 )
 
 const (
@@ -18,8 +21,22 @@ const (
 
 // AppSetInfo contains app ID and its app set classification
 type AppSetInfo struct {
-	AppID  string
-	AppSet string // "core" or "dx"
+	AppID   string   `json:"appId"`
+	AppSet  string   `json:"appSet"`            // "core" or "dx"; core when listed in both
+	AppSets []string `json:"appSets,omitempty"` // Every set the app is listed in
+}
+
+// InSet reports whether the app is listed in appSet
+func (info AppSetInfo) InSet(appSet string) bool {
+	if len(info.AppSets) == 0 {
+		return info.AppSet == appSet
+	}
+	for _, set := range info.AppSets {
+		if set == appSet {
+			return true
+		}
+	}
+	return false
 }
 
 // FetchFlatpakList fetches the list of Flatpak app IDs that Bluefin ships with
@@ -47,21 +64,27 @@ func FetchFlatpakListWithAppSets() ([]AppSetInfo, error) {
 	log.Println("Fetching Bluefin Flatpak list from Brewfiles...")
 
 	var allAppSetInfos []AppSetInfo
-
-	// Map of Brewfiles to their app set classification
-	brewfiles := map[string]string{
-		"system_files/bluefin/usr/share/ublue-os/homebrew/system-flatpaks.Brewfile":    "core",
-		"system_files/bluefin/usr/share/ublue-os/homebrew/system-dx-flatpaks.Brewfile": "dx",
+	var fetchErr error
+	fetched := 0
+
+	// Brewfiles and their app set classification, core first so the list
+	// keeps a stable order
+	brewfiles := []struct{ path, appSet string }{
+		{"system_files/bluefin/usr/share/ublue-os/homebrew/system-flatpaks.Brewfile", "core"},
+		{"system_files/bluefin/usr/share/ublue-os/homebrew/system-dx-flatpaks.Brewfile", "dx"},
 	}
 
-	for brewfile, appSet := range brewfiles {
+	for _, entry := range brewfiles {
+		brewfile, appSet := entry.path, entry.appSet
 		log.Printf("  Fetching %s (%s apps)...", brewfile, appSet)
 
 		content, err := fetchRawFile(BluefinCommonOwner, BluefinCommonRepo, BluefinCommonBranch, brewfile)
 		if err != nil {
 			log.Printf("⚠️  Failed to fetch %s: %v", brewfile, err)
+			fetchErr = err
 			continue // Skip this file, but continue with others
 		}
+		fetched++
 
 		appIDs := parseFlatpakBrewfile(content)
 		log.Printf("  Found %d Flatpak app IDs in %s", len(appIDs), brewfile)
@@ -74,6 +97,13 @@ func FetchFlatpakListWithAppSets() ([]AppSetInfo, error) {
 		}
 	}
 
+	// Without any Brewfile there is no curated list to build from
+	if fetched == 0 && fetchErr != nil {
+		return nil, fmt.Errorf("no Brewfiles could be fetched: %w", fetchErr)
+	}
+
+	allAppSetInfos = dedupeAppSets(allAppSetInfos)
+
 	// Count by app set
 	coreCount := 0
 	dxCount := 0
@@ -89,6 +119,44 @@ func FetchFlatpakListWithAppSets() ([]AppSetInfo, error) {
 	return allAppSetInfos, nil
 }
 
+// dedupeAppSets keeps one entry per app ID, preferring the core app set when
+// an app is listed in both while recording both in AppSets, and warns about
+// every duplicate so curation mistakes in the Brewfiles get noticed
+func dedupeAppSets(infos []AppSetInfo) []AppSetInfo {
+	index := make(map[string]int, len(infos))
+	sets := make(map[string][]string)
+	var deduped []AppSetInfo
+	var duplicates []string
+
+	for _, info := range infos {
+		sets[info.AppID] = append(sets[info.AppID], info.AppSet)
+		i, seen := index[info.AppID]
+		if !seen {
+			index[info.AppID] = len(deduped)
+			deduped = append(deduped, info)
+			continue
+		}
+		if len(sets[info.AppID]) == 2 {
+			duplicates = append(duplicates, info.AppID)
+		}
+		if info.AppSet == "core" {
+			deduped[i].AppSet = "core"
+		}
+	}
+
+	for i := range deduped {
+		deduped[i].AppSets = deduplicate(sets[deduped[i].AppID])
+	}
+
+	if len(duplicates) > 0 {
+		log.Printf("⚠️  Found %d duplicate app ID(s) in the Brewfiles:", len(duplicates))
+		for _, appID := range duplicates {
+			log.Printf("  %s listed %d times (%s), keeping %s", appID, len(sets[appID]), strings.Join(sets[appID], ", "), deduped[index[appID]].AppSet)
+		}
+	}
+	return deduped
+}
+
 // fetchRawFile fetches a raw file from GitHub using raw.githubusercontent.com
 // Supports optional GITHUB_TOKEN for authentication (helps with rate limits)
 func fetchRawFile(owner, repo, branch, path string) ([]byte, error) {
@@ -104,8 +172,7 @@ func fetchRawFile(owner, repo, branch, path string) ([]byte, error) {
 		req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := brewfileClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetch file: %w", err)
 	}
@@ -116,7 +183,7 @@ func fetchRawFile(owner, repo, branch, path string) ([]byte, error) {
 	}
 
 	if resp.StatusCode == http.StatusForbidden {
-		return nil, fmt.Errorf("rate limit exceeded (403) - consider setting GITHUB_TOKEN environment variable")
+		return nil, fmt.Errorf("%w (403) - consider setting GITHUB_TOKEN environment variable", httpx.ErrRateLimited)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -0,0 +1,36 @@
+package bluefin
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDedupeAppSets(t *testing.T) {
+	infos := []AppSetInfo{
+		{AppID: "com.visualstudio.code", AppSet: "dx"},
+		{AppID: "org.gnome.Loupe", AppSet: "core"},
+		{AppID: "org.gnome.Loupe", AppSet: "core"}, // Twice within core
+		{AppID: "io.podman_desktop.PodmanDesktop", AppSet: "dx"},
+		{AppID: "com.visualstudio.code", AppSet: "core"},         // In both sets
+		{AppID: "io.podman_desktop.PodmanDesktop", AppSet: "dx"}, // Twice within dx
+	}
+
+	got := dedupeAppSets(infos)
+
+	want := []AppSetInfo{
+		{AppID: "com.visualstudio.code", AppSet: "core", AppSets: []string{"dx", "core"}},
+		{AppID: "org.gnome.Loupe", AppSet: "core", AppSets: []string{"core"}},
+		{AppID: "io.podman_desktop.PodmanDesktop", AppSet: "dx", AppSets: []string{"dx"}},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// An app in both sets still belongs to each
+	if !got[0].InSet("core") || !got[0].InSet("dx") {
+		t.Errorf("Expected %s in both sets, got %v", got[0].AppID, got[0].AppSets)
+	}
+	if got[1].InSet("dx") {
+		t.Errorf("Expected %s only in core", got[1].AppID)
+	}
+}
@@ -0,0 +1,108 @@
+package bluefin
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// tableSeparatorRe matches the |---|---| line under a markdown table header
+	tableSeparatorRe = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?$`)
+	// headingRe matches a markdown ATX heading and captures its level
+	headingRe = regexp.MustCompile(`^(#{1,6})\s`)
+)
+
+// osHighlights strips the package version tables from an OS release body,
+// keeping the prose highlights (and other tables such as commits). The
+// package versions are already structured in OSInfo, so repeating the raw
+// tables in the rendered notes is just clutter. Headings left without any
+// content are dropped too.
+func osHighlights(body string) string {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+
+	var kept []string
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "|") && i+1 < len(lines) && tableSeparatorRe.MatchString(strings.TrimSpace(lines[i+1])) {
+			end := i + 2
+			for end < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[end]), "|") {
+				end++
+			}
+			if isPackageTable(line) {
+				i = end - 1
+				continue
+			}
+			kept = append(kept, lines[i:end]...)
+			i = end - 1
+			continue
+		}
+		kept = append(kept, lines[i])
+	}
+
+	return strings.TrimSpace(collapseBlankLines(dropEmptySections(kept)))
+}
+
+// isPackageTable reports whether a table header row belongs to a package
+// version table ("| Name | Version |" or "| | Name | Previous | New |")
+func isPackageTable(header string) bool {
+	cells := make(map[string]bool)
+	for _, cell := range strings.Split(header, "|") {
+		cells[strings.ToLower(strings.TrimSpace(cell))] = true
+	}
+	return cells["name"] && (cells["version"] || cells["previous"] || cells["new"])
+}
+
+// dropEmptySections removes headings with nothing but blank lines before the
+// next heading of the same or higher level, repeating until nested empty
+// sections are gone
+func dropEmptySections(lines []string) []string {
+	for {
+		removed := false
+		var kept []string
+		for i, line := range lines {
+			match := headingRe.FindStringSubmatch(line)
+			if match != nil && sectionIsEmpty(lines[i+1:], len(match[1])) {
+				removed = true
+				continue
+			}
+			kept = append(kept, line)
+		}
+		lines = kept
+		if !removed {
+			return lines
+		}
+	}
+}
+
+// sectionIsEmpty reports whether the lines following a heading of the given
+// level hold no content before the section ends
+func sectionIsEmpty(rest []string, level int) bool {
+	for _, line := range rest {
+		if match := headingRe.FindStringSubmatch(line); match != nil {
+			return len(match[1]) <= level
+		}
+		if strings.TrimSpace(line) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// collapseBlankLines joins lines, squeezing runs of blank lines into one
+func collapseBlankLines(lines []string) string {
+	var b strings.Builder
+	blank := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			blank = true
+			continue
+		}
+		if blank && b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		blank = false
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package bluefin
+
+import (
+	"strings"
+	"testing"
+)
+
+const osReleaseBody = `This is an automatically generated changelog for release ` + "`stable-20260203`" + `.
+
+From previous ` + "`stable`" + ` version ` + "`stable-20260127`" + ` there have been the following changes. **One package per new version shown.**
+
+### Major packages
+| Name | Version |
+| --- | --- |
+| **Kernel** | 6.17.12-300 |
+| **Gnome** | 49.2-1 |
+| **Mesa** | 25.2.7-1 ➡️ 25.2.8-1 |
+
+### Major DX packages
+| Name | Version |
+| --- | --- |
+| **Docker** | 29.1.3 |
+
+### Highlights
+- GNOME 49.2 brings fixes for the file chooser
+
+### Commits
+| Hash | Subject |
+| --- | --- |
+| **[1a2b3c4](https://github.com/ublue-os/bluefin/commit/1a2b3c4)** | fix: bump mesa |
+
+### All Images
+| | Name | Previous | New |
+| --- | --- | --- | --- |
+| 🔄 | mesa-dri-drivers | 25.2.7-1 | 25.2.8-1 |
+
+### How to rebase
+Run ` + "`bootc switch ghcr.io/ublue-os/bluefin:stable`"
+
+func TestOSHighlights(t *testing.T) {
+	got := osHighlights(osReleaseBody)
+
+	for _, gone := range []string{"Major packages", "Major DX packages", "**Kernel**", "**Docker**", "All Images", "mesa-dri-drivers"} {
+		if strings.Contains(got, gone) {
+			t.Errorf("Expected %q to be removed, got:\n%s", gone, got)
+		}
+	}
+	for _, kept := range []string{"automatically generated changelog", "### Highlights", "GNOME 49.2 brings fixes", "### Commits", "fix: bump mesa", "### How to rebase", "bootc switch"} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("Expected %q to be kept, got:\n%s", kept, got)
+		}
+	}
+	if strings.Contains(got, "\n\n\n") {
+		t.Errorf("Expected blank lines to be collapsed, got:\n%s", got)
+	}
+
+	// The structured versions still come from the full body
+	info := parseOSInfo(GitHubRelease{TagName: "stable-20260203", Body: osReleaseBody})
+	if info.KernelVersion != "6.17.12-300" || info.MesaVersion != "25.2.8-1" {
+		t.Errorf("Expected package versions from the body, got kernel %q mesa %q", info.KernelVersion, info.MesaVersion)
+	}
+}
+
+func TestOSHighlightsKeepsPlainNotes(t *testing.T) {
+	body := "Just a short note.\n\n- one\n- two"
+	if got := osHighlights(body); got != body {
+		t.Errorf("Expected %q, got %q", body, got)
+	}
+}
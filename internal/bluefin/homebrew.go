@@ -11,22 +11,27 @@ import (
 	"sync"
 	"time"
 
+	"github.com/castrojo/bluefin-releases/internal/httpx"
 	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/skips"
 )
 
 // HomebrewFormula represents metadata from Homebrew API
 type HomebrewFormula struct {
-	Name       string   `json:"name"`
-	FullName   string   `json:"full_name"`
-	Tap        string   `json:"tap"`
-	Desc       string   `json:"desc"`
-	License    string   `json:"license"`
-	Homepage   string   `json:"homepage"`
-	Versions   Versions `json:"versions"`
-	URLs       URLs     `json:"urls"`
-	Deprecated bool     `json:"deprecated"`
-	Disabled   bool     `json:"disabled"`
-	Bottle     *Bottle  `json:"bottle,omitempty"`
+	Name              string   `json:"name"`
+	FullName          string   `json:"full_name"`
+	Tap               string   `json:"tap"`
+	Desc              string   `json:"desc"`
+	License           string   `json:"license"`
+	Homepage          string   `json:"homepage"`
+	Versions          Versions `json:"versions"`
+	URLs              URLs     `json:"urls"`
+	Deprecated        bool     `json:"deprecated"`
+	Disabled          bool     `json:"disabled"`
+	Bottle            *Bottle  `json:"bottle,omitempty"`
+	Dependencies      []string `json:"dependencies,omitempty"`       // Runtime dependencies
+	DeprecationReason string   `json:"deprecation_reason,omitempty"` // e.g. "repo_archived"
+	DisableReason     string   `json:"disable_reason,omitempty"`
 }
 
 type Versions struct {
@@ -64,20 +69,49 @@ func FetchHomebrewPackages() ([]models.App, error) {
 
 	log.Printf("Fetching metadata for %d Homebrew packages...", len(packageNames))
 
-	// Step 2: Fetch metadata for each package (with concurrency)
+	// Step 2: Resolve homebrew-core packages from the bulk formula list (one request)
 	apps := make([]models.App, 0, len(packageNames))
+	remaining := packageNames
+	bulk, err := fetchBulkFormulae()
+	if err != nil {
+		log.Printf("⚠️  Failed to fetch bulk formula.json, falling back to per-package requests: %v", err)
+	} else {
+		remaining = nil
+		resolved := 0
+		for _, name := range packageNames {
+			formula, ok := bulk[name]
+			if !ok {
+				remaining = append(remaining, name)
+				continue
+			}
+			resolved++
+			if app := appFromFormula(formula, name); app != nil {
+				apps = append(apps, *app)
+			}
+		}
+		log.Printf("📉 Resolved %d/%d Homebrew packages from bulk formula.json (1 request instead of %d)", resolved, len(packageNames), resolved)
+	}
+
+	// Step 3: Fetch metadata for the rest individually (with concurrency)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, 10) // Limit to 10 concurrent requests
+	semaphore := make(chan struct{}, max(currentOptions().HomebrewConcurrency, 1))
 
-	for _, pkgName := range packageNames {
+	for _, pkgName := range remaining {
 		wg.Add(1)
 		go func(name string) {
 			defer wg.Done()
 			semaphore <- struct{}{}        // Acquire semaphore
 			defer func() { <-semaphore }() // Release semaphore
 
-			app, err := fetchHomebrewPackageMetadata(name)
+			var app *models.App
+			var err error
+			if bulk != nil && !strings.Contains(name, "/") {
+				// Not in the bulk list, so it isn't in homebrew-core
+				app = createMinimalHomebrewApp(name)
+			} else {
+				app, err = fetchHomebrewPackageMetadata(name)
+			}
 			if err != nil {
 				log.Printf("⚠️  Failed to fetch metadata for %s: %v", name, err)
 				return
@@ -107,15 +141,95 @@ func fetchHomebrewPackageMetadata(packageName string) (*models.App, error) {
 		return createMinimalHomebrewApp(packageName), nil
 	}
 
-	// Fetch from Homebrew API
+	body, err := fetchFormulaJSON(packageName)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		// Package not found in homebrew-core, treat as custom tap
+		return createMinimalHomebrewApp(packageName), nil
+	}
+
+	var formula HomebrewFormula
+	if err := json.Unmarshal(body, &formula); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	return appFromFormula(formula, packageName), nil
+}
+
+// appFromFormula converts a formula to an App, returning nil for packages we
+// skip. Deprecated and disabled packages are skipped unless KeepDeprecated is set.
+func appFromFormula(formula HomebrewFormula, packageName string) *models.App {
+	if (formula.Deprecated || formula.Disabled) && !currentOptions().KeepDeprecated {
+		log.Printf("  Skipping deprecated/disabled package: %s", packageName)
+		skips.Record(packageName, "homebrew", skips.ReasonDeprecated, "")
+		return nil
+	}
+
+	// Check if Linux-compatible (has Linux bottles)
+	if !isLinuxCompatible(formula) {
+		log.Printf("  Skipping non-Linux package: %s", packageName)
+		skips.Record(packageName, "homebrew", skips.ReasonNonLinux, "")
+		return nil
+	}
+
+	// Convert to App model
+	app := convertHomebrewFormulaToApp(formula)
+	app.Deprecated = formula.Deprecated
+	app.Disabled = formula.Disabled
+	if formula.Disabled {
+		app.DeprecationReason = formula.DisableReason
+	} else {
+		app.DeprecationReason = formula.DeprecationReason
+	}
+	return app
+}
+
+// fetchBulkFormulae fetches every homebrew-core formula in one request, keyed by name
+func fetchBulkFormulae() (map[string]HomebrewFormula, error) {
+	body, err := fetchCachedJSON("https://formulae.brew.sh/api/formula.json", 60*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, fmt.Errorf("bulk formula list not found")
+	}
+
+	var formulae []HomebrewFormula
+	if err := json.Unmarshal(body, &formulae); err != nil {
+		return nil, fmt.Errorf("unmarshal bulk formulae: %w", err)
+	}
+
+	byName := make(map[string]HomebrewFormula, len(formulae))
+	for _, formula := range formulae {
+		byName[formula.Name] = formula
+	}
+	return byName, nil
+}
+
+// fetchFormulaJSON returns the raw formula JSON from the Homebrew API, serving
+// from the on-disk cache when possible. Returns nil without error on 404.
+func fetchFormulaJSON(packageName string) ([]byte, error) {
 	url := fmt.Sprintf("https://formulae.brew.sh/api/formula/%s.json", packageName)
+	return fetchCachedJSON(url, 10*time.Second)
+}
 
+// fetchCachedJSON GETs url with retries. Responses are cached by the shared
+// transport as the Homebrew source, so fresh ones are served without a request.
+// Returns nil without error on 404.
+func fetchCachedJSON(url string, timeout time.Duration) ([]byte, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpx.NewClientWith(httpx.ClientOptions{
+		Timeout:     timeout,
+		UserAgent:   httpx.DefaultUserAgent,
+		Retry:       &httpx.DefaultRetry,
+		CacheSource: httpx.SourceHomebrew,
+	})
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetch metadata: %w", err)
@@ -123,8 +237,7 @@ func fetchHomebrewPackageMetadata(packageName string) (*models.App, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		// Package not found in homebrew-core, treat as custom tap
-		return createMinimalHomebrewApp(packageName), nil
+		return nil, nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -136,25 +249,7 @@ func fetchHomebrewPackageMetadata(packageName string) (*models.App, error) {
 		return nil, fmt.Errorf("read response: %w", err)
 	}
 
-	var formula HomebrewFormula
-	if err := json.Unmarshal(body, &formula); err != nil {
-		return nil, fmt.Errorf("unmarshal response: %w", err)
-	}
-
-	// Skip deprecated or disabled packages
-	if formula.Deprecated || formula.Disabled {
-		log.Printf("  Skipping deprecated/disabled package: %s", packageName)
-		return nil, nil
-	}
-
-	// Check if Linux-compatible (has Linux bottles)
-	if !isLinuxCompatible(formula) {
-		log.Printf("  Skipping non-Linux package: %s", packageName)
-		return nil, nil
-	}
-
-	// Convert to App model
-	return convertHomebrewFormulaToApp(formula), nil
+	return body, nil
 }
 
 // isLinuxCompatible checks if a formula has Linux bottles
@@ -174,6 +269,10 @@ func isLinuxCompatible(formula HomebrewFormula) bool {
 	return false
 }
 
+// maxDependencies caps the dependencies listed per formula; a handful of
+// formulae pull in dozens, which the dashboard only needs a sample of
+const maxDependencies = 20
+
 // convertHomebrewFormulaToApp converts a Homebrew formula to our App model
 func convertHomebrewFormulaToApp(formula HomebrewFormula) *models.App {
 	// Clean up the name - remove "homebrew-" prefix if present
@@ -186,7 +285,7 @@ func convertHomebrewFormulaToApp(formula HomebrewFormula) *models.App {
 		Description: formula.Desc,
 		Version:     formula.Versions.Stable,
 		PackageType: "homebrew",
-		FetchedAt:   time.Now(),
+		FetchedAt:   time.Now().UTC(),
 		HomebrewInfo: &models.HomebrewInfo{
 			Formula:  formula.Name,
 			FullName: formula.FullName,
@@ -195,6 +294,12 @@ func convertHomebrewFormulaToApp(formula HomebrewFormula) *models.App {
 			Versions: []string{formula.Versions.Stable},
 		},
 	}
+	if deps := formula.Dependencies; len(deps) > 0 {
+		if len(deps) > maxDependencies {
+			deps = deps[:maxDependencies]
+		}
+		app.HomebrewInfo.Dependencies = append([]string(nil), deps...)
+	}
 
 	// Extract GitHub URL for source repo
 	if formula.URLs.Stable.URL != "" {
@@ -246,7 +351,7 @@ func createMinimalHomebrewApp(packageName string) *models.App {
 		Name:        cleanName,
 		Summary:     fmt.Sprintf("Homebrew package: %s", cleanName),
 		PackageType: "homebrew",
-		FetchedAt:   time.Now(),
+		FetchedAt:   time.Now().UTC(),
 		HomebrewInfo: &models.HomebrewInfo{
 			Formula: packageName,
 		},
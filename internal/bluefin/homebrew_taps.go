@@ -47,6 +47,13 @@ func FetchUblueOSTapPackages() ([]models.App, error) {
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
+	// Bound per-file fetching across all taps so we stay within GitHub rate limits
+	concurrency := currentOptions().TapConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+
 	// Define taps to fetch from
 	taps := []TapConfig{
 		{Owner: "ublue-os", Repo: "homebrew-tap", Experimental: false},
@@ -59,7 +66,7 @@ func FetchUblueOSTapPackages() ([]models.App, error) {
 			defer wg.Done()
 
 			// Fetch formulae from /Formula directory
-			formulae, err := fetchTapDirectory(t.Owner, t.Repo, "Formula", "formula", t.Experimental)
+			formulae, err := fetchTapDirectory(t.Owner, t.Repo, "Formula", "formula", t.Experimental, semaphore)
 			if err != nil {
 				log.Printf("⚠️  Failed to fetch formulae from %s/%s: %v", t.Owner, t.Repo, err)
 			} else {
@@ -70,7 +77,7 @@ func FetchUblueOSTapPackages() ([]models.App, error) {
 			}
 
 			// Fetch casks from /Casks directory
-			casks, err := fetchTapDirectory(t.Owner, t.Repo, "Casks", "cask", t.Experimental)
+			casks, err := fetchTapDirectory(t.Owner, t.Repo, "Casks", "cask", t.Experimental, semaphore)
 			if err != nil {
 				log.Printf("⚠️  Failed to fetch casks from %s/%s: %v", t.Owner, t.Repo, err)
 			} else {
@@ -88,8 +95,9 @@ func FetchUblueOSTapPackages() ([]models.App, error) {
 	return allApps, nil
 }
 
-// fetchTapDirectory lists .rb files from a GitHub repo directory and parses them
-func fetchTapDirectory(owner, repo, directory, pkgType string, experimental bool) ([]models.App, error) {
+// fetchTapDirectory lists .rb files from a GitHub repo directory and parses them.
+// Files are fetched in parallel, bounded by the shared semaphore.
+func fetchTapDirectory(owner, repo, directory, pkgType string, experimental bool, semaphore chan struct{}) ([]models.App, error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s", owner, repo, directory)
 
 	req, err := http.NewRequest("GET", url, nil)
@@ -103,8 +111,7 @@ func fetchTapDirectory(owner, repo, directory, pkgType string, experimental bool
 	}
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := tapClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetch directory: %w", err)
 	}
@@ -131,23 +138,42 @@ func fetchTapDirectory(owner, repo, directory, pkgType string, experimental bool
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
-	var apps []models.App
-	for _, file := range files {
+	// Keep results in directory order regardless of completion order
+	results := make([]*models.App, len(files))
+	var wg sync.WaitGroup
+
+	for i, file := range files {
 		if !strings.HasSuffix(file.Name, ".rb") {
 			continue
 		}
 
-		// Extract package name (remove .rb extension)
-		pkgName := strings.TrimSuffix(file.Name, ".rb")
+		wg.Add(1)
+		go func(i int, filename string) {
+			defer wg.Done()
+			semaphore <- struct{}{}        // Acquire semaphore
+			defer func() { <-semaphore }() // Release semaphore
 
-		// Parse the .rb file
-		app, err := parseTapPackage(owner, repo, directory, file.Name, pkgName, pkgType, experimental)
-		if err != nil {
-			log.Printf("⚠️  Failed to parse %s/%s: %v", directory, file.Name, err)
-			continue
-		}
+			// Extract package name (remove .rb extension)
+			pkgName := strings.TrimSuffix(filename, ".rb")
 
-		apps = append(apps, app)
+			// Parse the .rb file
+			app, err := parseTapPackage(owner, repo, directory, filename, pkgName, pkgType, experimental)
+			if err != nil {
+				log.Printf("⚠️  Failed to parse %s/%s: %v", directory, filename, err)
+				return
+			}
+
+			results[i] = &app
+		}(i, file.Name)
+	}
+
+	wg.Wait()
+
+	var apps []models.App
+	for _, app := range results {
+		if app != nil {
+			apps = append(apps, *app)
+		}
 	}
 
 	return apps, nil
@@ -158,7 +184,7 @@ func parseTapPackage(owner, repo, directory, filename, pkgName, pkgType string,
 	// Fetch raw .rb file
 	url := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/main/%s/%s", owner, repo, directory, filename)
 
-	resp, err := http.Get(url)
+	resp, err := tapClient.Get(url)
 	if err != nil {
 		return models.App{}, fmt.Errorf("fetch file: %w", err)
 	}
@@ -188,7 +214,7 @@ func parseTapPackage(owner, repo, directory, filename, pkgName, pkgType string,
 		Version:      metadata.Version,
 		PackageType:  "homebrew",
 		Experimental: experimental,
-		FetchedAt:    time.Now(),
+		FetchedAt:    time.Now().UTC(),
 		HomebrewInfo: &models.HomebrewInfo{
 			Formula:  fullName,
 			Tap:      tapName,
@@ -0,0 +1,48 @@
+package bluefin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+)
+
+func TestFetchUblueOSTapPackagesBoundsConcurrency(t *testing.T) {
+	// A Contents API listing per directory and slow .rb fetches
+	const files = 12
+	var items []string
+	for i := 0; i < files; i++ {
+		items = append(items, fmt.Sprintf(`{"name":"pkg%d.rb","type":"file"}`, i))
+	}
+	listing := httpxtest.Response{Body: "[" + strings.Join(items, ",") + "]"}
+	transport := &httpxtest.Transport{Responses: map[string]httpxtest.Response{
+		"/contents/Formula": listing,
+		"/contents/Casks":   listing,
+		".rb":               {Body: `desc "Test package"` + "\n" + `homepage "https://github.com/ublue-os/test"`, Delay: 10 * time.Millisecond},
+	}}
+	defer httpx.SetBaseTransport(transport)()
+
+	httpx.Configure(httpx.Limits{})
+	defer httpx.Configure(httpx.DefaultLimits)
+
+	Configure(Options{TapConcurrency: 3})
+	defer Configure(DefaultOptions())
+
+	apps, err := FetchUblueOSTapPackages()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := 2 * 2 * files // two taps, Formula and Casks each
+	if len(apps) != expected {
+		t.Errorf("Expected %d apps, got %d", expected, len(apps))
+	}
+	if got := transport.MaxInFlight(".rb"); got > 3 {
+		t.Errorf("Expected at most 3 concurrent fetches, got %d", got)
+	} else if got < 2 {
+		t.Errorf("Expected fetches to run in parallel, got max %d", got)
+	}
+}
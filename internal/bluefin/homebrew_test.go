@@ -0,0 +1,343 @@
+package bluefin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/cache"
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/skips"
+)
+
+const gitFormulaFixture = `{
+  "name": "git",
+  "full_name": "git",
+  "tap": "homebrew/core",
+  "desc": "Distributed revision control system",
+  "homepage": "https://git-scm.com",
+  "versions": {"stable": "2.53.0"},
+  "bottle": {"stable": {"files": {"x86_64_linux": {}}}}
+}`
+
+func TestFetchHomebrewPackageMetadata(t *testing.T) {
+	// Formula fixtures: "flaky" fails once with 503, unknown names 404
+	formulae := &httpxtest.Transport{
+		Responses: map[string]httpxtest.Response{
+			"/git.json":   {Body: gitFormulaFixture},
+			"/flaky.json": {Body: strings.Replace(gitFormulaFixture, `"git"`, `"flaky"`, 1)},
+		},
+		NotFound: &httpxtest.Response{Status: http.StatusNotFound, Body: `{"error":"not found"}`},
+	}
+	var failed atomic.Bool
+	defer httpx.SetBaseTransport(httpxtest.Func(func(req *http.Request) (*http.Response, error) {
+		resp, err := formulae.RoundTrip(req)
+		if strings.HasSuffix(req.URL.Path, "/flaky.json") && failed.CompareAndSwap(false, true) {
+			return httpxtest.Reply(req, http.StatusServiceUnavailable, ""), nil
+		}
+		return resp, err
+	}))()
+
+	Configure(Options{TapConcurrency: 1})
+	defer Configure(DefaultOptions())
+	httpx.SetResponseCache(cache.NewMemory(), time.Hour)
+	defer httpx.SetResponseCache(nil, 0)
+
+	t.Run("parses formula and caches it", func(t *testing.T) {
+		app, err := fetchHomebrewPackageMetadata("git")
+		if err != nil || app == nil {
+			t.Fatalf("Expected app, got %v (err %v)", app, err)
+		}
+		if app.Version != "2.53.0" {
+			t.Errorf("Expected version 2.53.0, got %s", app.Version)
+		}
+
+		before := len(formulae.URLs())
+		if _, err := fetchHomebrewPackageMetadata("git"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(formulae.URLs()) != before {
+			t.Error("Expected second fetch to be served from cache")
+		}
+	})
+
+	t.Run("404 creates minimal app without retrying", func(t *testing.T) {
+		before := len(formulae.URLs())
+		app, err := fetchHomebrewPackageMetadata("not-in-core")
+		if err != nil || app == nil {
+			t.Fatalf("Expected minimal app, got %v (err %v)", app, err)
+		}
+		if got := len(formulae.URLs()) - before; got != 1 {
+			t.Errorf("Expected 1 request for 404, got %d", got)
+		}
+	})
+
+	t.Run("transient failure is retried", func(t *testing.T) {
+		app, err := fetchHomebrewPackageMetadata("flaky")
+		if err != nil || app == nil {
+			t.Fatalf("Expected app after retry, got %v (err %v)", app, err)
+		}
+		if app.Name != "flaky" {
+			t.Errorf("Expected name flaky, got %s", app.Name)
+		}
+	})
+}
+
+// keyRecordingCache is a memory cache that records every key it stores
+type keyRecordingCache struct {
+	*cache.Memory
+	mu   sync.Mutex
+	keys []string
+}
+
+func (c *keyRecordingCache) Set(key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	c.keys = append(c.keys, key)
+	c.mu.Unlock()
+	return c.Memory.Set(key, value, ttl)
+}
+
+func TestBulkFormulaeCachedOnce(t *testing.T) {
+	transport := &httpxtest.Transport{Responses: bulkResponses}
+	defer httpx.SetBaseTransport(transport)()
+	responses := &keyRecordingCache{Memory: cache.NewMemory()}
+	httpx.SetResponseCache(responses, time.Hour)
+	defer httpx.SetResponseCache(nil, 0)
+
+	for i := 0; i < 2; i++ {
+		if _, err := fetchBulkFormulae(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	want := []string{"http/GET https://formulae.brew.sh/api/formula.json"}
+	if !reflect.DeepEqual(responses.keys, want) {
+		t.Errorf("Expected the bulk list stored once as %v, got %v", want, responses.keys)
+	}
+}
+
+// bulkResponses serve a Brewfile and the bulk formula list; any per-package
+// request 404s
+var bulkResponses = map[string]httpxtest.Response{
+	".Brewfile":         {Body: "brew \"git\"\nbrew \"old-tool\"\nbrew \"not-in-core\"\nbrew \"ublue-os/tap/foo\"\n"},
+	"/api/formula.json": {Body: "[" + gitFormulaFixture + `,{"name":"old-tool","versions":{"stable":"1.0"},"deprecated":true}]`},
+}
+
+func TestFetchHomebrewPackagesUsesBulkList(t *testing.T) {
+	transport := &httpxtest.Transport{Responses: bulkResponses}
+	defer httpx.SetBaseTransport(transport)()
+
+	apps, err := FetchHomebrewPackages()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	names := make(map[string]string)
+	for _, app := range apps {
+		names[app.ID] = app.Version
+	}
+	if names["homebrew-git"] != "2.53.0" {
+		t.Errorf("Expected git resolved from bulk list, got %v", names)
+	}
+	if _, ok := names["homebrew-old-tool"]; ok {
+		t.Error("Expected deprecated formula to be skipped")
+	}
+	if len(apps) != 3 {
+		t.Errorf("Expected 3 apps (git, not-in-core, tap package), got %d: %v", len(apps), names)
+	}
+	for _, path := range transport.Paths() {
+		if strings.HasPrefix(path, "/api/formula/") {
+			t.Errorf("Expected no per-package requests, got %s", path)
+		}
+	}
+}
+
+func TestFetchHomebrewPackagesOfflineMatchesOnline(t *testing.T) {
+	responses := cache.NewDisk(t.TempDir())
+	httpx.SetResponseCache(responses, time.Hour)
+	defer httpx.SetResponseCache(nil, 0)
+
+	restore := httpx.SetBaseTransport(&httpxtest.Transport{Responses: bulkResponses})
+	online, err := FetchHomebrewPackages()
+	restore()
+	if err != nil {
+		t.Fatalf("Unexpected online error: %v", err)
+	}
+
+	httpx.SetOffline(true)
+	defer httpx.SetOffline(false)
+	offline, err := FetchHomebrewPackages()
+	if err != nil {
+		t.Fatalf("Unexpected offline error: %v", err)
+	}
+
+	normalize := func(apps []models.App) string {
+		sort.Slice(apps, func(i, j int) bool { return apps[i].ID < apps[j].ID })
+		for i := range apps {
+			apps[i].FetchedAt = time.Time{}
+		}
+		data, _ := json.Marshal(apps)
+		return string(data)
+	}
+	if normalize(online) != normalize(offline) {
+		t.Errorf("Expected offline output to match online\nonline:  %s\noffline: %s", normalize(online), normalize(offline))
+	}
+	if misses := httpx.OfflineMisses(); len(misses) != 0 {
+		t.Errorf("Expected no offline misses, got %v", misses)
+	}
+}
+
+func TestHomebrewFetchedAtIsUTC(t *testing.T) {
+	var formula HomebrewFormula
+	if err := json.Unmarshal([]byte(gitFormulaFixture), &formula); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, app := range []*models.App{convertHomebrewFormulaToApp(formula), createMinimalHomebrewApp("ublue-os/tap/foo")} {
+		if app.FetchedAt.Location() != time.UTC {
+			t.Errorf("Expected %s FetchedAt in UTC, got %s", app.ID, app.FetchedAt.Location())
+		}
+	}
+}
+
+func TestFetchHomebrewPackagesBoundsConcurrency(t *testing.T) {
+	// A Brewfile of n packages, no bulk list, and slow per-package formulae
+	const n = 12
+	var brewfile strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&brewfile, "brew \"tool%d\"\n", i)
+	}
+	transport := &httpxtest.Transport{Responses: map[string]httpxtest.Response{
+		".Brewfile":         {Body: brewfile.String()},
+		"/api/formula.json": {Status: http.StatusNotFound},
+		"":                  {Body: gitFormulaFixture, Delay: 10 * time.Millisecond},
+	}}
+	defer httpx.SetBaseTransport(transport)()
+
+	httpx.Configure(httpx.Limits{})
+	defer httpx.Configure(httpx.DefaultLimits)
+
+	options := DefaultOptions()
+	options.HomebrewConcurrency = 3
+	Configure(options)
+	defer Configure(DefaultOptions())
+
+	apps, err := FetchHomebrewPackages()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(apps) != n {
+		t.Errorf("Expected %d apps, got %d", n, len(apps))
+	}
+	if got := transport.MaxInFlight(""); got > 3 {
+		t.Errorf("Expected at most 3 concurrent requests, got %d", got)
+	} else if got < 2 {
+		t.Errorf("Expected requests to run in parallel, got max %d", got)
+	}
+}
+
+func TestSkipReportCategories(t *testing.T) {
+	skips.Reset()
+	defer skips.Reset()
+
+	appFromFormula(HomebrewFormula{Name: "old-tool", Deprecated: true}, "old-tool")
+	appFromFormula(HomebrewFormula{Name: "mac-tool", Bottle: &Bottle{Stable: BottleStable{Files: map[string]interface{}{"arm64_sonoma": nil}}}}, "mac-tool")
+	convertOSReleases([]GitHubRelease{
+		{TagName: "stable-20260303", Draft: true},
+		{TagName: "beta-20260302", Prerelease: true},
+		{TagName: "stable-20260301"},
+	}, nil, false)
+
+	report := skips.Collect()
+	want := []skips.Skip{
+		{ID: "old-tool", PackageType: "homebrew", Reason: skips.ReasonDeprecated},
+		{ID: "stable-20260303", PackageType: "os", Reason: skips.ReasonDraft, Detail: "stable-20260303"},
+		{ID: "mac-tool", PackageType: "homebrew", Reason: skips.ReasonNonLinux},
+		{ID: "beta-20260302", PackageType: "os", Reason: skips.ReasonPrerelease, Detail: "beta-20260302"},
+	}
+	if report.Total != len(want) || !reflect.DeepEqual(report.Skipped, want) {
+		t.Errorf("Expected skips %+v, got %+v", want, report.Skipped)
+	}
+	if report.ByReason[skips.ReasonDraft] != 1 || report.ByReason[skips.ReasonNonLinux] != 1 {
+		t.Errorf("Expected one skip per reason, got %v", report.ByReason)
+	}
+}
+
+func TestKeepDeprecatedFormulae(t *testing.T) {
+	const deprecatedFixture = `{
+  "name": "old-tool",
+  "full_name": "old-tool",
+  "versions": {"stable": "1.0"},
+  "bottle": {"stable": {"files": {"x86_64_linux": {}}}},
+  "deprecated": true,
+  "deprecation_date": "2025-06-01",
+  "deprecation_reason": "repo_archived",
+  "disabled": false
+}`
+	var formula HomebrewFormula
+	if err := json.Unmarshal([]byte(deprecatedFixture), &formula); err != nil {
+		t.Fatalf("Unexpected unmarshal error: %v", err)
+	}
+
+	if app := appFromFormula(formula, "old-tool"); app != nil {
+		t.Errorf("Expected deprecated formula dropped by default, got %+v", app)
+	}
+
+	Configure(Options{KeepDeprecated: true})
+	defer Configure(DefaultOptions())
+
+	app := appFromFormula(formula, "old-tool")
+	if app == nil {
+		t.Fatal("Expected deprecated formula kept with -keep-deprecated")
+	}
+	if !app.Deprecated || app.Disabled || app.DeprecationReason != "repo_archived" {
+		t.Errorf("Expected deprecated with reason repo_archived, got deprecated=%v disabled=%v reason=%q", app.Deprecated, app.Disabled, app.DeprecationReason)
+	}
+
+	formula.Disabled, formula.DisableReason = true, "unmaintained"
+	app = appFromFormula(formula, "old-tool")
+	if app == nil || !app.Disabled || app.DeprecationReason != "unmaintained" {
+		t.Errorf("Expected disabled formula kept with its disable reason, got %+v", app)
+	}
+}
+
+func TestHomebrewDependencies(t *testing.T) {
+	const fixture = `{
+  "name": "ffmpeg",
+  "versions": {"stable": "8.0"},
+  "bottle": {"stable": {"files": {"x86_64_linux": {}}}},
+  "dependencies": ["dav1d", "lame", "libvpx", "opus", "x264"],
+  "build_dependencies": ["pkgconf"]
+}`
+	var formula HomebrewFormula
+	if err := json.Unmarshal([]byte(fixture), &formula); err != nil {
+		t.Fatalf("Unexpected unmarshal error: %v", err)
+	}
+
+	app := appFromFormula(formula, "ffmpeg")
+	want := []string{"dav1d", "lame", "libvpx", "opus", "x264"}
+	if !reflect.DeepEqual(app.HomebrewInfo.Dependencies, want) {
+		t.Errorf("Expected runtime dependencies %v, got %v", want, app.HomebrewInfo.Dependencies)
+	}
+
+	formula.Dependencies = make([]string, maxDependencies+5)
+	for i := range formula.Dependencies {
+		formula.Dependencies[i] = fmt.Sprintf("dep%d", i)
+	}
+	if got := appFromFormula(formula, "ffmpeg").HomebrewInfo.Dependencies; len(got) != maxDependencies {
+		t.Errorf("Expected dependencies capped at %d, got %d", maxDependencies, len(got))
+	}
+
+	if minimal := createMinimalHomebrewApp("custom-tool"); minimal.HomebrewInfo != nil && minimal.HomebrewInfo.Dependencies != nil {
+		t.Errorf("Expected no dependencies for a minimal entry, got %v", minimal.HomebrewInfo.Dependencies)
+	}
+}
@@ -0,0 +1,44 @@
+package bluefin
+
+import "sync"
+
+// Options controls tunable behavior of the Bluefin fetchers
+type Options struct {
+	TapConcurrency      int      // Maximum concurrent .rb file fetches across all ublue-os taps
+	HomebrewConcurrency int      // Maximum concurrent per-package Homebrew API requests
+	OSStreams           []string // Bluefin OS streams to include (e.g. "stable", "gts"); empty includes all
+	OSCommits           bool     // Attach the commit log between consecutive OS releases (one compare request per stream)
+	IncludeDrafts       bool     // Keep draft OS releases (needs a GITHUB_TOKEN with access to the repos); never used as a stream's latest
+	MajorPackages       []string // Packages whose versions are read from OS changelog tables; empty means DefaultMajorPackages
+	KeepDeprecated      bool     // Keep deprecated and disabled Homebrew formulae, flagged, instead of dropping them
+}
+
+// DefaultMajorPackages are the packages read from OS changelogs by default,
+// named as in the changelog's bold table cells
+var DefaultMajorPackages = []string{"Kernel", "Gnome", "Mesa", "Podman", "Nvidia", "Docker", "Incus"}
+
+// DefaultOptions returns the settings used when Configure isn't called
+func DefaultOptions() Options {
+	return Options{
+		TapConcurrency:      8,
+		HomebrewConcurrency: 10,
+	}
+}
+
+var (
+	optionsMu sync.RWMutex
+	options   = DefaultOptions()
+)
+
+// Configure replaces the fetcher options for subsequent fetches
+func Configure(o Options) {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	options = o
+}
+
+func currentOptions() Options {
+	optionsMu.RLock()
+	defer optionsMu.RUnlock()
+	return options
+}
@@ -8,11 +8,16 @@ import (
 	"net/http"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/castrojo/bluefin-releases/internal/assets"
+	"github.com/castrojo/bluefin-releases/internal/httpx"
 	"github.com/castrojo/bluefin-releases/internal/markdown"
 	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/skips"
+	relversion "github.com/castrojo/bluefin-releases/internal/version"
 )
 
 const (
@@ -23,15 +28,106 @@ const (
 	BluefinImageURL = "ghcr.io/ublue-os/bluefin"
 )
 
+var (
+	// githubClient fetches OS releases from the GitHub API
+	githubClient = httpx.NewClientWith(httpx.ClientOptions{
+		UserAgent:           httpx.DefaultUserAgent,
+		CacheSource:         httpx.SourceGitHubReleases,
+		GitHubTokenFallback: true,
+	})
+
+	// brewfileClient fetches Brewfiles from raw.githubusercontent.com
+	brewfileClient = httpx.NewClientWith(httpx.ClientOptions{
+		UserAgent:           httpx.DefaultUserAgent,
+		CacheSource:         httpx.SourceBrewfile,
+		GitHubTokenFallback: true,
+	})
+
+	// githubRetryClient additionally retries transient failures
+	githubRetryClient = httpx.NewClientWith(httpx.ClientOptions{
+		UserAgent:           httpx.DefaultUserAgent,
+		Retry:               &httpx.DefaultRetry,
+		GitHubTokenFallback: true,
+	})
+
+	// tapClient fetches tap directory listings and formula files
+	tapClient = httpx.NewClientWith(httpx.ClientOptions{
+		Timeout:             10 * time.Second,
+		UserAgent:           httpx.DefaultUserAgent,
+		GitHubTokenFallback: true,
+	})
+)
+
 // GitHubRelease represents a GitHub release from the API
 type GitHubRelease struct {
-	TagName     string    `json:"tag_name"`
-	Name        string    `json:"name"`
-	Body        string    `json:"body"`
-	HTMLURL     string    `json:"html_url"`
-	PublishedAt time.Time `json:"published_at"`
-	Draft       bool      `json:"draft"`
-	Prerelease  bool      `json:"prerelease"`
+	TagName     string        `json:"tag_name"`
+	Name        string        `json:"name"`
+	Body        string        `json:"body"`
+	HTMLURL     string        `json:"html_url"`
+	PublishedAt time.Time     `json:"published_at"` // Zero for drafts
+	CreatedAt   time.Time     `json:"created_at"`
+	Draft       bool          `json:"draft"`
+	Prerelease  bool          `json:"prerelease"`
+	Author      *GitHubUser   `json:"author"` // Nil for some automated releases
+	Assets      []GitHubAsset `json:"assets"`
+}
+
+// GitHubAsset is a file attached to a GitHub release
+type GitHubAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+	Size               int64  `json:"size"`
+}
+
+// withAssets records a release's attached files and their total size. Bluefin
+// images are published to ghcr.io rather than attached, so most releases have
+// none and the size stays empty; querying the registry for manifest sizes
+// would cost an authenticated request per stream.
+func withAssets(info *models.OSInfo, ghAssets []GitHubAsset) *models.OSInfo {
+	for _, a := range assetList(ghAssets) {
+		info.Assets = append(info.Assets, models.ReleaseAsset{
+			Name:          a.Name,
+			URL:           a.URL,
+			Size:          a.Size,
+			SizeFormatted: assets.FormatSize(a.Size),
+		})
+		info.ImageBytes += a.Size
+	}
+	if info.ImageBytes > 0 {
+		info.ImageSize = assets.FormatSize(info.ImageBytes)
+	}
+	return info
+}
+
+// assetList converts release assets for classification
+func assetList(ghAssets []GitHubAsset) []assets.Asset {
+	list := make([]assets.Asset, 0, len(ghAssets))
+	for _, a := range ghAssets {
+		list = append(list, assets.Asset{Name: a.Name, URL: a.BrowserDownloadURL, Size: a.Size})
+	}
+	return list
+}
+
+// GitHubUser is the account that published a release
+type GitHubUser struct {
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// GetLogin returns the user's login, or "" for a nil user
+func (u *GitHubUser) GetLogin() string {
+	if u == nil {
+		return ""
+	}
+	return u.Login
+}
+
+// GetAvatarURL returns the user's avatar URL, or "" for a nil user
+func (u *GitHubUser) GetAvatarURL() string {
+	if u == nil {
+		return ""
+	}
+	return u.AvatarURL
 }
 
 // FetchBluefinReleases fetches the latest Bluefin OS releases from GitHub
@@ -52,19 +148,16 @@ func FetchBluefinReleases() ([]models.Release, error) {
 		req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
 	}
 
-	// GitHub API requires a User-Agent header
-	req.Header.Set("User-Agent", "bluefin-releases")
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := githubClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetch releases: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusForbidden {
-		return nil, fmt.Errorf("rate limit exceeded (403) - consider setting GITHUB_TOKEN environment variable")
+		return nil, fmt.Errorf("%w (403) - consider setting GITHUB_TOKEN environment variable", httpx.ErrRateLimited)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -81,28 +174,90 @@ func FetchBluefinReleases() ([]models.Release, error) {
 		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
 
-	// Convert GitHub releases to our Release model
+	compare := compareURLs(BluefinOSRepo, githubReleases, parseOSInfo)
+
+	releases := convertOSReleases(githubReleases, compare, currentOptions().IncludeDrafts)
+
+	log.Printf("✅ Fetched %d Bluefin OS releases", len(releases))
+	return releases, nil
+}
+
+// convertOSReleases converts GitHub releases to our Release model, skipping
+// pre-releases and, unless includeDrafts is set, drafts
+func convertOSReleases(githubReleases []GitHubRelease, compare map[string]string, includeDrafts bool) []models.Release {
 	var releases []models.Release
 	for _, ghRelease := range githubReleases {
-		// Skip draft and pre-releases
-		if ghRelease.Draft || ghRelease.Prerelease {
+		if ghRelease.Prerelease || (ghRelease.Draft && !includeDrafts) {
+			recordOSSkip(ghRelease)
 			continue
 		}
+		releases = append(releases, osRelease(ghRelease, compare[ghRelease.TagName]))
+	}
+	return releases
+}
 
-		release := models.Release{
-			Version:     ghRelease.TagName,
-			Date:        ghRelease.PublishedAt,
-			Title:       ghRelease.Name,
-			Description: parseReleaseNotes(ghRelease.Body),
-			URL:         ghRelease.HTMLURL,
-			Type:        "bluefin-os-release",
+// recordOSSkip adds an unpublished OS release to the skip report
+func recordOSSkip(ghRelease GitHubRelease) {
+	reason := skips.ReasonDraft
+	if ghRelease.Prerelease {
+		reason = skips.ReasonPrerelease
+	}
+	skips.Record(ghRelease.TagName, "os", reason, ghRelease.displayName())
+}
+
+// recordLatestSkip reports a release passed over when picking the latest one.
+// Drafts kept by IncludeDrafts are still listed (see streamDrafts), so they
+// aren't skips.
+func recordLatestSkip(ghRelease GitHubRelease) {
+	if ghRelease.Draft && !ghRelease.Prerelease && currentOptions().IncludeDrafts {
+		return
+	}
+	recordOSSkip(ghRelease)
+}
+
+// streamDrafts converts the draft releases of one stream. Drafts are listed
+// alongside a stream's latest release but never replace it.
+func streamDrafts(githubReleases []GitHubRelease, stream string, info func(GitHubRelease) *models.OSInfo) []models.Release {
+	var drafts []models.Release
+	for _, ghRelease := range githubReleases {
+		if ghRelease.Draft && !ghRelease.Prerelease && info(ghRelease).Stream == stream {
+			drafts = append(drafts, osRelease(ghRelease, ""))
 		}
+	}
+	return drafts
+}
 
-		releases = append(releases, release)
+// displayName is the release's name, or its tag when the name is empty (or
+// null in the API response)
+func (r GitHubRelease) displayName() string {
+	if name := strings.TrimSpace(r.Name); name != "" {
+		return name
 	}
+	return r.TagName
+}
 
-	log.Printf("✅ Fetched %d Bluefin OS releases", len(releases))
-	return releases, nil
+// osRelease converts a single OS release. Drafts aren't published yet, so
+// they're dated by creation time.
+func osRelease(ghRelease GitHubRelease, compareURL string) models.Release {
+	date := ghRelease.PublishedAt
+	if ghRelease.Draft && date.IsZero() {
+		date = ghRelease.CreatedAt
+	}
+	release := models.Release{
+		Version:           ghRelease.TagName,
+		Date:              date.UTC(),
+		Title:             ghRelease.displayName(),
+		Description:       parseReleaseNotes(osHighlights(ghRelease.Body)),
+		DescriptionSource: ghRelease.Body,
+		URL:               ghRelease.HTMLURL,
+		Type:              "bluefin-os-release",
+		CompareURL:        compareURL,
+		Author:            ghRelease.Author.GetLogin(),
+		AuthorAvatar:      ghRelease.Author.GetAvatarURL(),
+		Draft:             ghRelease.Draft,
+	}
+	assets.MarkVerification(&release, assetList(ghRelease.Assets))
+	return release
 }
 
 // parseReleaseNotes formats release notes for display
@@ -128,18 +283,16 @@ func FetchBluefinOSApps() ([]models.App, error) {
 		req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
 	}
 
-	req.Header.Set("User-Agent", "bluefin-releases")
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := githubClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetch releases: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusForbidden {
-		return nil, fmt.Errorf("rate limit exceeded (403) - consider setting GITHUB_TOKEN environment variable")
+		return nil, fmt.Errorf("%w (403) - consider setting GITHUB_TOKEN environment variable", httpx.ErrRateLimited)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -156,31 +309,12 @@ func FetchBluefinOSApps() ([]models.App, error) {
 		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
 
-	// Track latest release for each stream
-	latestByStream := make(map[string]*GitHubRelease)
-
-	// Convert GitHub releases to App objects, keeping only latest per stream
-	for i := range githubReleases {
-		ghRelease := &githubReleases[i]
-
-		// Skip draft and pre-releases
-		if ghRelease.Draft || ghRelease.Prerelease {
-			continue
-		}
-
-		// Parse OS-specific information to get stream
-		osInfo := parseOSInfo(*ghRelease)
-		stream := osInfo.Stream
-
-		// Only keep the latest release for each stream
-		if existing, ok := latestByStream[stream]; !ok || ghRelease.PublishedAt.After(existing.PublishedAt) {
-			latestByStream[stream] = ghRelease
-		}
-	}
+	compare := compareURLs(BluefinOSRepo, githubReleases, parseOSInfo)
+	ranges := compareRanges(githubReleases, parseOSInfo)
 
 	// Convert the latest releases to App objects
 	var apps []models.App
-	for _, ghRelease := range latestByStream {
+	for _, ghRelease := range latestByStream(githubReleases, currentOptions().OSStreams) {
 		// Parse OS-specific information
 		osInfo := parseOSInfo(*ghRelease)
 
@@ -201,19 +335,16 @@ func FetchBluefinOSApps() ([]models.App, error) {
 				Owner: BluefinOSOwner,
 				Repo:  BluefinOSRepo,
 			},
-			FetchedAt:   time.Now(),
+			FetchedAt:   time.Now().UTC(),
 			PackageType: "os",
 			OSInfo:      osInfo,
-			Releases: []models.Release{
-				{
-					Version:     ghRelease.TagName,
-					Date:        ghRelease.PublishedAt,
-					Title:       ghRelease.Name,
-					Description: parseReleaseNotes(ghRelease.Body),
-					URL:         ghRelease.HTMLURL,
-					Type:        "bluefin-os-release",
-				},
-			},
+			Releases:    []models.Release{osRelease(*ghRelease, compare[ghRelease.TagName])},
+		}
+		if currentOptions().OSCommits {
+			attachCommits(BluefinOSRepo, &app.Releases[0], ranges)
+		}
+		if currentOptions().IncludeDrafts {
+			app.Releases = append(app.Releases, streamDrafts(githubReleases, osInfo.Stream, parseOSInfo)...)
 		}
 
 		apps = append(apps, app)
@@ -223,6 +354,112 @@ func FetchBluefinOSApps() ([]models.App, error) {
 	return apps, nil
 }
 
+// compareRange is the span of commits between two consecutive releases
+type compareRange struct {
+	Base string // Previous release's commit
+	Head string // This release's commit
+}
+
+// compareRanges maps each release tag to the commit range from the previous
+// release's commit in the same stream. The oldest release of a stream, and
+// releases without a commit hash on either side, get no entry.
+func compareRanges(githubReleases []GitHubRelease, info func(GitHubRelease) *models.OSInfo) map[string]compareRange {
+	var published []GitHubRelease
+	for _, ghRelease := range githubReleases {
+		if !ghRelease.Draft && !ghRelease.Prerelease {
+			published = append(published, ghRelease)
+		}
+	}
+	sort.SliceStable(published, func(i, j int) bool {
+		return published[i].PublishedAt.Before(published[j].PublishedAt)
+	})
+
+	ranges := make(map[string]compareRange)
+	previousCommit := make(map[string]string)
+	for _, ghRelease := range published {
+		osInfo := info(ghRelease)
+		if prev := previousCommit[osInfo.Stream]; prev != "" && osInfo.CommitHash != "" {
+			ranges[ghRelease.TagName] = compareRange{Base: prev, Head: osInfo.CommitHash}
+		}
+		previousCommit[osInfo.Stream] = osInfo.CommitHash
+	}
+
+	return ranges
+}
+
+// compareURLs maps each release tag to a GitHub compare URL spanning from the
+// previous release's commit in the same stream (see compareRanges)
+func compareURLs(repo string, githubReleases []GitHubRelease, info func(GitHubRelease) *models.OSInfo) map[string]string {
+	urls := make(map[string]string)
+	for tag, r := range compareRanges(githubReleases, info) {
+		urls[tag] = fmt.Sprintf("https://github.com/%s/%s/compare/%s...%s", BluefinOSOwner, repo, r.Base, r.Head)
+	}
+	return urls
+}
+
+// latestByStream keeps the newest published release of each stream, skipping
+// drafts, pre-releases, unparseable tags, and streams not in the allowed list
+// (empty allows all)
+func latestByStream(githubReleases []GitHubRelease, streams []string) map[string]*GitHubRelease {
+	allowed := make(map[string]bool, len(streams))
+	for _, stream := range streams {
+		allowed[stream] = true
+	}
+
+	latest := make(map[string]*GitHubRelease)
+	for i := range githubReleases {
+		ghRelease := &githubReleases[i]
+
+		// Skip draft and pre-releases
+		if ghRelease.Draft || ghRelease.Prerelease {
+			recordLatestSkip(*ghRelease)
+			continue
+		}
+
+		// Parse OS-specific information to get stream
+		osInfo := parseOSInfo(*ghRelease)
+		stream := osInfo.Stream
+		if stream == unparsedStream {
+			log.Printf("⚠️  Skipping Bluefin OS release %s: unrecognized tag format", ghRelease.TagName)
+			skips.Record(ghRelease.TagName, "os", skips.ReasonBadTag, ghRelease.displayName())
+			continue
+		}
+		if len(allowed) > 0 && !allowed[stream] {
+			continue
+		}
+		if osInfo.UnknownStream {
+			log.Printf("⚠️  Bluefin OS release %s is in unknown stream %q; listing it separately", ghRelease.TagName, stream)
+		}
+
+		// Only keep the latest release for each stream
+		if existing, ok := latest[stream]; !ok || ghRelease.PublishedAt.After(existing.PublishedAt) {
+			latest[stream] = ghRelease
+		}
+	}
+
+	return latest
+}
+
+// latestLTS returns the newest published LTS release, skipping drafts and
+// pre-releases, or nil if there is none
+func latestLTS(githubReleases []GitHubRelease) *GitHubRelease {
+	var latestRelease *GitHubRelease
+	for i := range githubReleases {
+		ghRelease := &githubReleases[i]
+
+		// Skip draft and pre-releases
+		if ghRelease.Draft || ghRelease.Prerelease {
+			recordLatestSkip(*ghRelease)
+			continue
+		}
+
+		if latestRelease == nil || ghRelease.PublishedAt.After(latestRelease.PublishedAt) {
+			latestRelease = ghRelease
+		}
+	}
+	return latestRelease
+}
+
 // FetchBluefinLTSApps fetches Bluefin LTS releases from the bluefin-lts repository
 func FetchBluefinLTSApps() ([]models.App, error) {
 	log.Println("Fetching Bluefin LTS releases as Apps...")
@@ -239,18 +476,16 @@ func FetchBluefinLTSApps() ([]models.App, error) {
 		req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
 	}
 
-	req.Header.Set("User-Agent", "bluefin-releases")
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := githubClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetch releases: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusForbidden {
-		return nil, fmt.Errorf("rate limit exceeded (403) - consider setting GITHUB_TOKEN environment variable")
+		return nil, fmt.Errorf("%w (403) - consider setting GITHUB_TOKEN environment variable", httpx.ErrRateLimited)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -267,20 +502,7 @@ func FetchBluefinLTSApps() ([]models.App, error) {
 		return nil, fmt.Errorf("unmarshal response: %w", err)
 	}
 
-	// Get the latest LTS release
-	var latestRelease *GitHubRelease
-	for i := range githubReleases {
-		ghRelease := &githubReleases[i]
-
-		// Skip draft and pre-releases
-		if ghRelease.Draft || ghRelease.Prerelease {
-			continue
-		}
-
-		if latestRelease == nil || ghRelease.PublishedAt.After(latestRelease.PublishedAt) {
-			latestRelease = ghRelease
-		}
-	}
+	latestRelease := latestLTS(githubReleases)
 
 	var apps []models.App
 	if latestRelease != nil {
@@ -304,19 +526,16 @@ func FetchBluefinLTSApps() ([]models.App, error) {
 				Owner: BluefinOSOwner,
 				Repo:  BluefinLTSRepo,
 			},
-			FetchedAt:   time.Now(),
+			FetchedAt:   time.Now().UTC(),
 			PackageType: "os",
 			OSInfo:      osInfo,
-			Releases: []models.Release{
-				{
-					Version:     latestRelease.TagName,
-					Date:        latestRelease.PublishedAt,
-					Title:       latestRelease.Name,
-					Description: parseReleaseNotes(latestRelease.Body),
-					URL:         latestRelease.HTMLURL,
-					Type:        "bluefin-os-release",
-				},
-			},
+			Releases:    []models.Release{osRelease(*latestRelease, compareURLs(BluefinLTSRepo, githubReleases, parseLTSInfo)[latestRelease.TagName])},
+		}
+		if currentOptions().OSCommits {
+			attachCommits(BluefinLTSRepo, &app.Releases[0], compareRanges(githubReleases, parseLTSInfo))
+		}
+		if currentOptions().IncludeDrafts {
+			app.Releases = append(app.Releases, streamDrafts(githubReleases, osInfo.Stream, parseLTSInfo)...)
 		}
 
 		apps = append(apps, app)
@@ -326,15 +545,44 @@ func FetchBluefinLTSApps() ([]models.App, error) {
 	return apps, nil
 }
 
+// KnownOSStreams are the Bluefin OS streams whose tags are recognized.
+// Tags naming any other stream are still kept, but flagged as unknown.
+var KnownOSStreams = []string{"stable", "gts", "latest", "beta"}
+
+// unparsedStream is the stream of a release whose tag isn't "<stream>-<build>"
+const unparsedStream = "unknown"
+
+// osTagRe matches "<stream>-<YYYYMMDD>", with an optional ".N" rebuild suffix.
+// Streams may contain dashes, e.g. "stable-daily-20260203".
+var osTagRe = regexp.MustCompile(`^([a-z][a-z0-9]*(?:-[a-z][a-z0-9]*)*)-(\d{8}(?:\.\d+)?)$`)
+
+// parseOSTag splits a release tag into stream and build number. ok is false
+// when the tag doesn't have the "<stream>-<build>" shape at all.
+func parseOSTag(tag string) (stream, build string, ok bool) {
+	match := osTagRe.FindStringSubmatch(tag)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+// isKnownOSStream reports whether stream is in KnownOSStreams
+func isKnownOSStream(stream string) bool {
+	for _, known := range KnownOSStreams {
+		if stream == known {
+			return true
+		}
+	}
+	return false
+}
+
 // parseOSInfo extracts OS-specific information from release data
 func parseOSInfo(release GitHubRelease) *models.OSInfo {
-	// Parse tag name (e.g., "stable-20260203" or "gts-20260203")
-	parts := strings.Split(release.TagName, "-")
-	stream := "stable"
-	buildNumber := release.TagName
-	if len(parts) >= 2 {
-		stream = parts[0]
-		buildNumber = parts[1]
+	// Parse tag name (e.g., "stable-20260203" or "gts-20260203"). A tag in
+	// another shape is never guessed to be stable.
+	stream, buildNumber, ok := parseOSTag(release.TagName)
+	if !ok {
+		stream, buildNumber = unparsedStream, release.TagName
 	}
 
 	// Parse release name to extract Fedora version and commit
@@ -348,46 +596,22 @@ func parseOSInfo(release GitHubRelease) *models.OSInfo {
 		commitHash = commitMatch[1]
 	}
 
-	// Extract major package versions from changelog
-	kernelVersion := extractPackageVersion(release.Body, "Kernel")
-	gnomeVersion := extractPackageVersion(release.Body, "Gnome")
-	mesaVersion := extractPackageVersion(release.Body, "Mesa")
-
-	// Extract other major packages
-	majorPackages := make(map[string]string)
-	if podmanVer := extractPackageVersion(release.Body, "Podman"); podmanVer != "" {
-		majorPackages["Podman"] = podmanVer
-	}
-	if nvidiaVer := extractPackageVersion(release.Body, "Nvidia"); nvidiaVer != "" {
-		majorPackages["Nvidia"] = nvidiaVer
-	}
-	if dockerVer := extractPackageVersion(release.Body, "Docker"); dockerVer != "" {
-		majorPackages["Docker"] = dockerVer
-	}
-	if incusVer := extractPackageVersion(release.Body, "Incus"); incusVer != "" {
-		majorPackages["Incus"] = incusVer
-	}
-
-	return &models.OSInfo{
+	return withAssets(withPackages(&models.OSInfo{
 		Stream:        stream,
+		UnknownStream: !isKnownOSStream(stream),
 		FedoraVersion: fedoraVersion,
 		BuildNumber:   buildNumber,
 		CommitHash:    commitHash,
 		ImageName:     fmt.Sprintf("%s:%s", BluefinImageURL, stream),
-		KernelVersion: kernelVersion,
-		GnomeVersion:  gnomeVersion,
-		MesaVersion:   mesaVersion,
-		MajorPackages: majorPackages,
-	}
+	}, release.Body), release.Assets)
 }
 
 // parseLTSInfo extracts LTS-specific information from release data
 func parseLTSInfo(release GitHubRelease) *models.OSInfo {
 	// Parse tag name (e.g., "lts-20260203")
-	parts := strings.Split(release.TagName, "-")
 	buildNumber := release.TagName
-	if len(parts) >= 2 {
-		buildNumber = parts[1]
+	if _, build, ok := parseOSTag(release.TagName); ok {
+		buildNumber = build
 	}
 
 	// Parse release name to extract CentOS version and commit
@@ -401,39 +625,48 @@ func parseLTSInfo(release GitHubRelease) *models.OSInfo {
 		commitHash = commitMatch[1]
 	}
 
-	// Extract major package versions from changelog
-	kernelVersion := extractPackageVersion(release.Body, "Kernel")
-	gnomeVersion := extractPackageVersion(release.Body, "Gnome")
-	mesaVersion := extractPackageVersion(release.Body, "Mesa")
-
-	// Extract other major packages
-	majorPackages := make(map[string]string)
-	if podmanVer := extractPackageVersion(release.Body, "Podman"); podmanVer != "" {
-		majorPackages["Podman"] = podmanVer
-	}
-	if nvidiaVer := extractPackageVersion(release.Body, "Nvidia"); nvidiaVer != "" {
-		majorPackages["Nvidia"] = nvidiaVer
-	}
-	if dockerVer := extractPackageVersion(release.Body, "Docker"); dockerVer != "" {
-		majorPackages["Docker"] = dockerVer
-	}
-	if incusVer := extractPackageVersion(release.Body, "Incus"); incusVer != "" {
-		majorPackages["Incus"] = incusVer
-	}
-
-	return &models.OSInfo{
+	return withAssets(withPackages(&models.OSInfo{
 		Stream:        "lts",
 		CentOSVersion: centosVersion,
 		BuildNumber:   buildNumber,
 		CommitHash:    commitHash,
 		ImageName:     fmt.Sprintf("%s:lts", BluefinImageURL),
-		KernelVersion: kernelVersion,
-		GnomeVersion:  gnomeVersion,
-		MesaVersion:   mesaVersion,
-		MajorPackages: majorPackages,
+	}, release.Body), release.Assets)
+}
+
+// withPackages fills info with the versions of the configured major packages
+// (Options.MajorPackages) found in a release changelog. Kernel, Gnome, and
+// Mesa have dedicated fields; the rest go into MajorPackages by name.
+func withPackages(info *models.OSInfo, body string) *models.OSInfo {
+	names := currentOptions().MajorPackages
+	if len(names) == 0 {
+		names = DefaultMajorPackages
 	}
+
+	info.MajorPackages = make(map[string]string)
+	for _, name := range names {
+		version := extractPackageVersion(body, name)
+		if version == "" {
+			continue
+		}
+		switch strings.ToLower(name) {
+		case "kernel":
+			info.KernelVersion = version
+		case "gnome":
+			info.GnomeVersion = version
+		case "mesa":
+			info.MesaVersion = version
+		default:
+			info.MajorPackages[name] = version
+		}
+	}
+	return info
 }
 
+// packageVersionRe matches the numeric version in a changelog cell, keeping a
+// prerelease tag and a numeric package release: "49.2-1", "49~rc", "6.17.12-300"
+var packageVersionRe = regexp.MustCompile(`\d+(?:\.\d+)*(?:[.~-]?(?:alpha|beta|rc)\.?\d*)?(?:-\d+)?`)
+
 // extractPackageVersion extracts a package version from the release body
 // Looks for lines like "| **Kernel** | 6.17.12-300 |"
 func extractPackageVersion(body, packageName string) string {
@@ -441,20 +674,29 @@ func extractPackageVersion(body, packageName string) string {
 	pattern := fmt.Sprintf(`\|\s*\*\*%s\*\*\s*\|\s*([^\|]+)\s*\|`, regexp.QuoteMeta(packageName))
 	re := regexp.MustCompile(pattern)
 	if match := re.FindStringSubmatch(body); len(match) > 1 {
-		// Clean up version string (remove arrows and extra whitespace)
-		version := strings.TrimSpace(match[1])
-		// If there's an arrow (version change), take the second version
-		if strings.Contains(version, "➡️") {
-			parts := strings.Split(version, "➡️")
-			if len(parts) > 1 {
-				return strings.TrimSpace(parts[1])
-			}
+		// Drop the package's own name ("GNOME 47", "mesa-25.2.8") so both
+		// sides of an upgrade start with their version
+		namePrefix := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(packageName) + `[\s-]*`)
+		version := strings.TrimSpace(namePrefix.ReplaceAllString(match[1], ""))
+		// If there's an arrow (version change), take the new version
+		if _, to, ok := relversion.ParseTransition(version); ok {
+			version = to
 		}
-		return version
+		return normalizePackageVersion(version)
 	}
 	return ""
 }
 
+// normalizePackageVersion reduces a changelog cell to its numeric version,
+// dropping prefixes, suffixes like "-mesa", and dist tags like ".fc43".
+// Cells without a number are returned trimmed.
+func normalizePackageVersion(cell string) string {
+	if version := packageVersionRe.FindString(cell); version != "" {
+		return version
+	}
+	return strings.Trim(cell, " `")
+}
+
 // formatOSName creates a display name for the OS release
 func formatOSName(osInfo *models.OSInfo) string {
 	switch osInfo.Stream {
@@ -463,10 +705,23 @@ func formatOSName(osInfo *models.OSInfo) string {
 	case "lts":
 		return "Bluefin LTS"
 	default:
+		if osInfo.UnknownStream {
+			// Keep a new stream distinguishable from stable
+			return "Bluefin (" + osInfo.Stream + ")"
+		}
 		return "Bluefin"
 	}
 }
 
+// withVersion appends version to name, leaving name alone when the version
+// couldn't be parsed (e.g. from a release without a name)
+func withVersion(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + " " + version
+}
+
 // extractSummary creates a concise summary for the OS release
 func extractSummary(release GitHubRelease, osInfo *models.OSInfo) string {
 	var streamName string
@@ -475,13 +730,20 @@ func extractSummary(release GitHubRelease, osInfo *models.OSInfo) string {
 	switch osInfo.Stream {
 	case "gts":
 		streamName = "GTS (General-Term Support)"
-		baseOS = fmt.Sprintf("Fedora %s", osInfo.FedoraVersion)
+		baseOS = withVersion("Fedora", osInfo.FedoraVersion)
 	case "lts":
 		streamName = "LTS (Long-Term Support)"
-		baseOS = fmt.Sprintf("CentOS Stream %s", osInfo.CentOSVersion)
-	default:
+		baseOS = withVersion("CentOS Stream", osInfo.CentOSVersion)
+	case "stable":
 		streamName = "Stable"
-		baseOS = fmt.Sprintf("Fedora %s", osInfo.FedoraVersion)
+		baseOS = withVersion("Fedora", osInfo.FedoraVersion)
+	default:
+		// Never fold latest, beta or a new stream into stable
+		streamName = strings.ToUpper(osInfo.Stream[:1]) + osInfo.Stream[1:]
+		if osInfo.UnknownStream {
+			streamName = fmt.Sprintf("Unrecognized stream %q", osInfo.Stream)
+		}
+		baseOS = withVersion("Fedora", osInfo.FedoraVersion)
 	}
 
 	summary := fmt.Sprintf("%s release based on %s", streamName, baseOS)
@@ -0,0 +1,383 @@
+package bluefin
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/skips"
+)
+
+func TestLatestByStream(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 2, d, 0, 0, 0, 0, time.UTC) }
+	releases := []GitHubRelease{
+		{TagName: "stable-20260201", PublishedAt: day(1)},
+		{TagName: "stable-20260203", PublishedAt: day(3)},
+		{TagName: "gts-20260202", PublishedAt: day(2)},
+		{TagName: "stable-20260204", PublishedAt: day(4), Prerelease: true},
+	}
+
+	tests := []struct {
+		name    string
+		streams []string
+		want    map[string]string
+	}{
+		{
+			name: "all streams",
+			want: map[string]string{"stable": "stable-20260203", "gts": "gts-20260202"},
+		},
+		{
+			name:    "stable only",
+			streams: []string{"stable"},
+			want:    map[string]string{"stable": "stable-20260203"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := latestByStream(releases, tt.streams)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d streams, got %d", len(tt.want), len(got))
+			}
+			for stream, tag := range tt.want {
+				if got[stream] == nil || got[stream].TagName != tag {
+					t.Errorf("Expected %s latest to be %s, got %v", stream, tag, got[stream])
+				}
+			}
+		})
+	}
+}
+
+func TestCompareURLs(t *testing.T) {
+	releases := []GitHubRelease{
+		// Newest first, as returned by the API
+		{TagName: "stable-20260203", Name: "stable-20260203: Stable (F43.20260203, #4132884)", PublishedAt: time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)},
+		{TagName: "stable-20260127", Name: "stable-20260127: Stable (F43.20260127, #9a1b2c3)", PublishedAt: time.Date(2026, 1, 27, 0, 0, 0, 0, time.UTC)},
+	}
+
+	urls := compareURLs(BluefinOSRepo, releases, parseOSInfo)
+
+	want := "https://github.com/ublue-os/bluefin/compare/9a1b2c3...4132884"
+	if urls["stable-20260203"] != want {
+		t.Errorf("Expected %q, got %q", want, urls["stable-20260203"])
+	}
+	if url, ok := urls["stable-20260127"]; ok {
+		t.Errorf("Expected no compare URL for the oldest release, got %q", url)
+	}
+}
+
+func TestGitHubReleaseAuthor(t *testing.T) {
+	payload := `[
+  {"tag_name": "stable-20260301", "author": {"login": "ublue-os-bot", "avatar_url": "https://avatars.githubusercontent.com/in/1?v=4"}},
+  {"tag_name": "stable-20260222"}
+]`
+	var releases []GitHubRelease
+	if err := json.Unmarshal([]byte(payload), &releases); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+
+	if got := releases[0].Author.GetLogin(); got != "ublue-os-bot" {
+		t.Errorf("Expected author ublue-os-bot, got %q", got)
+	}
+	if got := releases[0].Author.GetAvatarURL(); got != "https://avatars.githubusercontent.com/in/1?v=4" {
+		t.Errorf("Expected avatar URL, got %q", got)
+	}
+	if got := releases[1].Author.GetLogin(); got != "" {
+		t.Errorf("Expected no author, got %q", got)
+	}
+}
+
+func TestOSReleaseDrafts(t *testing.T) {
+	published := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	created := published.AddDate(0, 0, 2)
+	releases := []GitHubRelease{
+		{TagName: "stable-20260303", Draft: true, CreatedAt: created},
+		{TagName: "stable-20260301", PublishedAt: published},
+	}
+
+	t.Run("skipped by default", func(t *testing.T) {
+		got := convertOSReleases(releases, nil, false)
+		if len(got) != 1 || got[0].Version != "stable-20260301" || got[0].Draft {
+			t.Errorf("Expected only the published release, got %+v", got)
+		}
+	})
+
+	t.Run("included on request", func(t *testing.T) {
+		got := convertOSReleases(releases, nil, true)
+		if len(got) != 2 {
+			t.Fatalf("Expected draft and published releases, got %d", len(got))
+		}
+		if !got[0].Draft || !got[0].Date.Equal(created) {
+			t.Errorf("Expected draft dated by creation time, got %+v", got[0])
+		}
+	})
+
+	t.Run("never the latest of a stream", func(t *testing.T) {
+		if latest := latestByStream(releases, nil)["stable"]; latest == nil || latest.TagName != "stable-20260301" {
+			t.Errorf("Expected stable-20260301 as latest, got %+v", latest)
+		}
+		drafts := streamDrafts(releases, "stable", parseOSInfo)
+		if len(drafts) != 1 || drafts[0].Version != "stable-20260303" {
+			t.Errorf("Expected the stable draft, got %+v", drafts)
+		}
+		if drafts := streamDrafts(releases, "gts", parseOSInfo); len(drafts) != 0 {
+			t.Errorf("Expected no gts drafts, got %+v", drafts)
+		}
+	})
+}
+
+func TestLatestSkipsIncludedDrafts(t *testing.T) {
+	published := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	releases := []GitHubRelease{
+		{TagName: "stable-20260303", Draft: true},
+		{TagName: "stable-20260302", Prerelease: true},
+		{TagName: "stable-20260301", PublishedAt: published},
+	}
+	lts := []GitHubRelease{
+		{TagName: "lts-20260303", Draft: true},
+		{TagName: "lts-20260302", Prerelease: true},
+		{TagName: "lts-20260301", PublishedAt: published},
+	}
+
+	tests := []struct {
+		includeDrafts bool
+		want          map[string]int
+	}{
+		{includeDrafts: false, want: map[string]int{skips.ReasonDraft: 2, skips.ReasonPrerelease: 2}},
+		{includeDrafts: true, want: map[string]int{skips.ReasonPrerelease: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("includeDrafts=%v", tt.includeDrafts), func(t *testing.T) {
+			skips.Reset()
+			defer skips.Reset()
+			opts := DefaultOptions()
+			opts.IncludeDrafts = tt.includeDrafts
+			Configure(opts)
+			defer Configure(DefaultOptions())
+
+			if latest := latestByStream(releases, nil)["stable"]; latest == nil || latest.TagName != "stable-20260301" {
+				t.Errorf("Expected stable-20260301 as latest, got %+v", latest)
+			}
+			if latest := latestLTS(lts); latest == nil || latest.TagName != "lts-20260301" {
+				t.Errorf("Expected lts-20260301 as latest, got %+v", latest)
+			}
+
+			if got := skips.Collect().ByReason; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected skips %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOSInfoAssetSizes(t *testing.T) {
+	var release GitHubRelease
+	data := `{
+		"tag_name": "stable-20260301",
+		"assets": [
+			{"name": "bluefin-stable.iso", "browser_download_url": "https://example.com/bluefin-stable.iso", "size": 2254857830},
+			{"name": "bluefin-stable.iso-CHECKSUM", "browser_download_url": "https://example.com/bluefin-stable.iso-CHECKSUM", "size": 512}
+		]
+	}`
+	if err := json.Unmarshal([]byte(data), &release); err != nil {
+		t.Fatalf("Failed to decode release: %v", err)
+	}
+
+	info := parseOSInfo(release)
+	if len(info.Assets) != 2 {
+		t.Fatalf("Expected 2 assets, got %d", len(info.Assets))
+	}
+	if info.Assets[0].Size != 2254857830 || info.Assets[0].SizeFormatted != "2.1 GiB" {
+		t.Errorf("Unexpected ISO asset: %+v", info.Assets[0])
+	}
+	if info.Assets[1].SizeFormatted != "512 B" {
+		t.Errorf("Expected checksum size 512 B, got %q", info.Assets[1].SizeFormatted)
+	}
+	if info.ImageBytes != 2254858342 || info.ImageSize != "2.1 GiB" {
+		t.Errorf("Expected 2.1 GiB total, got %d (%q)", info.ImageBytes, info.ImageSize)
+	}
+
+	// Releases without attached images report no size rather than zero
+	bare := parseLTSInfo(GitHubRelease{TagName: "lts-20260301"})
+	if len(bare.Assets) != 0 || bare.ImageBytes != 0 || bare.ImageSize != "" {
+		t.Errorf("Expected no asset sizes, got %+v", bare)
+	}
+}
+
+func TestParseOSInfoTagFormats(t *testing.T) {
+	tests := []struct {
+		tag         string
+		wantStream  string
+		wantBuild   string
+		wantUnknown bool
+	}{
+		{tag: "stable-20260203", wantStream: "stable", wantBuild: "20260203"},
+		{tag: "gts-20260203", wantStream: "gts", wantBuild: "20260203"},
+		{tag: "latest-20260203.1", wantStream: "latest", wantBuild: "20260203.1"},
+		{tag: "beta-20260302", wantStream: "beta", wantBuild: "20260302"},
+		// Hypothetical future formats
+		{tag: "nightly-20270101", wantStream: "nightly", wantBuild: "20270101", wantUnknown: true},
+		{tag: "stable-daily-20270101", wantStream: "stable-daily", wantBuild: "20270101", wantUnknown: true},
+		{tag: "v2027.01.01", wantStream: unparsedStream, wantBuild: "v2027.01.01", wantUnknown: true},
+		{tag: "20270101", wantStream: unparsedStream, wantBuild: "20270101", wantUnknown: true},
+	}
+
+	for _, tt := range tests {
+		info := parseOSInfo(GitHubRelease{TagName: tt.tag})
+		if info.Stream != tt.wantStream || info.BuildNumber != tt.wantBuild || info.UnknownStream != tt.wantUnknown {
+			t.Errorf("%s: expected stream %q build %q unknown %v, got %q %q %v",
+				tt.tag, tt.wantStream, tt.wantBuild, tt.wantUnknown, info.Stream, info.BuildNumber, info.UnknownStream)
+		}
+	}
+}
+
+func TestExtractSummaryNamesStream(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{tag: "stable-20260203", want: "Stable release based on Fedora 43"},
+		{tag: "latest-20260203", want: "Latest release based on Fedora 43"},
+		{tag: "beta-20260203", want: "Beta release based on Fedora 43"},
+		// Hypothetical future tags
+		{tag: "nightly-20270101", want: `Unrecognized stream "nightly" release based on Fedora 43`},
+		{tag: "v2027.01.01", want: `Unrecognized stream "unknown" release based on Fedora 43`},
+	}
+
+	for _, tt := range tests {
+		release := GitHubRelease{TagName: tt.tag, Name: tt.tag + ": (F43.20260203, #4132884)"}
+		if got := extractSummary(release, parseOSInfo(release)); got != tt.want {
+			t.Errorf("%s: expected summary %q, got %q", tt.tag, tt.want, got)
+		}
+	}
+}
+
+func TestLatestByStreamKeepsNewStreamsApart(t *testing.T) {
+	skips.Reset()
+	defer skips.Reset()
+
+	day := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	latest := latestByStream([]GitHubRelease{
+		{TagName: "stable-20261231", PublishedAt: day.Add(-24 * time.Hour)},
+		{TagName: "nightly-20270101", PublishedAt: day},
+		{TagName: "v2027.01.01", PublishedAt: day},
+	}, nil)
+
+	if got := latest["stable"]; got == nil || got.TagName != "stable-20261231" {
+		t.Errorf("Expected stable to keep its own release, got %+v", got)
+	}
+	if got := latest["nightly"]; got == nil || got.TagName != "nightly-20270101" {
+		t.Errorf("Expected nightly listed as its own stream, got %+v", got)
+	}
+	if len(latest) != 2 {
+		t.Errorf("Expected 2 streams, got %d", len(latest))
+	}
+	if report := skips.Collect(); report.ByReason[skips.ReasonBadTag] != 1 {
+		t.Errorf("Expected the unparseable tag in the skip report, got %+v", report.Skipped)
+	}
+	if name := formatOSName(parseOSInfo(*latest["nightly"])); name != "Bluefin (nightly)" {
+		t.Errorf("Expected new stream named apart from stable, got %q", name)
+	}
+}
+
+func TestExtractPackageVersionFormats(t *testing.T) {
+	tests := []struct {
+		pkg  string
+		cell string
+		want string
+	}{
+		{pkg: "Kernel", cell: "6.17.12-300", want: "6.17.12-300"},
+		{pkg: "Kernel", cell: "6.17.12-300.fc43.x86_64", want: "6.17.12-300"},
+		{pkg: "Kernel", cell: "6.17.11-300 ➡️ 6.17.12-300", want: "6.17.12-300"},
+		{pkg: "Gnome", cell: "47.1", want: "47.1"},
+		{pkg: "Gnome", cell: "GNOME 47", want: "47"},
+		{pkg: "Gnome", cell: "GNOME 48 ➡️ GNOME 49", want: "49"},
+		{pkg: "Gnome", cell: "49.rc-1", want: "49.rc-1"},
+		{pkg: "Gnome", cell: "49.2-1", want: "49.2-1"},
+		{pkg: "Mesa", cell: "25.2.8-1", want: "25.2.8-1"},
+		{pkg: "Mesa", cell: "25.2.8-mesa", want: "25.2.8"},
+		{pkg: "Mesa", cell: "mesa-25.2.7-1.fc43 → mesa-25.2.8-1.fc43", want: "25.2.8-1"},
+		{pkg: "Mesa", cell: "`25.2.8`", want: "25.2.8"},
+		{pkg: "Mesa", cell: "n/a", want: "n/a"},
+	}
+
+	for _, tt := range tests {
+		body := fmt.Sprintf("| Name | Version |\n| --- | --- |\n| **%s** | %s |\n", tt.pkg, tt.cell)
+		if got := extractPackageVersion(body, tt.pkg); got != tt.want {
+			t.Errorf("%s %q: expected %q, got %q", tt.pkg, tt.cell, tt.want, got)
+		}
+	}
+}
+
+func TestOSInfoMajorPackages(t *testing.T) {
+	body := strings.Replace(osReleaseBody, "| **Docker** | 29.1.3 |",
+		"| **Docker** | 29.1.3 |\n| **systemd** | 257.6-1 ➡️ 257.7-1 |\n| **Firefox** | 141.0-1 |", 1)
+	defer Configure(DefaultOptions())
+
+	t.Run("defaults", func(t *testing.T) {
+		Configure(DefaultOptions())
+		info := parseOSInfo(GitHubRelease{TagName: "stable-20260203", Body: body})
+		if info.KernelVersion != "6.17.12-300" || info.GnomeVersion != "49.2-1" || info.MesaVersion != "25.2.8-1" {
+			t.Errorf("Expected kernel, GNOME and Mesa versions, got %+v", info)
+		}
+		want := map[string]string{"Docker": "29.1.3"}
+		if !reflect.DeepEqual(info.MajorPackages, want) {
+			t.Errorf("Expected major packages %v, got %v", want, info.MajorPackages)
+		}
+	})
+
+	t.Run("custom list", func(t *testing.T) {
+		opts := DefaultOptions()
+		opts.MajorPackages = []string{"Kernel", "systemd", "Firefox", "Podman"}
+		Configure(opts)
+
+		for _, info := range []*models.OSInfo{
+			parseOSInfo(GitHubRelease{TagName: "stable-20260203", Body: body}),
+			parseLTSInfo(GitHubRelease{TagName: "lts-20260203", Body: body}),
+		} {
+			if info.KernelVersion != "6.17.12-300" {
+				t.Errorf("%s: expected kernel version, got %q", info.Stream, info.KernelVersion)
+			}
+			if info.GnomeVersion != "" || info.MesaVersion != "" {
+				t.Errorf("%s: expected unlisted GNOME and Mesa to be skipped, got %q %q", info.Stream, info.GnomeVersion, info.MesaVersion)
+			}
+			want := map[string]string{"systemd": "257.7-1", "Firefox": "141.0-1"}
+			if !reflect.DeepEqual(info.MajorPackages, want) {
+				t.Errorf("%s: expected major packages %v, got %v", info.Stream, want, info.MajorPackages)
+			}
+		}
+	})
+}
+
+func TestOSReleaseWithoutName(t *testing.T) {
+	var release GitHubRelease
+	data := `{"tag_name": "stable-20260301", "name": null, "body": "| **Kernel** | 6.17.12-300 |", "published_at": "2026-03-01T00:00:00Z"}`
+	if err := json.Unmarshal([]byte(data), &release); err != nil {
+		t.Fatalf("Failed to decode release: %v", err)
+	}
+
+	info := parseOSInfo(release)
+	if info.Stream != "stable" || info.FedoraVersion != "" || info.CommitHash != "" {
+		t.Errorf("Expected stream from the tag and no version or commit, got %+v", info)
+	}
+	if got, want := extractSummary(release, info), "Stable release based on Fedora with Kernel 6.17.12-300"; got != want {
+		t.Errorf("Expected summary %q, got %q", want, got)
+	}
+	if got := osRelease(release, "").Title; got != "stable-20260301" {
+		t.Errorf("Expected the tag as title, got %q", got)
+	}
+
+	lts := GitHubRelease{TagName: "lts-20260301", Name: "  "}
+	if got, want := extractSummary(lts, parseLTSInfo(lts)), "LTS (Long-Term Support) release based on CentOS Stream"; got != want {
+		t.Errorf("Expected summary %q, got %q", want, got)
+	}
+
+	named := GitHubRelease{TagName: "gts-20260301", Name: "gts-20260301: GTS (F42.20260301, #4132884)"}
+	if got, want := extractSummary(named, parseOSInfo(named)), "GTS (General-Term Support) release based on Fedora 42"; got != want {
+		t.Errorf("Expected summary %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,214 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores byte payloads with per-entry expiry
+type Cache interface {
+	// Get returns the value for key if present and not expired
+	Get(key string) ([]byte, bool)
+	// GetStale returns the value for key even if it has expired
+	GetStale(key string) ([]byte, bool)
+	// Set stores value under key for ttl
+	Set(key string, value []byte, ttl time.Duration) error
+}
+
+// Backend names accepted by New
+const (
+	BackendMemory = "memory" // Per-run, in-process
+	BackendDisk   = "disk"   // Persisted across runs under a directory
+	BackendNone   = "none"   // Caching disabled
+)
+
+// Backends returns the backend names accepted by New
+func Backends() []string {
+	return []string{BackendMemory, BackendDisk, BackendNone}
+}
+
+// New returns the cache for backend. The disk backend is rooted at dir and
+// falls back to None when dir is empty, so caching stays opt-in.
+func New(backend, dir string) (Cache, error) {
+	switch backend {
+	case BackendMemory:
+		return NewMemory(), nil
+	case BackendDisk:
+		if dir == "" {
+			return None{}, nil
+		}
+		return NewDisk(dir), nil
+	case BackendNone:
+		return None{}, nil
+	}
+	return nil, fmt.Errorf("unknown cache backend %q: must be one of %s", backend, strings.Join(Backends(), ", "))
+}
+
+// Enabled reports whether c can hold anything: false for nil, None, and a nil *Disk
+func Enabled(c Cache) bool {
+	switch c := c.(type) {
+	case nil, None:
+		return false
+	case *Disk:
+		return c != nil
+	}
+	return true
+}
+
+// Disk is a simple on-disk cache of byte payloads with per-entry expiry.
+// A nil *Disk is a valid, always-missing cache so callers don't need to
+// special-case caching being disabled.
+type Disk struct {
+	dir string
+}
+
+// entry is the envelope written for each cached key
+type entry struct {
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Data      []byte    `json:"data"`
+}
+
+// NewDisk returns a cache rooted at dir, or nil when dir is empty
+func NewDisk(dir string) *Disk {
+	if dir == "" {
+		return nil
+	}
+	return &Disk{dir: dir}
+}
+
+// Get returns the cached value for key if present and not expired
+func (d *Disk) Get(key string) ([]byte, bool) {
+	e, ok := d.read(key)
+	if !ok || time.Now().After(e.ExpiresAt) {
+		return nil, false
+	}
+	return e.Data, true
+}
+
+// GetStale returns the cached value for key even if it has expired.
+// Used by offline mode, where any cached data beats none.
+func (d *Disk) GetStale(key string) ([]byte, bool) {
+	e, ok := d.read(key)
+	if !ok {
+		return nil, false
+	}
+	return e.Data, true
+}
+
+func (d *Disk) read(key string) (entry, bool) {
+	if d == nil {
+		return entry{}, false
+	}
+
+	raw, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return entry{}, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(raw, &e); err != nil || e.Key != key {
+		return entry{}, false
+	}
+	return e, true
+}
+
+// Set stores value under key for ttl
+func (d *Disk) Set(key string, value []byte, ttl time.Duration) error {
+	if d == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+
+	raw, err := json.Marshal(entry{Key: key, ExpiresAt: time.Now().Add(ttl), Data: value})
+	if err != nil {
+		return fmt.Errorf("encode cache entry: %w", err)
+	}
+
+	// Write to a temp file first so concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("close cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("store cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// path maps a key to a filesystem-safe filename
+func (d *Disk) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Memory is an in-process cache that lives for the run
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// NewMemory returns an empty in-memory cache
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]entry)}
+}
+
+// Get returns the cached value for key if present and not expired
+func (m *Memory) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok || time.Now().After(e.ExpiresAt) {
+		return nil, false
+	}
+	return e.Data, true
+}
+
+// GetStale returns the cached value for key even if it has expired
+func (m *Memory) GetStale(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	return e.Data, ok
+}
+
+// Set stores a copy of value under key for ttl
+func (m *Memory) Set(key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = entry{Key: key, ExpiresAt: time.Now().Add(ttl), Data: append([]byte(nil), value...)}
+	return nil
+}
+
+// None is a cache that stores nothing and always misses
+type None struct{}
+
+// Get always misses
+func (None) Get(string) ([]byte, bool) { return nil, false }
+
+// GetStale always misses
+func (None) GetStale(string) ([]byte, bool) { return nil, false }
+
+// Set discards value
+func (None) Set(string, []byte, time.Duration) error { return nil }
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDisk(t *testing.T) {
+	d := NewDisk(t.TempDir())
+
+	if _, ok := d.Get("formula/git"); ok {
+		t.Fatal("Expected miss on empty cache")
+	}
+
+	if err := d.Set("formula/git", []byte(`{"name":"git"}`), time.Hour); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, ok := d.Get("formula/git")
+	if !ok || string(data) != `{"name":"git"}` {
+		t.Errorf("Expected cached value, got %q (hit=%v)", data, ok)
+	}
+
+	if err := d.Set("formula/old", []byte("stale"), -time.Second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := d.Get("formula/old"); ok {
+		t.Error("Expected expired entry to miss")
+	}
+}
+
+func TestNilDisk(t *testing.T) {
+	var d *Disk = NewDisk("")
+	if err := d.Set("key", []byte("value"), time.Hour); err != nil {
+		t.Errorf("Expected nil cache Set to succeed, got %v", err)
+	}
+	if _, ok := d.Get("key"); ok {
+		t.Error("Expected nil cache to always miss")
+	}
+}
+
+func TestBackends(t *testing.T) {
+	tests := []struct {
+		backend  string
+		dir      string
+		wantHits bool
+	}{
+		{backend: BackendMemory, wantHits: true},
+		{backend: BackendDisk, dir: t.TempDir(), wantHits: true},
+		{backend: BackendDisk},
+		{backend: BackendNone, dir: t.TempDir()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.backend+"/"+tt.dir, func(t *testing.T) {
+			c, err := New(tt.backend, tt.dir)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if Enabled(c) != tt.wantHits {
+				t.Errorf("Expected Enabled %v, got %v", tt.wantHits, Enabled(c))
+			}
+
+			if _, ok := c.Get("key"); ok {
+				t.Error("Expected miss on empty cache")
+			}
+			if err := c.Set("key", []byte("value"), time.Hour); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err := c.Set("old", []byte("stale"), -time.Second); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			data, ok := c.Get("key")
+			if ok != tt.wantHits || (ok && string(data) != "value") {
+				t.Errorf("Expected fresh hit=%v, got %q (hit=%v)", tt.wantHits, data, ok)
+			}
+			if _, ok := c.Get("old"); ok {
+				t.Error("Expected expired entry to miss")
+			}
+			data, ok = c.GetStale("old")
+			if ok != tt.wantHits || (ok && string(data) != "stale") {
+				t.Errorf("Expected stale hit=%v, got %q (hit=%v)", tt.wantHits, data, ok)
+			}
+		})
+	}
+}
+
+func TestMemoryCopiesValues(t *testing.T) {
+	m := NewMemory()
+	value := []byte("value")
+	if err := m.Set("key", value, time.Hour); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	value[0] = 'X'
+	if data, _ := m.Get("key"); string(data) != "value" {
+		t.Errorf("Expected stored copy to be unaffected, got %q", data)
+	}
+}
+
+func TestUnknownBackend(t *testing.T) {
+	if _, err := New("redis", ""); err == nil {
+		t.Error("Expected error for unknown backend")
+	}
+}
@@ -0,0 +1,116 @@
+package category
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// Other is assigned to apps no rule matches
+const Other = "other"
+
+//go:embed roles.json
+var defaultRulesJSON []byte
+
+// Pattern assigns a category to app IDs matching a regular expression
+type Pattern struct {
+	Pattern  string `json:"pattern"`
+	Category string `json:"category"`
+	re       *regexp.Regexp
+}
+
+// Rules maps apps to Bluefin roles. Rules are checked in field order and the
+// first match wins.
+type Rules struct {
+	Comment           string            `json:"comment,omitempty"`
+	Overrides         map[string]string `json:"overrides"`         // Exact app ID
+	IDPatterns        []Pattern         `json:"idPatterns"`        // App ID regular expressions
+	AppSets           map[string]string `json:"appSets"`           // "core"/"dx"
+	FlathubCategories map[string]string `json:"flathubCategories"` // First matching Flathub category wins
+	PackageTypes      map[string]string `json:"packageTypes"`      // "flatpak", "homebrew", "os"
+}
+
+// Load returns the default rules, with the rules in path (if any) layered on
+// top: map entries replace defaults and ID patterns are checked before defaults.
+func Load(path string) (*Rules, error) {
+	var rules Rules
+	if err := json.Unmarshal(defaultRulesJSON, &rules); err != nil {
+		return nil, fmt.Errorf("parse default rules: %w", err)
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read rules: %w", err)
+		}
+		var custom Rules
+		if err := json.Unmarshal(data, &custom); err != nil {
+			return nil, fmt.Errorf("parse rules %s: %w", path, err)
+		}
+		rules.merge(custom)
+	}
+
+	for i := range rules.IDPatterns {
+		re, err := regexp.Compile(rules.IDPatterns[i].Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("compile pattern %q: %w", rules.IDPatterns[i].Pattern, err)
+		}
+		rules.IDPatterns[i].re = re
+	}
+
+	return &rules, nil
+}
+
+func (r *Rules) merge(custom Rules) {
+	r.Overrides = mergeMap(r.Overrides, custom.Overrides)
+	r.AppSets = mergeMap(r.AppSets, custom.AppSets)
+	r.FlathubCategories = mergeMap(r.FlathubCategories, custom.FlathubCategories)
+	r.PackageTypes = mergeMap(r.PackageTypes, custom.PackageTypes)
+	r.IDPatterns = append(custom.IDPatterns, r.IDPatterns...)
+}
+
+func mergeMap(base, custom map[string]string) map[string]string {
+	if base == nil {
+		base = make(map[string]string)
+	}
+	for k, v := range custom {
+		base[k] = v
+	}
+	return base
+}
+
+// Assign returns the Bluefin category for an app
+func (r *Rules) Assign(app *models.App) string {
+	if category, ok := r.Overrides[app.ID]; ok {
+		return category
+	}
+	for _, p := range r.IDPatterns {
+		if p.re != nil && p.re.MatchString(app.ID) {
+			return p.Category
+		}
+	}
+	if category, ok := r.AppSets[app.AppSet]; ok {
+		return category
+	}
+	for _, flathubCategory := range app.Categories {
+		if category, ok := r.FlathubCategories[flathubCategory]; ok {
+			return category
+		}
+	}
+	if category, ok := r.PackageTypes[app.PackageType]; ok {
+		return category
+	}
+	return Other
+}
+
+// Apply sets BluefinCategory on every app
+func (r *Rules) Apply(apps []models.App) []models.App {
+	for i := range apps {
+		apps[i].BluefinCategory = r.Assign(&apps[i])
+	}
+	return apps
+}
@@ -0,0 +1,65 @@
+package category
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestAssign(t *testing.T) {
+	rules, err := Load("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		app  models.App
+		want string
+	}{
+		{name: "override", app: models.App{ID: "org.mozilla.firefox", Categories: []string{"Development"}}, want: "internet"},
+		{name: "id pattern", app: models.App{ID: "com.valvesoftware.Steam"}, want: "gaming"},
+		{name: "dx app set", app: models.App{ID: "org.example.Tool", AppSet: "dx", Categories: []string{"Office"}}, want: "developer"},
+		{name: "flathub category", app: models.App{ID: "org.gnome.Loupe", AppSet: "core", Categories: []string{"Graphics", "Viewer"}}, want: "creativity"},
+		{name: "package type", app: models.App{ID: "homebrew-gh", PackageType: "homebrew"}, want: "developer"},
+		{name: "no match", app: models.App{ID: "org.example.Mystery", PackageType: "flatpak"}, want: Other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules.Assign(&tt.app); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roles.json")
+	custom := `{
+  "overrides": {"org.mozilla.firefox": "browsers"},
+  "idPatterns": [{"pattern": "^org\\.example\\.", "category": "examples"}]
+}`
+	if err := os.WriteFile(path, []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	apps := rules.Apply([]models.App{
+		{ID: "org.mozilla.firefox"},
+		{ID: "org.example.Mystery"},
+		{ID: "com.valvesoftware.Steam"},
+	})
+	want := []string{"browsers", "examples", "gaming"}
+	for i, app := range apps {
+		if app.BluefinCategory != want[i] {
+			t.Errorf("Expected %s to be %q, got %q", app.ID, want[i], app.BluefinCategory)
+		}
+	}
+}
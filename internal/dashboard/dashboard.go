@@ -0,0 +1,109 @@
+// Package dashboard builds the ordered index the dashboard uses as its
+// single entrypoint: OS streams first, then apps grouped by set and type
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// Group keys, in display order
+const (
+	GroupOS       = "os"
+	GroupCore     = "core"
+	GroupDX       = "dx"
+	GroupHomebrew = "homebrew"
+	GroupOther    = "other" // Flatpaks outside the core and dx sets
+)
+
+// groupOrder lists every group with its heading, in display order
+var groupOrder = []struct{ key, title string }{
+	{GroupOS, "Bluefin OS"},
+	{GroupCore, "Core Apps"},
+	{GroupDX, "Developer Apps"},
+	{GroupHomebrew, "Homebrew Packages"},
+	{GroupOther, "Other Apps"},
+}
+
+// Entry is one app in a group
+type Entry struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	PackageType string `json:"packageType"`
+	Category    string `json:"category,omitempty"`
+}
+
+// Group is one section of the dashboard
+type Group struct {
+	Key   string  `json:"key"`
+	Title string  `json:"title"`
+	Apps  []Entry `json:"apps"`
+}
+
+// Index is the dashboard index written by WriteIndex
+type Index struct {
+	Groups []Group `json:"groups"`
+}
+
+// groupKey returns the group an app is shown in
+func groupKey(app models.App) string {
+	switch {
+	case app.PackageType == "os":
+		return GroupOS
+	case app.PackageType == "homebrew":
+		return GroupHomebrew
+	case app.AppSet == GroupCore:
+		return GroupCore
+	case app.AppSet == GroupDX:
+		return GroupDX
+	}
+	return GroupOther
+}
+
+// BuildIndex groups apps in display order, each group sorted by name then
+// ID. Empty groups are left out.
+func BuildIndex(apps []models.App) Index {
+	byGroup := make(map[string][]Entry)
+	for _, app := range apps {
+		entry := Entry{ID: app.ID, Name: app.Name, PackageType: app.PackageType}
+		if len(app.Categories) > 0 {
+			entry.Category = app.Categories[0]
+		}
+		key := groupKey(app)
+		byGroup[key] = append(byGroup[key], entry)
+	}
+
+	index := Index{Groups: []Group{}}
+	for _, g := range groupOrder {
+		entries := byGroup[g.key]
+		if len(entries) == 0 {
+			continue
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			a, b := strings.ToLower(entries[i].Name), strings.ToLower(entries[j].Name)
+			if a != b {
+				return a < b
+			}
+			return entries[i].ID < entries[j].ID
+		})
+		index.Groups = append(index.Groups, Group{Key: g.key, Title: g.title, Apps: entries})
+	}
+	return index
+}
+
+// WriteIndex writes the dashboard index of apps to path as indented JSON
+func WriteIndex(apps []models.App, path string) error {
+	data, err := json.MarshalIndent(BuildIndex(apps), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode dashboard index: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestBuildIndexOrder(t *testing.T) {
+	apps := []models.App{
+		{ID: "homebrew-gh", Name: "gh", PackageType: "homebrew"},
+		{ID: "com.visualstudio.code", Name: "Visual Studio Code", PackageType: "flatpak", AppSet: "dx"},
+		{ID: "org.gnome.Loupe", Name: "Loupe", PackageType: "flatpak", AppSet: "core", Categories: []string{"Graphics"}},
+		{ID: "bluefin-lts", Name: "Bluefin LTS", PackageType: "os"},
+		{ID: "com.example.Extra", Name: "Extra", PackageType: "flatpak"},
+		{ID: "org.mozilla.firefox", Name: "Firefox", PackageType: "flatpak", AppSet: "core"},
+		{ID: "homebrew-bat", Name: "bat", PackageType: "homebrew"},
+		{ID: "bluefin-stable", Name: "Bluefin", PackageType: "os"},
+		{ID: "io.podman_desktop.PodmanDesktop", Name: "Podman Desktop", PackageType: "flatpak", AppSet: "dx"},
+	}
+
+	index := BuildIndex(apps)
+
+	var got [][]string
+	var keys []string
+	for _, group := range index.Groups {
+		keys = append(keys, group.Key)
+		var ids []string
+		for _, entry := range group.Apps {
+			ids = append(ids, entry.ID)
+		}
+		got = append(got, ids)
+	}
+
+	wantKeys := []string{GroupOS, GroupCore, GroupDX, GroupHomebrew, GroupOther}
+	if !reflect.DeepEqual(keys, wantKeys) {
+		t.Errorf("Expected groups %v, got %v", wantKeys, keys)
+	}
+	want := [][]string{
+		{"bluefin-stable", "bluefin-lts"},
+		{"org.mozilla.firefox", "org.gnome.Loupe"},
+		{"io.podman_desktop.PodmanDesktop", "com.visualstudio.code"},
+		{"homebrew-bat", "homebrew-gh"},
+		{"com.example.Extra"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected order %v, got %v", want, got)
+	}
+	if category := index.Groups[1].Apps[1].Category; category != "Graphics" {
+		t.Errorf("Expected Loupe category Graphics, got %q", category)
+	}
+}
+
+func TestWriteIndexSkipsEmptyGroups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	apps := []models.App{{ID: "homebrew-gh", Name: "gh", PackageType: "homebrew"}}
+	if err := WriteIndex(apps, path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatalf("Index is not valid JSON: %v", err)
+	}
+	if len(index.Groups) != 1 || index.Groups[0].Key != GroupHomebrew {
+		t.Errorf("Expected only the homebrew group, got %+v", index.Groups)
+	}
+}
@@ -0,0 +1,65 @@
+// Package dates decides what release converters do with a release whose date
+// is missing or can't be parsed
+package dates
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policy is what happens to an undated release
+type Policy string
+
+// Fallback policies
+const (
+	PolicyNow  Policy = "now"  // Date it now, so it sorts newest
+	PolicyZero Policy = "zero" // Leave the date zero, so it sorts oldest
+	PolicySkip Policy = "skip" // Drop the release
+)
+
+// DefaultPolicy keeps undated releases without making them look newest
+const DefaultPolicy = PolicyZero
+
+// Policies returns the accepted policy names
+func Policies() []string {
+	return []string{string(PolicyNow), string(PolicyZero), string(PolicySkip)}
+}
+
+// ParsePolicy validates a policy name
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case PolicyNow, PolicyZero, PolicySkip:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown date fallback %q: must be one of %s", s, strings.Join(Policies(), ", "))
+}
+
+var (
+	policyMu sync.RWMutex
+	policy   = DefaultPolicy
+)
+
+// SetFallback sets the policy used by Fallback
+func SetFallback(p Policy) {
+	policyMu.Lock()
+	defer policyMu.Unlock()
+	policy = p
+}
+
+// Fallback returns the date to use for an undated release under the current
+// policy. keep is false when the release should be dropped.
+func Fallback() (date time.Time, keep bool) {
+	policyMu.RLock()
+	p := policy
+	policyMu.RUnlock()
+
+	switch p {
+	case PolicyNow:
+		return time.Now().UTC(), true
+	case PolicySkip:
+		return time.Time{}, false
+	}
+	return time.Time{}, true
+}
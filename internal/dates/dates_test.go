@@ -0,0 +1,36 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFallback(t *testing.T) {
+	defer SetFallback(DefaultPolicy)
+
+	SetFallback(PolicyNow)
+	if date, keep := Fallback(); !keep || time.Since(date) > time.Minute || date.Location() != time.UTC {
+		t.Errorf("Expected the current UTC time, got %s (keep=%v)", date, keep)
+	}
+
+	SetFallback(PolicyZero)
+	if date, keep := Fallback(); !keep || !date.IsZero() {
+		t.Errorf("Expected a zero date, got %s (keep=%v)", date, keep)
+	}
+
+	SetFallback(PolicySkip)
+	if _, keep := Fallback(); keep {
+		t.Error("Expected skip to drop the release")
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	for _, name := range Policies() {
+		if p, err := ParsePolicy(name); err != nil || string(p) != name {
+			t.Errorf("Expected %q to parse, got %q (%v)", name, p, err)
+		}
+	}
+	if _, err := ParsePolicy("newest"); err == nil {
+		t.Error("Expected error for unknown policy")
+	}
+}
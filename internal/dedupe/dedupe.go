@@ -0,0 +1,149 @@
+// Package dedupe finds apps that are likely the same software listed under
+// two IDs (a beta branch, a migrated ID), and can merge them
+package dedupe
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/version"
+)
+
+// Pair is two apps that are likely the same software listed under two IDs
+type Pair struct {
+	IDs        [2]string `json:"ids"` // Kept app first when merged
+	Name       string    `json:"name"`
+	Developer  string    `json:"developer,omitempty"`
+	Confidence float64   `json:"confidence"`
+	Reason     string    `json:"reason"`
+	Merged     bool      `json:"merged"`
+}
+
+var (
+	// nonAlnumRe matches everything normalizeName drops
+	nonAlnumRe = regexp.MustCompile(`[^a-z0-9]+`)
+	// channelWordRe matches release channel qualifiers in app names
+	channelWordRe = regexp.MustCompile(`(?i)\b(beta|nightly|preview|devel|development|canary|unstable)\b`)
+)
+
+// normalizeName lowercases a name and drops punctuation and spacing
+func normalizeName(name string) string {
+	return nonAlnumRe.ReplaceAllString(strings.ToLower(name), "")
+}
+
+// Find flags apps of the same package type whose names match
+// once normalized. Matching developers are required unless one is unknown,
+// which lowers confidence; apps named the same by different developers are
+// distinct software and never flagged.
+func Find(apps []models.App) []Pair {
+	var found []Pair
+	for i := range apps {
+		for j := i + 1; j < len(apps); j++ {
+			a, b := apps[i], apps[j]
+			if a.PackageType != b.PackageType || a.Name == "" || b.Name == "" {
+				continue
+			}
+
+			var confidence float64
+			var reason string
+			switch {
+			case normalizeName(a.Name) == normalizeName(b.Name):
+				confidence, reason = 1.0, "same name"
+			case normalizeName(channelWordRe.ReplaceAllString(a.Name, "")) == normalizeName(channelWordRe.ReplaceAllString(b.Name, "")):
+				confidence, reason = 0.9, "same name apart from release channel"
+			default:
+				continue
+			}
+
+			developer := a.DeveloperName
+			switch {
+			case a.DeveloperName != "" && b.DeveloperName != "":
+				if !strings.EqualFold(a.DeveloperName, b.DeveloperName) {
+					continue
+				}
+				reason += ", same developer"
+			default:
+				confidence -= 0.3
+				reason += ", developer unknown"
+				if developer == "" {
+					developer = b.DeveloperName
+				}
+			}
+
+			found = append(found, Pair{
+				IDs:        [2]string{a.ID, b.ID},
+				Name:       a.Name,
+				Developer:  developer,
+				Confidence: confidence,
+				Reason:     reason,
+			})
+		}
+	}
+	return found
+}
+
+// Merge folds each flagged pair at or above threshold into one
+// app: the one with more releases (the earlier listed on a tie) keeps its
+// metadata and gains the other's releases for versions it lacks.
+func Merge(apps []models.App, found []Pair, threshold float64) []models.App {
+	index := make(map[string]int, len(apps))
+	for i, app := range apps {
+		index[app.ID] = i
+	}
+
+	removed := make(map[string]bool)
+	for k := range found {
+		d := &found[k]
+		if d.Confidence < threshold || removed[d.IDs[0]] || removed[d.IDs[1]] {
+			continue
+		}
+
+		keep, drop := &apps[index[d.IDs[0]]], apps[index[d.IDs[1]]]
+		if len(drop.Releases) > len(keep.Releases) {
+			keep, drop = &apps[index[d.IDs[1]]], apps[index[d.IDs[0]]]
+			d.IDs = [2]string{d.IDs[1], d.IDs[0]}
+		}
+
+		versions := make(map[string]bool, len(keep.Releases))
+		for _, release := range keep.Releases {
+			versions[version.Key(release.Version)] = true
+		}
+		for _, release := range drop.Releases {
+			if !versions[version.Key(release.Version)] {
+				keep.Releases = append(keep.Releases, release)
+			}
+		}
+
+		removed[drop.ID] = true
+		d.Merged = true
+		log.Printf("🔗 Merged duplicate %s into %s (%s)", drop.ID, keep.ID, d.Reason)
+	}
+
+	kept := apps[:0]
+	for _, app := range apps {
+		if !removed[app.ID] {
+			kept = append(kept, app)
+		}
+	}
+	return kept
+}
+
+// WriteReport writes flagged duplicates as indented JSON
+func WriteReport(found []Pair, path string) error {
+	if found == nil {
+		found = []Pair{}
+	}
+	data, err := json.MarshalIndent(found, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode duplicates: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("write duplicates: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package dedupe
+
+import (
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestFind(t *testing.T) {
+	apps := []models.App{
+		{ID: "org.gnome.Loupe", Name: "Loupe", DeveloperName: "The GNOME Project", PackageType: "flatpak"},
+		{ID: "org.gnome.Loupe.Devel", Name: "Loupe (Devel)", DeveloperName: "The GNOME Project", PackageType: "flatpak"},
+		{ID: "com.example.OldNotes", Name: "Quick-Notes", DeveloperName: "Example", PackageType: "flatpak"},
+		{ID: "com.example.QuickNotes", Name: "quick notes", PackageType: "flatpak"},
+		{ID: "org.other.Notes", Name: "Notes", DeveloperName: "Other", PackageType: "flatpak"},
+		{ID: "io.example.Notes", Name: "Notes", DeveloperName: "Someone Else", PackageType: "flatpak"},
+		{ID: "loupe", Name: "Loupe", DeveloperName: "The GNOME Project", PackageType: "homebrew"},
+	}
+
+	found := Find(apps)
+	want := []Pair{
+		{IDs: [2]string{"org.gnome.Loupe", "org.gnome.Loupe.Devel"}, Confidence: 0.9},
+		{IDs: [2]string{"com.example.OldNotes", "com.example.QuickNotes"}, Confidence: 0.7},
+	}
+	if len(found) != len(want) {
+		t.Fatalf("Expected %d duplicates, got %+v", len(want), found)
+	}
+	for i, w := range want {
+		if found[i].IDs != w.IDs || found[i].Confidence != w.Confidence {
+			t.Errorf("Duplicate %d: expected %v at %.1f, got %v at %.1f (%s)", i, w.IDs, w.Confidence, found[i].IDs, found[i].Confidence, found[i].Reason)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	apps := []models.App{
+		{ID: "org.gnome.Loupe.Devel", Name: "Loupe (Devel)", Releases: []models.Release{{Version: "49.0"}, {Version: "50.beta"}}},
+		{ID: "org.gnome.Loupe", Name: "Loupe", Releases: []models.Release{{Version: "49.0"}, {Version: "48.2"}, {Version: "48.1"}}},
+		{ID: "com.example.A", Name: "A"},
+		{ID: "com.example.B", Name: "A"},
+	}
+	found := []Pair{
+		{IDs: [2]string{"org.gnome.Loupe.Devel", "org.gnome.Loupe"}, Confidence: 0.9},
+		{IDs: [2]string{"com.example.A", "com.example.B"}, Confidence: 0.7},
+	}
+
+	merged := Merge(apps, found, 0.8)
+
+	if len(merged) != 3 {
+		t.Fatalf("Expected 3 apps after merging one pair, got %d", len(merged))
+	}
+	if merged[0].ID != "org.gnome.Loupe" {
+		t.Fatalf("Expected the app with more releases to be kept, got %s", merged[0].ID)
+	}
+	if len(merged[0].Releases) != 4 {
+		t.Errorf("Expected releases unioned by version, got %+v", merged[0].Releases)
+	}
+	if !found[0].Merged || found[0].IDs[0] != "org.gnome.Loupe" {
+		t.Errorf("Expected the pair to be reported merged with the kept app first, got %+v", found[0])
+	}
+	if found[1].Merged {
+		t.Error("Expected a pair below the threshold to be left alone")
+	}
+}
@@ -0,0 +1,227 @@
+// Package diff compares the apps of two runs and renders what changed
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// Output formats accepted by Write
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+	FormatText     = "text"
+)
+
+// Formats lists the output formats accepted by Write
+func Formats() []string {
+	return []string{FormatJSON, FormatMarkdown, FormatText}
+}
+
+// ValidFormat reports whether format is accepted by Write
+func ValidFormat(format string) bool {
+	for _, f := range Formats() {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Change is one app that was added, updated, or removed between runs
+type Change struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	FromVersion string `json:"fromVersion,omitempty"` // Empty for added apps
+	ToVersion   string `json:"toVersion,omitempty"`   // Empty for removed apps
+}
+
+// DiffResult lists the apps that changed between two runs, each sorted by ID
+type DiffResult struct {
+	Added   []Change `json:"added"`
+	Updated []Change `json:"updated"`
+	Removed []Change `json:"removed"`
+}
+
+// Empty reports whether nothing changed
+func (d DiffResult) Empty() bool {
+	return len(d.Added) == 0 && len(d.Updated) == 0 && len(d.Removed) == 0
+}
+
+// Compare diffs two app lists by ID. An app is updated when its current
+// release version differs from the previous run's.
+func Compare(previous, current []models.App) DiffResult {
+	before := make(map[string]models.App, len(previous))
+	for _, app := range previous {
+		before[app.ID] = app
+	}
+
+	result := DiffResult{Added: []Change{}, Updated: []Change{}, Removed: []Change{}}
+	seen := make(map[string]bool, len(current))
+	for _, app := range current {
+		seen[app.ID] = true
+		old, ok := before[app.ID]
+		switch {
+		case !ok:
+			result.Added = append(result.Added, Change{ID: app.ID, Name: app.Name, ToVersion: app.Version})
+		case old.Version != app.Version:
+			result.Updated = append(result.Updated, Change{ID: app.ID, Name: app.Name, FromVersion: old.Version, ToVersion: app.Version})
+		}
+	}
+	for _, app := range previous {
+		if !seen[app.ID] {
+			result.Removed = append(result.Removed, Change{ID: app.ID, Name: app.Name, FromVersion: app.Version})
+		}
+	}
+
+	for _, changes := range [][]Change{result.Added, result.Updated, result.Removed} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	}
+	return result
+}
+
+// MarkNew flags the releases of current apps whose version wasn't in the
+// previous run, comparing versions case-insensitively and without a "v"
+// prefix, and clears the flag on every other release. Apps missing from
+// previous are newly tracked rather than newly released and stay unflagged.
+// Returns the number of releases flagged.
+func MarkNew(previous, current []models.App) int {
+	known := make(map[string]map[string]bool, len(previous))
+	for _, app := range previous {
+		versions := make(map[string]bool, len(app.Releases))
+		for _, release := range app.Releases {
+			versions[normalizeVersion(release.Version)] = true
+		}
+		known[app.ID] = versions
+	}
+
+	marked := 0
+	for i := range current {
+		versions, ok := known[current[i].ID]
+		for j := range current[i].Releases {
+			release := &current[i].Releases[j]
+			release.IsNew = ok && !versions[normalizeVersion(release.Version)]
+			if release.IsNew {
+				marked++
+			}
+		}
+	}
+	return marked
+}
+
+// normalizeVersion makes "v1.2.0" and "1.2.0" compare equal
+func normalizeVersion(v string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(v)), "v")
+}
+
+// LoadApps reads the apps from a previous run's JSON output
+func LoadApps(path string) ([]models.App, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var output models.OutputData
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return output.Apps, nil
+}
+
+// Write renders d to w in format: indented JSON, Markdown suitable for a PR
+// comment or changelog, or plain text for logs
+func Write(w io.Writer, d DiffResult, format string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(d)
+	case FormatMarkdown:
+		_, err := io.WriteString(w, markdown(d))
+		return err
+	case FormatText:
+		_, err := io.WriteString(w, text(d))
+		return err
+	default:
+		return fmt.Errorf("unknown diff format %q: must be one of %s", format, strings.Join(Formats(), ", "))
+	}
+}
+
+// WriteFile renders d in format to path
+func WriteFile(d DiffResult, path, format string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create diff: %w", err)
+	}
+	if err := Write(f, d, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// counts summarizes d in one line, e.g. "2 added, 1 updated, 0 removed"
+func counts(d DiffResult) string {
+	return fmt.Sprintf("%d added, %d updated, %d removed", len(d.Added), len(d.Updated), len(d.Removed))
+}
+
+func markdown(d DiffResult) string {
+	var b strings.Builder
+	b.WriteString("## Release changes\n\n")
+	if d.Empty() {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "**%s**\n", counts(d))
+
+	sections := []struct {
+		title   string
+		changes []Change
+		version func(Change) string
+	}{
+		{"Added", d.Added, func(c Change) string { return c.ToVersion }},
+		{"Updated", d.Updated, func(c Change) string { return versionOrNone(c.FromVersion) + " → " + versionOrNone(c.ToVersion) }},
+		{"Removed", d.Removed, func(c Change) string { return c.FromVersion }},
+	}
+	for _, section := range sections {
+		if len(section.changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", section.title)
+		for _, c := range section.changes {
+			fmt.Fprintf(&b, "- **%s** (`%s`)", c.Name, c.ID)
+			if version := section.version(c); version != "" {
+				b.WriteString(" " + version)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+func text(d DiffResult) string {
+	var b strings.Builder
+	b.WriteString(counts(d) + "\n")
+	for _, c := range d.Added {
+		b.WriteString(strings.TrimSpace("+ "+c.ID+" "+c.ToVersion) + "\n")
+	}
+	for _, c := range d.Updated {
+		fmt.Fprintf(&b, "~ %s %s -> %s\n", c.ID, versionOrNone(c.FromVersion), versionOrNone(c.ToVersion))
+	}
+	for _, c := range d.Removed {
+		b.WriteString(strings.TrimSpace("- "+c.ID+" "+c.FromVersion) + "\n")
+	}
+	return b.String()
+}
+
+func versionOrNone(version string) string {
+	if version == "" {
+		return "(none)"
+	}
+	return version
+}
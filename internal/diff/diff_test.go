@@ -0,0 +1,158 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestCompare(t *testing.T) {
+	previous := []models.App{
+		{ID: "org.mozilla.firefox", Name: "Firefox", Version: "140.0"},
+		{ID: "org.gnome.Loupe", Name: "Loupe", Version: "49.1"},
+		{ID: "com.example.Old", Name: "Old", Version: "1.0"},
+	}
+	current := []models.App{
+		{ID: "org.mozilla.firefox", Name: "Firefox", Version: "141.0"},
+		{ID: "org.gnome.Loupe", Name: "Loupe", Version: "49.1"},
+		{ID: "io.github.New", Name: "New", Version: "0.1.0"},
+	}
+
+	want := DiffResult{
+		Added:   []Change{{ID: "io.github.New", Name: "New", ToVersion: "0.1.0"}},
+		Updated: []Change{{ID: "org.mozilla.firefox", Name: "Firefox", FromVersion: "140.0", ToVersion: "141.0"}},
+		Removed: []Change{{ID: "com.example.Old", Name: "Old", FromVersion: "1.0"}},
+	}
+	if got := Compare(previous, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if !Compare(current, current).Empty() {
+		t.Error("Expected no changes comparing a run with itself")
+	}
+}
+
+func TestWriteFormats(t *testing.T) {
+	result := DiffResult{
+		Added:   []Change{{ID: "io.github.New", Name: "New", ToVersion: "0.1.0"}},
+		Updated: []Change{{ID: "org.mozilla.firefox", Name: "Firefox", FromVersion: "140.0", ToVersion: "141.0"}},
+		Removed: []Change{{ID: "com.example.Old", Name: "Old", FromVersion: "1.0"}},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: FormatMarkdown,
+			want: "## Release changes\n\n" +
+				"**1 added, 1 updated, 1 removed**\n\n" +
+				"### Added\n\n- **New** (`io.github.New`) 0.1.0\n\n" +
+				"### Updated\n\n- **Firefox** (`org.mozilla.firefox`) 140.0 → 141.0\n\n" +
+				"### Removed\n\n- **Old** (`com.example.Old`) 1.0\n",
+		},
+		{
+			format: FormatText,
+			want: "1 added, 1 updated, 1 removed\n" +
+				"+ io.github.New 0.1.0\n" +
+				"~ org.mozilla.firefox 140.0 -> 141.0\n" +
+				"- com.example.Old 1.0\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, result, tt.format); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+
+	t.Run(FormatJSON, func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, result, FormatJSON); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var decoded DiffResult
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Output is not valid JSON: %v", err)
+		}
+		if !reflect.DeepEqual(decoded, result) {
+			t.Errorf("Expected %+v, got %+v", result, decoded)
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		if err := Write(&bytes.Buffer{}, result, "yaml"); err == nil {
+			t.Error("Expected an error for an unknown format")
+		}
+	})
+
+	t.Run("empty markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, DiffResult{}, FormatMarkdown); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if want := "## Release changes\n\nNo changes.\n"; buf.String() != want {
+			t.Errorf("Expected %q, got %q", want, buf.String())
+		}
+	})
+}
+
+func TestMarkNewAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "apps.json")
+	firstRun := &models.OutputData{Apps: []models.App{
+		{ID: "org.mozilla.firefox", Releases: []models.Release{{Version: "140.0"}}},
+	}}
+	if err := firstRun.WriteJSON(path); err != nil {
+		t.Fatalf("Failed to write first run: %v", err)
+	}
+
+	// Second run: Firefox ships 141.0 (tagged with a "v" this time) and a
+	// newly tracked app appears
+	previous, err := LoadApps(path)
+	if err != nil {
+		t.Fatalf("Failed to load first run: %v", err)
+	}
+	secondRun := &models.OutputData{Apps: []models.App{
+		{ID: "org.mozilla.firefox", Releases: []models.Release{{Version: "v141.0"}, {Version: "v140.0"}}},
+		{ID: "io.github.New", Releases: []models.Release{{Version: "0.1.0"}}},
+	}}
+	if marked := MarkNew(previous, secondRun.Apps); marked != 1 {
+		t.Errorf("Expected 1 release flagged, got %d", marked)
+	}
+	if !secondRun.Apps[0].Releases[0].IsNew {
+		t.Error("Expected v141.0 to be flagged new")
+	}
+	if secondRun.Apps[0].Releases[1].IsNew {
+		t.Error("Expected v140.0 to match 140.0 from the previous run")
+	}
+	if secondRun.Apps[1].Releases[0].IsNew {
+		t.Error("Expected releases of a newly tracked app to stay unflagged")
+	}
+	if err := secondRun.WriteJSON(path); err != nil {
+		t.Fatalf("Failed to write second run: %v", err)
+	}
+
+	// Third run: nothing shipped, so the flag carried in the data clears
+	previous, err = LoadApps(path)
+	if err != nil {
+		t.Fatalf("Failed to load second run: %v", err)
+	}
+	thirdRun, err := LoadApps(path)
+	if err != nil {
+		t.Fatalf("Failed to load second run: %v", err)
+	}
+	if marked := MarkNew(previous, thirdRun); marked != 0 {
+		t.Errorf("Expected no releases flagged, got %d", marked)
+	}
+	if thirdRun[0].Releases[0].IsNew {
+		t.Error("Expected v141.0 to no longer be new")
+	}
+}
@@ -0,0 +1,32 @@
+package diff
+
+import "log"
+
+// Sink is a consumer of a run's diff. The -diff-output file is the only one
+// today; there are no notifiers yet, but a webhook would be another Sink.
+type Sink struct {
+	Name    string
+	Publish func(DiffResult) error
+}
+
+// FileSink writes the diff in format to path
+func FileSink(path, format string) Sink {
+	return Sink{
+		Name: "diff " + path,
+		Publish: func(d DiffResult) error {
+			return WriteFile(d, path, format)
+		},
+	}
+}
+
+// Publish hands one computed diff to every sink, so they all report the same
+// changes. A failing sink doesn't stop the others.
+func Publish(d DiffResult, sinks []Sink) {
+	for _, sink := range sinks {
+		if err := sink.Publish(d); err != nil {
+			log.Printf("⚠️  Failed to publish diff to %s: %v", sink.Name, err)
+		} else {
+			log.Printf("🔀 Diff published to %s", sink.Name)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestPublishSharesOneResult(t *testing.T) {
+	dir := t.TempDir()
+	previousPath := filepath.Join(dir, "apps.json")
+	previous := &models.OutputData{Apps: []models.App{
+		{ID: "org.mozilla.firefox", Name: "Firefox", Version: "140.0"},
+		{ID: "com.example.Old", Name: "Old", Version: "1.0"},
+	}}
+	if err := previous.WriteJSON(previousPath); err != nil {
+		t.Fatalf("Failed to write previous output: %v", err)
+	}
+
+	previousApps, err := LoadApps(previousPath)
+	if err != nil {
+		t.Fatalf("Failed to read previous output: %v", err)
+	}
+	result := Compare(previousApps, []models.App{
+		{ID: "org.mozilla.firefox", Name: "Firefox", Version: "141.0"},
+		{ID: "io.github.New", Name: "New", Version: "0.1.0"},
+	})
+
+	diffPath := filepath.Join(dir, "diff.json")
+	var notified []DiffResult
+	Publish(result, []Sink{
+		{Name: "failing", Publish: func(DiffResult) error { return errors.New("unreachable") }},
+		FileSink(diffPath, FormatJSON),
+		{Name: "recorder", Publish: func(d DiffResult) error {
+			notified = append(notified, d)
+			return nil
+		}},
+	})
+
+	data, err := os.ReadFile(diffPath)
+	if err != nil {
+		t.Fatalf("Failed to read diff: %v", err)
+	}
+	var written DiffResult
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("Diff is not valid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(written, result) {
+		t.Errorf("Expected diff file %+v, got %+v", result, written)
+	}
+	if len(notified) != 1 || !reflect.DeepEqual(notified[0], result) {
+		t.Errorf("Expected the recorder to receive the same diff once, got %+v", notified)
+	}
+	if len(result.Added) != 1 || len(result.Updated) != 1 || len(result.Removed) != 1 {
+		t.Errorf("Expected one of each change, got %+v", result)
+	}
+}
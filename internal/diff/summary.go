@@ -0,0 +1,100 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// RunStats are the run totals a step summary reports
+type RunStats struct {
+	AppsTotal         int
+	AppsWithChangelog int
+	TotalReleases     int
+	Duration          string
+	ErrorsBySource    map[string]int // Failed fetches per source
+}
+
+// AppendStepSummary appends a Markdown run report to path (GitHub Actions'
+// $GITHUB_STEP_SUMMARY), listing totals, the newest releases, and fetch warnings
+func AppendStepSummary(path string, apps []models.App, stats RunStats) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open step summary: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(stepSummary(apps, stats)); err != nil {
+		return fmt.Errorf("write step summary: %w", err)
+	}
+	return nil
+}
+
+func stepSummary(apps []models.App, stats RunStats) string {
+	type newest struct {
+		app     string
+		release models.Release
+	}
+	var releases []newest
+	for _, app := range apps {
+		for _, release := range app.Releases {
+			releases = append(releases, newest{app: app.Name, release: release})
+		}
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].release.Date.After(releases[j].release.Date)
+	})
+	if len(releases) > 5 {
+		releases = releases[:5]
+	}
+
+	var b strings.Builder
+	b.WriteString("## Bluefin Releases\n\n")
+	fmt.Fprintf(&b, "| Apps | With changelogs | Releases | Duration |\n|---|---|---|---|\n| %d | %d | %d | %s |\n\n",
+		stats.AppsTotal, stats.AppsWithChangelog, stats.TotalReleases, tableCell(stats.Duration))
+
+	if len(releases) > 0 {
+		b.WriteString("### Newest releases\n\n| App | Version | Date |\n|---|---|---|\n")
+		for _, r := range releases {
+			version := tableCell(r.release.Version)
+			if r.release.URL != "" {
+				version = fmt.Sprintf("[%s](%s)", linkTextEscaper.Replace(version), linkURLEscaper.Replace(r.release.URL))
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", tableCell(r.app), version, r.release.Date.UTC().Format("2006-01-02"))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(stats.ErrorsBySource) > 0 {
+		sources := make([]string, 0, len(stats.ErrorsBySource))
+		for source := range stats.ErrorsBySource {
+			sources = append(sources, source)
+		}
+		sort.Strings(sources)
+
+		b.WriteString("### ⚠️ Warnings\n\n")
+		for _, source := range sources {
+			fmt.Fprintf(&b, "- %s: %d failed fetch(es)\n", source, stats.ErrorsBySource[source])
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+var (
+	// linkTextEscaper escapes brackets that would end a link's text early
+	linkTextEscaper = strings.NewReplacer("[", `\[`, "]", `\]`)
+	// linkURLEscaper percent-encodes what would end a link's URL or its table cell
+	linkURLEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "|", "%7C", "\n", "", "\r", "")
+)
+
+// tableCell keeps upstream text on one line of its Markdown table cell:
+// line breaks collapse to spaces and pipes are escaped
+func tableCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "|", `\|`)
+}
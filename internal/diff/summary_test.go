@@ -0,0 +1,65 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestAppendStepSummary(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 2, d, 0, 0, 0, 0, time.UTC) }
+	var releases []models.Release
+	for d := 1; d <= 7; d++ {
+		releases = append(releases, models.Release{Version: fmt.Sprintf("1.%d", d), Date: day(d)})
+	}
+	apps := []models.App{{Name: "Example", Releases: releases}}
+	stats := RunStats{AppsTotal: 1, AppsWithChangelog: 1, TotalReleases: 7, Duration: "3s", ErrorsBySource: map[string]int{"homebrew": 1}}
+
+	path := filepath.Join(t.TempDir(), "step-summary.md")
+	if err := os.WriteFile(path, []byte("existing\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendStepSummary(path, apps, stats); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+
+	for _, want := range []string{"existing\n", "| 1 | 1 | 7 | 3s |", "| Example | 1.7 | 2026-02-07 |", "| Example | 1.3 |", "- homebrew: 1 failed fetch(es)"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "| Example | 1.2 |") {
+		t.Error("Expected only the 5 newest releases")
+	}
+}
+
+func TestStepSummaryEscapesCells(t *testing.T) {
+	apps := []models.App{{
+		Name: "Pipe | App\nName",
+		Releases: []models.Release{
+			{Version: "1.0|beta\n2", Date: time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)},
+			{Version: "[0.9]", URL: "https://example.com/release (0.9)|x", Date: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}}
+
+	content := stepSummary(apps, RunStats{})
+	for _, want := range []string{
+		`| Pipe \| App Name | 1.0\|beta 2 | 2026-02-02 |`,
+		`| Pipe \| App Name | [\[0.9\]](https://example.com/release%20%280.9%29%7Cx) | 2026-02-01 |`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", want, content)
+		}
+	}
+}
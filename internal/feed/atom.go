@@ -0,0 +1,137 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// atomIDPrefix namespaces feed and entry IDs so they stay stable across runs
+const atomIDPrefix = "tag:projectbluefin.io,2025:releases/"
+
+// AtomFeed represents an Atom 1.0 feed
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []AtomLink  `xml:"link"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+// AtomLink is a link element of a feed or entry
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+// AtomEntry is a single release in a feed
+type AtomEntry struct {
+	Title   string       `xml:"title"`
+	ID      string       `xml:"id"`
+	Updated string       `xml:"updated"`
+	Links   []AtomLink   `xml:"link,omitempty"`
+	Content *AtomContent `xml:"content,omitempty"`
+}
+
+// AtomContent holds rendered release notes
+type AtomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// unsafeFileChars matches characters not allowed in feed file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// AppFeedFile returns the file name of an app's feed, derived from its ID
+func AppFeedFile(appID string) string {
+	return unsafeFileChars.ReplaceAllString(appID, "_") + ".atom"
+}
+
+// WriteAppFeeds writes one Atom feed per app with releases into dir, keeping
+// at most maxReleases of the newest releases per feed (0 keeps all). It
+// returns how many feeds were written.
+func WriteAppFeeds(apps []models.App, dir string, maxReleases int) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("create feeds directory: %w", err)
+	}
+
+	written := 0
+	for _, app := range apps {
+		if len(app.Releases) == 0 {
+			continue
+		}
+		if err := writeXML(appFeed(app, maxReleases), filepath.Join(dir, AppFeedFile(app.ID))); err != nil {
+			return written, fmt.Errorf("write feed for %s: %w", app.ID, err)
+		}
+		written++
+	}
+	return written, nil
+}
+
+// appFeed builds the Atom feed of an app's releases, newest first
+func appFeed(app models.App, maxReleases int) AtomFeed {
+	releases := append([]models.Release(nil), app.Releases...)
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].Date.After(releases[j].Date)
+	})
+	if maxReleases > 0 && len(releases) > maxReleases {
+		releases = releases[:maxReleases]
+	}
+
+	name := app.Name
+	if name == "" {
+		name = app.ID
+	}
+
+	doc := AtomFeed{
+		Title:   fmt.Sprintf("%s releases", name),
+		ID:      atomIDPrefix + app.ID,
+		Updated: atomTime(releases[0].Date),
+	}
+	if link := appLink(app); link != "" {
+		doc.Links = append(doc.Links, AtomLink{Href: link, Rel: "alternate"})
+	}
+
+	for _, release := range releases {
+		title := release.Title
+		if title == "" {
+			title = fmt.Sprintf("%s %s", name, release.Version)
+		}
+		entry := AtomEntry{
+			Title:   title,
+			ID:      atomIDPrefix + app.ID + "/" + release.Version,
+			Updated: atomTime(release.Date),
+		}
+		if release.URL != "" {
+			entry.Links = append(entry.Links, AtomLink{Href: release.URL, Rel: "alternate"})
+		}
+		if release.Description != "" {
+			entry.Content = &AtomContent{Type: "html", Body: release.Description}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return doc
+}
+
+// appLink picks the page an app's feed links back to
+func appLink(app models.App) string {
+	if app.FlathubURL != "" {
+		return app.FlathubURL
+	}
+	if app.SourceRepo != nil {
+		return app.SourceRepo.URL
+	}
+	return ""
+}
+
+// atomTime formats a time as RFC 3339 in UTC, as Atom requires
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,73 @@
+package feed
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestWriteAppFeeds(t *testing.T) {
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	apps := []models.App{
+		{
+			ID:         "org.gnome.Loupe",
+			Name:       "Loupe",
+			FlathubURL: "https://flathub.org/apps/org.gnome.Loupe",
+			Releases: []models.Release{
+				{Version: "48.0", Date: day.AddDate(0, 0, -30), Title: "Loupe 48.0"},
+				{Version: "49.1", Date: day, Title: "Loupe 49.1", URL: "https://gitlab.gnome.org/GNOME/loupe/-/releases/49.1", Description: "<p>Fixes <em>zoom</em></p>"},
+				{Version: "49.0", Date: day.AddDate(0, 0, -7), Title: "Loupe 49.0"},
+			},
+		},
+		{ID: "org.example.Quiet", Name: "Quiet"},
+		{ID: "github.com/cli/cli", Name: "cli", Releases: []models.Release{{Version: "v2.0.0", Date: day}}},
+	}
+
+	dir := filepath.Join(t.TempDir(), "feeds")
+	written, err := WriteAppFeeds(apps, dir, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if written != 2 {
+		t.Errorf("Expected 2 feeds (apps without releases skipped), got %d", written)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "org.example.Quiet.atom")); !os.IsNotExist(err) {
+		t.Error("Expected no feed for an app without releases")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "github.com_cli_cli.atom")); err != nil {
+		t.Errorf("Expected a feed with a file-safe name: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "org.gnome.Loupe.atom"))
+	if err != nil {
+		t.Fatalf("Failed to read feed: %v", err)
+	}
+	var doc AtomFeed
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Feed is not valid Atom XML: %v", err)
+	}
+
+	if doc.ID != "tag:projectbluefin.io,2025:releases/org.gnome.Loupe" || doc.Updated != "2026-03-01T12:00:00Z" {
+		t.Errorf("Unexpected feed id/updated: %q %q", doc.ID, doc.Updated)
+	}
+	if len(doc.Entries) != 2 {
+		t.Fatalf("Expected 2 entries (max releases), got %d", len(doc.Entries))
+	}
+	first := doc.Entries[0]
+	if first.ID != "tag:projectbluefin.io,2025:releases/org.gnome.Loupe/49.1" || first.Title != "Loupe 49.1" {
+		t.Errorf("Expected newest release first with a stable ID, got %q %q", first.ID, first.Title)
+	}
+	if first.Content == nil || first.Content.Type != "html" || first.Content.Body != "<p>Fixes <em>zoom</em></p>" {
+		t.Errorf("Expected HTML content, got %+v", first.Content)
+	}
+	if len(first.Links) != 1 || first.Links[0].Href != "https://gitlab.gnome.org/GNOME/loupe/-/releases/49.1" {
+		t.Errorf("Expected release link, got %+v", first.Links)
+	}
+	if doc.Entries[1].Title != "Loupe 49.0" {
+		t.Errorf("Expected 49.0 second, got %q", doc.Entries[1].Title)
+	}
+}
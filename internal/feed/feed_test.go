@@ -0,0 +1,73 @@
+package feed
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestWriteOPML(t *testing.T) {
+	apps := []models.App{
+		{
+			ID:         "org.gnome.Builder",
+			Name:       "Builder",
+			Categories: []string{"Development"},
+			SourceRepo: &models.SourceRepo{Type: "gitlab", URL: "https://gitlab.gnome.org/GNOME/gnome-builder", Owner: "GNOME", Repo: "gnome-builder"},
+		},
+		{
+			ID:         "homebrew-gh",
+			Name:       "gh",
+			SourceRepo: &models.SourceRepo{Type: "github", URL: "https://github.com/cli/cli", Owner: "cli", Repo: "cli"},
+		},
+		{
+			ID:         "org.example.NoRepo",
+			Name:       "No Repo",
+			Categories: []string{"Utility"},
+		},
+		{
+			ID:         "org.example.Other",
+			Name:       "Other Forge",
+			SourceRepo: &models.SourceRepo{Type: "other", URL: "https://example.org"},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "feeds.opml")
+	if err := WriteOPML(apps, path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read OPML: %v", err)
+	}
+
+	var doc OPML
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("OPML is not valid XML: %v", err)
+	}
+
+	want := map[string]string{
+		"Builder": "https://gitlab.gnome.org/GNOME/gnome-builder/-/tags?format=atom",
+		"gh":      "https://github.com/cli/cli/releases.atom",
+	}
+	wantCategory := map[string]string{"Builder": "Development", "gh": "Uncategorized"}
+
+	found := 0
+	for _, group := range doc.Body.Outlines {
+		for _, outline := range group.Outlines {
+			found++
+			if outline.XMLURL != want[outline.Text] {
+				t.Errorf("Expected feed %q for %s, got %q", want[outline.Text], outline.Text, outline.XMLURL)
+			}
+			if group.Text != wantCategory[outline.Text] {
+				t.Errorf("Expected %s under %q, got %q", outline.Text, wantCategory[outline.Text], group.Text)
+			}
+		}
+	}
+	if found != len(want) {
+		t.Errorf("Expected %d feeds, got %d", len(want), found)
+	}
+}
@@ -0,0 +1,142 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// OPML represents an OPML 2.0 document
+type OPML struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    OPMLHead `xml:"head"`
+	Body    OPMLBody `xml:"body"`
+}
+
+// OPMLHead contains document metadata
+type OPMLHead struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+// OPMLBody contains the top-level outlines
+type OPMLBody struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is either a category folder (with children) or a feed entry
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Outlines []Outline `xml:"outline,omitempty"`
+}
+
+// ReleaseFeedURL returns the upstream Atom feed for an app's releases,
+// or an empty string when the source repository has no usable feed
+func ReleaseFeedURL(app models.App) string {
+	repo := app.SourceRepo
+	if repo == nil || repo.Owner == "" || repo.Repo == "" {
+		return ""
+	}
+
+	switch repo.Type {
+	case "github":
+		return fmt.Sprintf("https://github.com/%s/%s/releases.atom", repo.Owner, repo.Repo)
+	case "gitlab":
+		// GitLab has no releases feed, but tags are exposed as Atom
+		return fmt.Sprintf("%s/-/tags?format=atom", strings.TrimSuffix(strings.TrimSuffix(repo.URL, "/"), ".git"))
+	default:
+		return ""
+	}
+}
+
+// WriteOPML writes an OPML file listing the release feed of every app that has one,
+// grouped by the app's first category
+func WriteOPML(apps []models.App, path string) error {
+	groups := make(map[string][]Outline)
+	for _, app := range apps {
+		feedURL := ReleaseFeedURL(app)
+		if feedURL == "" {
+			continue
+		}
+
+		category := "Uncategorized"
+		if len(app.Categories) > 0 && app.Categories[0] != "" {
+			category = app.Categories[0]
+		}
+
+		name := app.Name
+		if name == "" {
+			name = app.ID
+		}
+
+		groups[category] = append(groups[category], Outline{
+			Text:    name,
+			Title:   name,
+			Type:    "rss",
+			XMLURL:  feedURL,
+			HTMLURL: app.SourceRepo.URL,
+		})
+	}
+
+	categories := make([]string, 0, len(groups))
+	for category := range groups {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	doc := OPML{
+		Version: "2.0",
+		Head: OPMLHead{
+			Title:       "Bluefin Releases",
+			DateCreated: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+	for _, category := range categories {
+		feeds := groups[category]
+		sort.Slice(feeds, func(i, j int) bool {
+			return strings.ToLower(feeds[i].Text) < strings.ToLower(feeds[j].Text)
+		})
+		doc.Body.Outlines = append(doc.Body.Outlines, Outline{
+			Text:     category,
+			Title:    category,
+			Outlines: feeds,
+		})
+	}
+
+	return writeXML(doc, path)
+}
+
+// writeXML writes an XML document with a header to path
+func writeXML(doc interface{}, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(xml.Header); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	encoder := xml.NewEncoder(file)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("encode XML: %w", err)
+	}
+
+	if _, err := file.WriteString("\n"); err != nil {
+		return fmt.Errorf("write trailer: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// ReleaseStream is every app's releases flattened into one newest-first list,
+// for a global "latest activity" feed
+type ReleaseStream struct {
+	Metadata models.Metadata `json:"metadata"`
+	Releases []ReleaseEntry  `json:"releases"`
+}
+
+// ReleaseEntry is a release along with the app it belongs to
+type ReleaseEntry struct {
+	AppID           string    `json:"appId"`
+	AppName         string    `json:"appName"`
+	Version         string    `json:"version"`
+	Date            time.Time `json:"date"`
+	Title           string    `json:"title"`
+	DescriptionHTML string    `json:"descriptionHTML,omitempty"`
+	URL             string    `json:"url,omitempty"`
+	Type            string    `json:"type"`
+}
+
+// FlattenReleases returns the releases of all apps sorted newest first,
+// keeping at most limit of them (0 keeps all). Apps sharing a source repo
+// list the same upstream release; only the first of those is kept.
+func FlattenReleases(apps []models.App, limit int) []ReleaseEntry {
+	type keyed struct {
+		entry ReleaseEntry
+		key   string
+	}
+	var all []keyed
+	for _, app := range apps {
+		for _, release := range app.Releases {
+			all = append(all, keyed{
+				entry: ReleaseEntry{
+					AppID:           app.ID,
+					AppName:         app.Name,
+					Version:         release.Version,
+					Date:            release.Date,
+					Title:           release.Title,
+					DescriptionHTML: release.Description,
+					URL:             release.URL,
+					Type:            release.Type,
+				},
+				key: releaseKey(app, release),
+			})
+		}
+	}
+
+	// Ties are broken by app ID and version so the file is stable across runs
+	sort.SliceStable(all, func(i, j int) bool {
+		a, b := all[i].entry, all[j].entry
+		if !a.Date.Equal(b.Date) {
+			return a.Date.After(b.Date)
+		}
+		if a.AppID != b.AppID {
+			return a.AppID < b.AppID
+		}
+		return a.Version > b.Version
+	})
+
+	entries := []ReleaseEntry{}
+	seen := make(map[string]bool)
+	for _, k := range all {
+		if seen[k.key] {
+			continue
+		}
+		seen[k.key] = true
+		entries = append(entries, k.entry)
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
+// releaseKey identifies an upstream release: by source repo and version when
+// the app has a repo, so apps built from the same repo share keys, otherwise
+// by app ID and version
+func releaseKey(app models.App, release models.Release) string {
+	version := strings.TrimPrefix(strings.ToLower(release.Version), "v")
+	if app.SourceRepo != nil && app.SourceRepo.URL != "" {
+		repo := strings.TrimSuffix(strings.TrimSuffix(strings.ToLower(app.SourceRepo.URL), "/"), ".git")
+		return repo + "@" + version
+	}
+	return app.ID + "@" + version
+}
+
+// WriteReleases writes the flattened releases of output to path as
+// pretty-printed JSON, keeping at most limit (0 keeps all). It returns how
+// many releases were written.
+func WriteReleases(output *models.OutputData, path string, limit int) (int, error) {
+	stream := ReleaseStream{
+		Metadata: output.Metadata,
+		Releases: FlattenReleases(output.Apps, limit),
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false) // Keep URLs readable, as in the main output
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(stream); err != nil {
+		return 0, fmt.Errorf("encode releases: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return 0, fmt.Errorf("write releases: %w", err)
+	}
+	return len(stream.Releases), nil
+}
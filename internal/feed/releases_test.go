@@ -0,0 +1,129 @@
+package feed
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestWriteReleases(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	output := &models.OutputData{
+		Metadata: models.Metadata{SchemaVersion: "1.0"},
+		Apps: []models.App{
+			{
+				ID:   "org.mozilla.firefox",
+				Name: "Firefox",
+				Releases: []models.Release{
+					{Version: "141.0", Date: day(5), Title: "Firefox 141.0", Description: "<p>Vertical tabs</p>", URL: "https://example.com/141", Type: "github-release"},
+					{Version: "140.0", Date: day(1), Title: "Firefox 140.0", Type: "github-release"},
+				},
+			},
+			{ID: "org.example.Quiet", Name: "Quiet"},
+			{
+				ID:       "org.gnome.Loupe",
+				Name:     "Loupe",
+				Releases: []models.Release{{Version: "49.1", Date: day(3), Title: "49.1", Type: "appstream"}},
+			},
+			{
+				ID:       "com.example.Same",
+				Name:     "Same Day",
+				Releases: []models.Release{{Version: "2.0", Date: day(5), Type: "github-release"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  []string // appId@version, newest first
+	}{
+		{name: "all releases", want: []string{"com.example.Same@2.0", "org.mozilla.firefox@141.0", "org.gnome.Loupe@49.1", "org.mozilla.firefox@140.0"}},
+		{name: "limited", limit: 2, want: []string{"com.example.Same@2.0", "org.mozilla.firefox@141.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "releases.json")
+			written, err := WriteReleases(output, path, tt.limit)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if written != len(tt.want) {
+				t.Errorf("Expected %d releases written, got %d", len(tt.want), written)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read releases: %v", err)
+			}
+			var stream ReleaseStream
+			if err := json.Unmarshal(data, &stream); err != nil {
+				t.Fatalf("Releases file is not valid JSON: %v", err)
+			}
+			if stream.Metadata.SchemaVersion != "1.0" {
+				t.Errorf("Expected metadata to be carried over, got %+v", stream.Metadata)
+			}
+			if len(stream.Releases) != len(tt.want) {
+				t.Fatalf("Expected %d releases, got %d", len(tt.want), len(stream.Releases))
+			}
+			for i, entry := range stream.Releases {
+				if got := entry.AppID + "@" + entry.Version; got != tt.want[i] {
+					t.Errorf("Release %d: expected %s, got %s", i, tt.want[i], got)
+				}
+			}
+
+			first := stream.Releases[1]
+			if first.AppName != "Firefox" || first.Title != "Firefox 141.0" || first.DescriptionHTML != "<p>Vertical tabs</p>" ||
+				first.URL != "https://example.com/141" || first.Type != "github-release" || !first.Date.Equal(day(5)) {
+				t.Errorf("Unexpected flattened release: %+v", first)
+			}
+		})
+	}
+}
+
+func TestFlattenReleasesDedupesSharedRepos(t *testing.T) {
+	date := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	repo := &models.SourceRepo{Type: "github", URL: "https://github.com/obsproject/obs-studio", Owner: "obsproject", Repo: "obs-studio"}
+	apps := []models.App{
+		{
+			ID:         "com.obsproject.Studio",
+			Name:       "OBS Studio",
+			SourceRepo: repo,
+			Releases:   []models.Release{{Version: "31.1.0", Date: date, Type: "github-release"}},
+		},
+		{
+			ID:         "homebrew-obs",
+			Name:       "obs",
+			SourceRepo: &models.SourceRepo{Type: "github", URL: "https://github.com/obsproject/obs-studio/", Owner: "obsproject", Repo: "obs-studio"},
+			Releases: []models.Release{
+				{Version: "v31.1.0", Date: date, Type: "github-release"},
+				{Version: "31.0.0", Date: date.AddDate(0, -1, 0), Type: "github-release"},
+			},
+		},
+		{
+			ID:       "org.example.NoRepo",
+			Releases: []models.Release{{Version: "31.1.0", Date: date, Type: "appstream"}},
+		},
+	}
+
+	entries := FlattenReleases(apps, 0)
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.AppID+"@"+entry.Version)
+	}
+	want := []string{"com.obsproject.Studio@31.1.0", "org.example.NoRepo@31.1.0", "homebrew-obs@31.0.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// Per-app data is untouched
+	if len(apps[1].Releases) != 2 {
+		t.Errorf("Expected the app's own releases to be kept, got %d", len(apps[1].Releases))
+	}
+}
@@ -0,0 +1,68 @@
+package feed
+
+import (
+	"encoding/xml"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// URLSet represents a sitemaps.org 0.9 sitemap
+type URLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+// SitemapURL is a single page in a sitemap
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// AppPageURL returns the dashboard page of an app under baseURL
+func AppPageURL(baseURL, appID string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/" + url.PathEscape(appID)
+}
+
+// WriteSitemap writes a sitemap listing each app's page under baseURL, with
+// lastmod set to its newest release so crawlers revisit recently updated apps
+// first. Apps without releases have no detail page and are skipped unless
+// includeAll is set. It returns how many URLs were written.
+func WriteSitemap(apps []models.App, baseURL, path string, includeAll bool) (int, error) {
+	doc := URLSet{}
+	for _, app := range apps {
+		newest := newestRelease(app.Releases)
+		if newest.IsZero() && !includeAll {
+			continue
+		}
+
+		entry := SitemapURL{Loc: AppPageURL(baseURL, app.ID)}
+		if !newest.IsZero() {
+			entry.LastMod = newest.UTC().Format("2006-01-02")
+		}
+		doc.URLs = append(doc.URLs, entry)
+	}
+
+	sort.Slice(doc.URLs, func(i, j int) bool {
+		return doc.URLs[i].Loc < doc.URLs[j].Loc
+	})
+
+	if err := writeXML(doc, path); err != nil {
+		return 0, err
+	}
+	return len(doc.URLs), nil
+}
+
+// newestRelease returns the date of the most recent release, or zero
+func newestRelease(releases []models.Release) time.Time {
+	var newest time.Time
+	for _, release := range releases {
+		if release.Date.After(newest) {
+			newest = release.Date
+		}
+	}
+	return newest
+}
@@ -0,0 +1,82 @@
+package feed
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestWriteSitemap(t *testing.T) {
+	day := time.Date(2026, 3, 1, 23, 30, 0, 0, time.FixedZone("PST", -8*3600))
+	apps := []models.App{
+		{
+			ID: "org.gnome.Loupe",
+			Releases: []models.Release{
+				{Version: "48.0", Date: day.AddDate(0, 0, -30)},
+				{Version: "49.1", Date: day},
+			},
+		},
+		{ID: "org.example.Quiet"},
+		{ID: "github.com/cli/cli", Releases: []models.Release{{Version: "v2.0.0", Date: day.AddDate(0, 0, -2)}}},
+	}
+
+	tests := []struct {
+		name       string
+		includeAll bool
+		want       []SitemapURL
+	}{
+		{
+			name: "skips apps without releases",
+			want: []SitemapURL{
+				{Loc: "https://example.org/apps/github.com%2Fcli%2Fcli", LastMod: "2026-02-28"},
+				{Loc: "https://example.org/apps/org.gnome.Loupe", LastMod: "2026-03-02"},
+			},
+		},
+		{
+			name:       "include all",
+			includeAll: true,
+			want: []SitemapURL{
+				{Loc: "https://example.org/apps/github.com%2Fcli%2Fcli", LastMod: "2026-02-28"},
+				{Loc: "https://example.org/apps/org.example.Quiet"},
+				{Loc: "https://example.org/apps/org.gnome.Loupe", LastMod: "2026-03-02"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sitemap.xml")
+			written, err := WriteSitemap(apps, "https://example.org/apps/", path, tt.includeAll)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if written != len(tt.want) {
+				t.Errorf("Expected %d URLs written, got %d", len(tt.want), written)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read sitemap: %v", err)
+			}
+			var doc URLSet
+			if err := xml.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("Sitemap is not valid XML: %v", err)
+			}
+			if doc.XMLName.Space != "http://www.sitemaps.org/schemas/sitemap/0.9" {
+				t.Errorf("Expected sitemaps.org namespace, got %q", doc.XMLName.Space)
+			}
+			if len(doc.URLs) != len(tt.want) {
+				t.Fatalf("Expected %d URLs, got %+v", len(tt.want), doc.URLs)
+			}
+			for i, want := range tt.want {
+				if doc.URLs[i] != want {
+					t.Errorf("URL %d: expected %+v, got %+v", i, want, doc.URLs[i])
+				}
+			}
+		})
+	}
+}
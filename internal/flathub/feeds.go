@@ -0,0 +1,88 @@
+package flathub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// DefaultFeed is the collection legacy mode lists apps from
+const DefaultFeed = "recently-updated"
+
+// feeds maps -feed names to their Flathub collection endpoints
+var feeds = map[string]string{
+	"recently-updated": "collection/recently-updated",
+	"recently-added":   "collection/recently-added",
+	"popular":          "collection/popular",
+	"trending":         "collection/trending",
+}
+
+// rankedFeeds are the collections ordered by rank, whose apps get a TrendingRank
+var rankedFeeds = map[string]bool{
+	"popular":  true,
+	"trending": true,
+}
+
+func currentFeed() string {
+	if feed := currentOptions().Feed; feed != "" {
+		return feed
+	}
+	return DefaultFeed
+}
+
+// Feeds returns the supported feed names, sorted
+func Feeds() []string {
+	names := make([]string, 0, len(feeds))
+	for name := range feeds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidFeed reports whether name is a supported feed
+func ValidFeed(name string) bool {
+	_, ok := feeds[name]
+	return ok
+}
+
+// FetchFeed fetches the apps listed in a Flathub feed (see Feeds)
+func FetchFeed(name string) ([]models.FlathubApp, error) {
+	return fetchFeed(context.Background(), name)
+}
+
+// fetchFeed is FetchFeed bounded by ctx
+func fetchFeed(ctx context.Context, name string) ([]models.FlathubApp, error) {
+	path, ok := feeds[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown feed %q", name)
+	}
+	return fetchCollection(ctx, path)
+}
+
+// decodeCollection parses a collection response. Search-backed collections
+// wrap apps in {"hits": [...]}; others return a bare array of apps or of app IDs.
+func decodeCollection(body []byte) ([]models.FlathubApp, error) {
+	var collectionResp models.FlathubCollectionResponse
+	if err := json.Unmarshal(body, &collectionResp); err == nil {
+		return collectionResp.Hits, nil
+	}
+
+	var apps []models.FlathubApp
+	if err := json.Unmarshal(body, &apps); err == nil {
+		return apps, nil
+	}
+
+	var appIDs []string
+	if err := json.Unmarshal(body, &appIDs); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	apps = make([]models.FlathubApp, len(appIDs))
+	for i, appID := range appIDs {
+		apps[i] = models.FlathubApp{AppID: appID}
+	}
+	return apps, nil
+}
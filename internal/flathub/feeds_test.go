@@ -0,0 +1,111 @@
+package flathub
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// feedFixtures are collection responses keyed by API path
+var feedFixtures = map[string]string{
+	"/api/v2/collection/recently-updated": `{"hits": [{"app_id": "org.gnome.Loupe", "name": "Image Viewer", "updated_at": 1772323200}], "totalHits": 1, "hitsPerPage": 250, "page": 1, "totalPages": 1}`,
+	"/api/v2/collection/recently-added":   `{"hits": [{"app_id": "io.github.new.App", "name": "New App", "added_at": 1772236800}], "totalHits": 1}`,
+	"/api/v2/collection/popular":          `{"hits": [{"app_id": "org.mozilla.firefox", "name": "Firefox", "installs_last_month": 500000}], "totalHits": 1}`,
+	"/api/v2/collection/trending":         `[{"app_id": "com.valvesoftware.Steam", "name": "Steam", "favorites_count": 1200}]`,
+}
+
+func TestFetchFeed(t *testing.T) {
+	defer httpx.SetBaseTransport(httpxtest.Bodies(feedFixtures))()
+
+	tests := []struct {
+		feed  string
+		appID string
+		want  func(app models.FlathubApp) bool
+	}{
+		{feed: "recently-updated", appID: "org.gnome.Loupe", want: func(app models.FlathubApp) bool { return app.UpdatedAt == 1772323200 }},
+		{feed: "recently-added", appID: "io.github.new.App", want: func(app models.FlathubApp) bool { return app.AddedAt == 1772236800 }},
+		{feed: "popular", appID: "org.mozilla.firefox", want: func(app models.FlathubApp) bool { return app.InstallsLastMonth == 500000 }},
+		{feed: "trending", appID: "com.valvesoftware.Steam", want: func(app models.FlathubApp) bool { return app.Name == "Steam" && app.FavoritesCount == 1200 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.feed, func(t *testing.T) {
+			apps, err := FetchFeed(tt.feed)
+			if err != nil {
+				t.Fatalf("FetchFeed failed: %v", err)
+			}
+			if len(apps) != 1 || apps[0].AppID != tt.appID {
+				t.Fatalf("Expected [%s], got %+v", tt.appID, apps)
+			}
+			if !tt.want(apps[0]) {
+				t.Errorf("Expected feed-specific fields to be parsed, got %+v", apps[0])
+			}
+		})
+	}
+
+	if _, err := FetchFeed("editors-choice"); err == nil {
+		t.Error("Expected an error for an unknown feed")
+	}
+}
+
+func TestDecodeCollectionAppIDs(t *testing.T) {
+	apps, err := decodeCollection([]byte(`["org.gnome.Loupe", "org.mozilla.firefox"]`))
+	if err != nil {
+		t.Fatalf("decodeCollection failed: %v", err)
+	}
+	if len(apps) != 2 || apps[0].AppID != "org.gnome.Loupe" || apps[1].AppID != "org.mozilla.firefox" {
+		t.Errorf("Expected apps from bare IDs, got %+v", apps)
+	}
+}
+
+func TestFetchAllAppsTrendingRank(t *testing.T) {
+	appIDs := []string{"com.valvesoftware.Steam", "org.mozilla.firefox", "org.gnome.Loupe", "com.spotify.Client"}
+	// Serve the apps as trending and answer their details in reverse order, so
+	// concurrent enrichment finishes out of order
+	hits := make([]string, len(appIDs))
+	responses := map[string]httpxtest.Response{"": {Body: `{"name": "App"}`}}
+	for i, id := range appIDs {
+		hits[i] = fmt.Sprintf(`{"app_id": %q}`, id)
+		responses["/"+id] = httpxtest.Response{Body: `{"name": "App"}`, Delay: time.Duration(len(appIDs)-i) * 5 * time.Millisecond}
+	}
+	responses["/api/v2/collection/trending"] = httpxtest.Response{Body: `{"hits": [` + strings.Join(hits, ",") + `]}`}
+	defer httpx.SetBaseTransport(&httpxtest.Transport{Responses: responses})()
+	defer Configure(Options{})
+
+	for _, budget := range []time.Duration{0, 5 * time.Second} {
+		t.Run(fmt.Sprintf("budget %s", budget), func(t *testing.T) {
+			Configure(Options{Feed: "trending", Budget: budget})
+			results, err := FetchAllApps()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(results.Apps) != len(appIDs) {
+				t.Fatalf("Expected %d apps, got %d", len(appIDs), len(results.Apps))
+			}
+			for i, app := range results.Apps {
+				if app.ID != appIDs[i] || app.TrendingRank != i+1 {
+					t.Errorf("Position %d: expected %s ranked %d, got %s ranked %d", i, appIDs[i], i+1, app.ID, app.TrendingRank)
+				}
+			}
+		})
+	}
+
+	t.Run("unranked feed", func(t *testing.T) {
+		Configure(Options{Feed: "recently-updated"})
+		defer httpx.SetBaseTransport(httpxtest.Bodies(feedFixtures))()
+		results, err := FetchAllApps()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, app := range results.Apps {
+			if app.TrendingRank != 0 {
+				t.Errorf("Expected no rank for %s, got %d", app.ID, app.TrendingRank)
+			}
+		}
+	})
+}
@@ -1,19 +1,25 @@
 package flathub
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	neturl "net/url"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/castrojo/bluefin-releases/internal/dates"
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/markdown"
 	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/skips"
 )
 
 const (
@@ -30,6 +36,11 @@ type SourceOverride struct {
 	Owner string `json:"owner"`
 	Repo  string `json:"repo"`
 	Notes string `json:"notes"`
+
+	// PreferredSource keeps only this source's releases for the app:
+	// "github", "gitlab", or "appstream". Empty uses the normal merge. An
+	// override may set just this and leave repository detection alone.
+	PreferredSource string `json:"preferredSource,omitempty"`
 }
 
 // SourceOverrides contains the full overrides mapping
@@ -38,6 +49,12 @@ type SourceOverrides struct {
 	Overrides map[string]SourceOverride `json:"overrides"`
 }
 
+// httpClient is shared by all Flathub API calls so they respect the global request limits
+var httpClient = httpx.NewClientWith(httpx.ClientOptions{
+	UserAgent:   httpx.DefaultUserAgent,
+	CacheSource: httpx.SourceFlathub,
+})
+
 var (
 	sourceOverrides     *SourceOverrides
 	sourceOverridesOnce sync.Once
@@ -58,80 +75,201 @@ func loadSourceOverrides() *SourceOverrides {
 	return sourceOverrides
 }
 
+// PreferredSources maps app IDs to the release source their override prefers
+func PreferredSources() map[string]string {
+	preferred := make(map[string]string)
+	for appID, override := range loadSourceOverrides().Overrides {
+		if override.PreferredSource != "" {
+			preferred[appID] = override.PreferredSource
+		}
+	}
+	return preferred
+}
+
 // FetchAllApps fetches apps and enriches with details.
 // If appIDs is provided, fetches only those specific apps.
-// Otherwise, fetches recently updated apps.
-// Follows the pattern of feeds.FetchAllFeeds from firehose
-func FetchAllApps(appIDs ...string) *models.FetchResults {
-	var (
-		wg      sync.WaitGroup
-		mu      sync.Mutex
-		allApps []models.App
-	)
-
+// Otherwise, fetches the apps in the configured feed (recently updated by default).
+// Follows the pattern of feeds.FetchAllFeeds from firehose.
+// Returns an error only when the feed itself can't be listed; apps whose
+// details fail are still returned with base data.
+func FetchAllApps(appIDs ...string) (*models.FetchResults, error) {
 	var flathubApps []models.FlathubApp
 
+	// A time budget covers listing the apps as well as enriching them
+	start := time.Now()
+	ctx := context.Background()
+	budget := currentOptions().Budget
+	if budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, start.Add(budget))
+		defer cancel()
+	}
+
 	// Step 1: Fetch list of apps (either specific IDs or recently updated)
 	if len(appIDs) > 0 {
 		// Fetch specific app IDs
 		log.Printf("Fetching %d specific apps from Flathub...", len(appIDs))
+
+		// The details endpoint doesn't expose when an app was first published,
+		// so join against the recently-added collection to find new apps
+		addedAt := make(map[string]int64)
+		if recentlyAdded, err := fetchFeed(ctx, "recently-added"); err != nil {
+			log.Printf("⚠️  Failed to fetch recently added apps: %v", err)
+		} else {
+			for _, fa := range recentlyAdded {
+				addedAt[fa.AppID] = fa.AddedAt
+			}
+		}
+
 		for _, appID := range appIDs {
 			// Create a FlathubApp stub with just the ID
 			// The enrichApp function will fetch full details
 			flathubApps = append(flathubApps, models.FlathubApp{
-				AppID: appID,
+				AppID:   appID,
+				AddedAt: addedAt[appID],
 			})
 		}
 	} else {
-		// Fetch recently updated apps (original behavior)
-		log.Println("Fetching recently updated apps from Flathub...")
+		// Fetch the apps listed in the configured feed
+		feed := currentFeed()
+		log.Printf("Fetching %s apps from Flathub...", feed)
 		var err error
-		flathubApps, err = FetchRecentlyUpdated()
+		flathubApps, err = fetchFeed(ctx, feed)
 		if err != nil {
-			log.Fatalf("Failed to fetch apps: %v", err)
+			return nil, fmt.Errorf("fetch %s apps: %w", feed, err)
 		}
-		log.Printf("Fetched %d recently updated apps", len(flathubApps))
+		log.Printf("Fetched %d %s apps", len(flathubApps), feed)
 	}
 
 	// Step 2: Fetch details for each app in parallel
-	// Limit to 50 apps only for recently-updated to avoid timeouts
+	// Limit to 50 apps only for feeds to avoid timeouts
 	// For specific app IDs, fetch all of them
 	appsToFetch := flathubApps
 	if len(appIDs) == 0 && len(appsToFetch) > 50 {
-		// Only limit when fetching recently updated apps
+		// Only limit when fetching a feed
 		appsToFetch = appsToFetch[:50]
 		log.Printf("Limited to first 50 apps to avoid timeouts")
 	}
 
-	for _, flathubApp := range appsToFetch {
+	// Results are stored at each app's position in the feed, so concurrent
+	// enrichment doesn't reorder them
+	var allApps []models.App
+	if budget > 0 {
+		log.Printf("Enriching %d apps within a %s budget", len(appsToFetch), budget)
+		allApps = enrichWithinBudget(appsToFetch, start.Add(budget))
+	} else {
+		var wg sync.WaitGroup
+		allApps = make([]models.App, len(appsToFetch))
+		for i, flathubApp := range appsToFetch {
+			wg.Add(1)
+			go func(i int, fa models.FlathubApp) {
+				defer wg.Done()
+
+				appStart := time.Now()
+				app := enrichApp(fa)
+
+				log.Printf("✅ Processed %s in %s", app.ID, time.Since(appStart))
+
+				allApps[i] = app
+			}(i, flathubApp)
+		}
+
+		wg.Wait()
+	}
+
+	if len(appIDs) == 0 && rankedFeeds[currentFeed()] {
+		for i := range allApps {
+			allApps[i].TrendingRank = i + 1
+		}
+	}
+
+	return &models.FetchResults{
+		Apps: allApps,
+	}, nil
+}
+
+// budgetWorkers is how many apps are enriched at once under a time budget
+const budgetWorkers = 8
+
+// enrichWithinBudget enriches apps with a bounded worker pool, giving each
+// app a deadline from appDeadline so the whole batch finishes by deadline.
+// Apps are returned in input order.
+func enrichWithinBudget(flathubApps []models.FlathubApp, deadline time.Time) []models.App {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		next int
+	)
+	apps := make([]models.App, len(flathubApps))
+
+	for w := 0; w < budgetWorkers; w++ {
 		wg.Add(1)
-		go func(fa models.FlathubApp) {
+		go func() {
 			defer wg.Done()
+			for {
+				mu.Lock()
+				if next >= len(flathubApps) {
+					mu.Unlock()
+					return
+				}
+				i, fa := next, flathubApps[next]
+				pending := len(flathubApps) - next
+				next++
+				mu.Unlock()
 
-			appStart := time.Now()
-			app := enrichApp(fa)
+				appStart := time.Now()
+				ctx, cancel := context.WithDeadline(context.Background(), appDeadline(appStart, deadline, pending, budgetWorkers))
+				app := enrichAppContext(ctx, fa)
+				cancel()
 
-			log.Printf("✅ Processed %s in %s", app.ID, time.Since(appStart))
+				log.Printf("✅ Processed %s in %s", app.ID, time.Since(appStart))
 
-			mu.Lock()
-			allApps = append(allApps, app)
-			mu.Unlock()
-		}(flathubApp)
+				apps[i] = app
+			}
+		}()
 	}
 
 	wg.Wait()
+	return apps
+}
 
-	return &models.FetchResults{
-		Apps: allApps,
+// appDeadline splits the time left before deadline evenly across the rounds
+// of work still pending, so early apps can't use up the time later ones need.
+// Time an app doesn't use flows back to the apps after it.
+func appDeadline(now, deadline time.Time, pending, workers int) time.Time {
+	remaining := deadline.Sub(now)
+	if remaining <= 0 {
+		return now
 	}
+	rounds := (pending + workers - 1) / workers
+	return now.Add(remaining / time.Duration(rounds))
 }
 
 // enrichApp fetches details and enriches a single app
 func enrichApp(flathubApp models.FlathubApp) models.App {
+	return enrichAppContext(context.Background(), flathubApp)
+}
+
+// enrichAppContext is enrichApp bounded by ctx. Apps whose details can't be
+// fetched before ctx expires keep their base data and are flagged TimedOut.
+func enrichAppContext(ctx context.Context, flathubApp models.FlathubApp) models.App {
 	fetchedAt := time.Now().UTC()
 
 	// Fetch detailed information first (needed for apps with only ID)
-	details, err := FetchAppDetails(flathubApp.AppID)
+	details, err := fetchAppDetails(ctx, flathubApp.AppID)
+	if err != nil && ctx.Err() != nil {
+		log.Printf("⏱️  Out of time enriching %s, keeping base data", flathubApp.AppID)
+		return models.App{
+			ID:          flathubApp.AppID,
+			Name:        flathubApp.Name,
+			Summary:     flathubApp.Summary,
+			Icon:        flathubApp.Icon,
+			FlathubURL:  fmt.Sprintf("https://flathub.org/apps/%s", flathubApp.AppID),
+			FetchedAt:   fetchedAt,
+			PackageType: "flatpak",
+			TimedOut:    true,
+		}
+	}
 	if err != nil {
 		log.Printf("⚠️  Failed to fetch details for %s: %v", flathubApp.AppID, err)
 		// Return minimal app with just ID and URL
@@ -142,6 +280,15 @@ func enrichApp(flathubApp models.FlathubApp) models.App {
 		}
 	}
 
+	// A 404 for an app we only know by ID (the curated list) means Flathub no
+	// longer lists it. Apps that came from a collection feed are still listed,
+	// so a 404 there is just missing details.
+	eol := details == nil && flathubApp.Name == ""
+	if eol {
+		log.Printf("⚠️  %s is no longer listed on Flathub (EOL or removed)", flathubApp.AppID)
+		skips.Record(flathubApp.AppID, "flatpak", skips.ReasonNotFound, "no longer listed on Flathub")
+	}
+
 	// Use details to fill in missing data from collection API
 	name := flathubApp.Name
 	if name == "" && details != nil {
@@ -157,6 +304,8 @@ func enrichApp(flathubApp models.FlathubApp) models.App {
 	if description == "" && details != nil {
 		description = details.Description
 	}
+	// Appstream descriptions are markup; keep paragraphs and lists, drop anything unsafe
+	description = markdown.SanitizeHTML(description)
 
 	icon := flathubApp.Icon
 	if icon == "" && details != nil {
@@ -175,6 +324,11 @@ func enrichApp(flathubApp models.FlathubApp) models.App {
 		updatedAt = time.Unix(flathubApp.UpdatedAt, 0).UTC().Format(time.RFC3339)
 	}
 
+	addedAt := ""
+	if flathubApp.AddedAt > 0 {
+		addedAt = time.Unix(flathubApp.AddedAt, 0).UTC().Format(time.RFC3339)
+	}
+
 	// Build verification info
 	var verificationInfo *models.Verification
 	if flathubApp.VerificationVerified {
@@ -200,6 +354,7 @@ func enrichApp(flathubApp models.FlathubApp) models.App {
 		ProjectLicense:    flathubApp.ProjectLicense,
 		Categories:        categories,
 		UpdatedAt:         updatedAt,
+		AddedAt:           addedAt,
 		FlathubURL:        fmt.Sprintf("https://flathub.org/apps/%s", flathubApp.AppID),
 		FetchedAt:         fetchedAt,
 		InstallsLastMonth: flathubApp.InstallsLastMonth,
@@ -207,9 +362,14 @@ func enrichApp(flathubApp models.FlathubApp) models.App {
 		IsVerified:        flathubApp.VerificationVerified,
 		VerificationInfo:  verificationInfo,
 		PackageType:       "flatpak", // All apps from Flathub are Flatpaks
+		EOL:               eol,
 	}
 
 	if details != nil {
+		app.Homepage = details.URLs["homepage"]
+		app.ProjectGroup = strings.TrimSpace(details.ProjectGroup)
+		app.Runtime, app.Branch = parseBundle(details.Bundle)
+
 		// Extract source repository (with override support)
 		sourceRepo := ExtractSourceRepo(flathubApp.AppID, details)
 		if sourceRepo != nil {
@@ -222,15 +382,12 @@ func enrichApp(flathubApp models.FlathubApp) models.App {
 			// Set current version and release date from first release
 			app.Version = details.Releases[0].Version
 
-			// Parse timestamp for release date (prefer timestamp over date string)
-			if details.Releases[0].Timestamp != "" {
-				if ts, err := strconv.ParseInt(details.Releases[0].Timestamp, 10, 64); err == nil {
-					app.ReleaseDate = time.Unix(ts, 0).UTC().Format(time.RFC3339)
-				}
-			}
-			// Fall back to date string if timestamp parsing failed or was empty
-			if app.ReleaseDate == "" && details.Releases[0].Date != "" {
-				app.ReleaseDate = details.Releases[0].Date
+			// Parse release date (prefer timestamp over date string)
+			if date, ok := parseReleaseDate(details.Releases[0]); ok {
+				app.ReleaseDate = date.Format(time.RFC3339)
+			} else if details.Releases[0].Date != "" {
+				// Keep the raw date string if it couldn't be parsed
+				app.ReleaseDate = string(details.Releases[0].Date)
 			}
 		}
 	}
@@ -243,11 +400,26 @@ func enrichApp(flathubApp models.FlathubApp) models.App {
 
 // FetchRecentlyUpdated fetches the list of recently updated apps from Flathub (using JSON collection API)
 func FetchRecentlyUpdated() ([]models.FlathubApp, error) {
-	url := fmt.Sprintf("%s/collection/recently-updated", FlathubAPIBase)
+	return FetchFeed("recently-updated")
+}
+
+// FetchRecentlyAdded fetches the list of recently added apps from Flathub (using JSON collection API)
+func FetchRecentlyAdded() ([]models.FlathubApp, error) {
+	return FetchFeed("recently-added")
+}
 
-	resp, err := http.Get(url)
+// fetchCollection fetches a Flathub collection endpoint (e.g. "collection/recently-updated")
+func fetchCollection(ctx context.Context, path string) ([]models.FlathubApp, error) {
+	url := fmt.Sprintf("%s/%s", FlathubAPIBase, path)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("fetch recently updated: %w", err)
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", path, err)
 	}
 	defer resp.Body.Close()
 
@@ -260,19 +432,24 @@ func FetchRecentlyUpdated() ([]models.FlathubApp, error) {
 		return nil, fmt.Errorf("read response body: %w", err)
 	}
 
-	var collectionResp models.FlathubCollectionResponse
-	if err := json.Unmarshal(body, &collectionResp); err != nil {
-		return nil, fmt.Errorf("unmarshal response: %w", err)
-	}
-
-	return collectionResp.Hits, nil
+	return decodeCollection(body)
 }
 
 // FetchAppDetails fetches detailed information for a specific app
 func FetchAppDetails(appID string) (*models.FlathubAppDetails, error) {
+	return fetchAppDetails(context.Background(), appID)
+}
+
+// fetchAppDetails is FetchAppDetails bounded by ctx
+func fetchAppDetails(ctx context.Context, appID string) (*models.FlathubAppDetails, error) {
 	url := fmt.Sprintf("%s/appstream/%s", FlathubAPIBase, appID)
 
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("fetch app details: %w", err)
 	}
@@ -304,7 +481,7 @@ func FetchAppDetails(appID string) (*models.FlathubAppDetails, error) {
 func ExtractSourceRepo(appID string, details *models.FlathubAppDetails) *models.SourceRepo {
 	// Check overrides first
 	overrides := loadSourceOverrides()
-	if override, found := overrides.Overrides[appID]; found {
+	if override, found := overrides.Overrides[appID]; found && override.URL != "" {
 		log.Printf("Using source override for %s: %s", appID, override.URL)
 		return &models.SourceRepo{
 			Type:  override.Type,
@@ -342,8 +519,8 @@ func ExtractSourceRepo(appID string, details *models.FlathubAppDetails) *models.
 		return extractGitHubRepo(repoURL)
 	}
 
-	// Check if it's a GitLab URL (gitlab.com or self-hosted like gitlab.gnome.org)
-	if strings.Contains(repoURL, "gitlab") {
+	// Check if it's a GitLab URL (gitlab.com, self-hosted like gitlab.gnome.org, or invent.kde.org)
+	if strings.Contains(repoURL, "gitlab") || isGitLabHost(repoURL) {
 		return extractGitLabRepo(repoURL)
 	}
 
@@ -378,29 +555,51 @@ func extractGitHubRepo(url string) *models.SourceRepo {
 	}
 }
 
-// extractGitLabRepo extracts owner/repo from a GitLab URL (supports gitlab.com and self-hosted)
-func extractGitLabRepo(url string) *models.SourceRepo {
-	// Match gitlab.*/owner/repo patterns (supports gitlab.com, gitlab.gnome.org, etc.)
-	re := regexp.MustCompile(`gitlab\.[^/]+/([^/]+)/([^/\s?#]+)`)
-	matches := re.FindStringSubmatch(url)
+// gitlabHosts are GitLab instances whose hostname doesn't contain "gitlab"
+var gitlabHosts = []string{"invent.kde.org"}
 
-	if len(matches) < 3 {
-		return &models.SourceRepo{
-			Type: "gitlab",
-			URL:  url,
+// isGitLabHost reports whether a URL points at one of gitlabHosts
+func isGitLabHost(repoURL string) bool {
+	parsed, err := neturl.Parse(httpx.WithScheme(repoURL))
+	if err != nil {
+		return false
+	}
+	for _, host := range gitlabHosts {
+		if strings.EqualFold(parsed.Host, host) {
+			return true
 		}
 	}
+	return false
+}
 
-	owner := matches[1]
-	repo := strings.TrimSuffix(matches[2], ".git")
-	repo = strings.TrimSuffix(repo, "/") // Remove trailing slash if present
+// extractGitLabRepo extracts the host and project path from a GitLab URL
+// (supports gitlab.com and self-hosted instances). GitLab projects can sit in
+// nested groups, so Owner is the full namespace ("plasma-mobile/apps") and
+// Repo the project; page suffixes like "/-/releases" are dropped.
+func extractGitLabRepo(url string) *models.SourceRepo {
+	repo := &models.SourceRepo{
+		Type: "gitlab",
+		URL:  url,
+	}
 
-	return &models.SourceRepo{
-		Type:  "gitlab",
-		URL:   url,
-		Owner: owner,
-		Repo:  repo,
+	parsed, err := neturl.Parse(httpx.WithScheme(strings.TrimSpace(url)))
+	if err != nil || parsed.Host == "" {
+		return repo
+	}
+	repo.Host = strings.ToLower(parsed.Host)
+
+	path := parsed.Path
+	if i := strings.Index(path, "/-/"); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 2 {
+		return repo
 	}
+
+	repo.Owner = strings.Join(segments[:len(segments)-1], "/")
+	repo.Repo = strings.TrimSuffix(segments[len(segments)-1], ".git")
+	return repo
 }
 
 // ConvertFlathubReleases converts Flathub releases to our Release format
@@ -408,35 +607,19 @@ func ConvertFlathubReleases(releases []models.FlathubReleaseEntry) []models.Rele
 	var result []models.Release
 
 	for _, release := range releases {
-		var date time.Time
-		var err error
-		parsed := false
-
-		// Prefer Unix timestamp from Flathub API (most reliable)
-		if release.Timestamp != "" {
-			ts, parseErr := strconv.ParseInt(release.Timestamp, 10, 64)
-			if parseErr == nil {
-				date = time.Unix(ts, 0).UTC()
-				parsed = true
-			}
-		}
-
-		// Try date string formats if timestamp didn't work
-		if !parsed {
-			date, err = time.Parse("2006-01-02", release.Date)
-			if err != nil {
-				date, err = time.Parse(time.RFC3339, release.Date)
-				if err != nil {
-					log.Printf("⚠️  No valid date for release %s, using current time", release.Version)
-					date = time.Now()
-				}
+		date, ok := parseReleaseDate(release)
+		if !ok {
+			var keep bool
+			if date, keep = dates.Fallback(); !keep {
+				log.Printf("⚠️  No valid date for release %s, dropping it", release.Version)
+				continue
 			}
+			log.Printf("⚠️  No valid date for release %s, using fallback date", release.Version)
 		}
 
 		result = append(result, models.Release{
 			Version:     release.Version,
-			Date:        date,
-			Title:       fmt.Sprintf("Version %s", release.Version),
+			Date:        date.UTC(),
 			Description: release.Description,
 			Type:        "appstream",
 		})
@@ -444,3 +627,63 @@ func ConvertFlathubReleases(releases []models.FlathubReleaseEntry) []models.Rele
 
 	return result
 }
+
+// parseReleaseDate resolves a Flathub release date, preferring the Unix timestamp
+// (most reliable) and falling back to the date field, which may itself be a
+// date string, an RFC3339 timestamp, or epoch seconds/millis
+func parseReleaseDate(release models.FlathubReleaseEntry) (time.Time, bool) {
+	if date, ok := parseEpoch(string(release.Timestamp)); ok {
+		return date, true
+	}
+
+	dateStr := strings.TrimSpace(string(release.Date))
+	if dateStr == "" {
+		return time.Time{}, false
+	}
+
+	if date, err := time.Parse("2006-01-02", dateStr); err == nil {
+		return date, true
+	}
+	if date, err := time.Parse(time.RFC3339, dateStr); err == nil {
+		return date, true
+	}
+
+	return parseEpoch(dateStr)
+}
+
+// parseEpoch parses Unix epoch seconds or milliseconds
+func parseEpoch(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	ts, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ts <= 0 {
+		return time.Time{}, false
+	}
+
+	// Anything past year ~33658 in seconds is really milliseconds
+	if ts >= 1e12 {
+		return time.UnixMilli(ts).UTC(), true
+	}
+	return time.Unix(ts, 0).UTC(), true
+}
+
+// parseBundle extracts the runtime ("org.gnome.Platform//48") and app branch
+// ("stable") from an appstream bundle. Flatpak refs are name/arch/branch; the
+// arch is dropped since Flathub builds every arch from the same manifest.
+func parseBundle(bundle *models.FlathubBundle) (runtime, branch string) {
+	if bundle == nil {
+		return "", ""
+	}
+
+	if parts := strings.Split(bundle.Runtime, "/"); len(parts) == 3 && parts[0] != "" {
+		runtime = parts[0] + "//" + parts[2]
+	}
+	// App refs carry a leading kind: app/name/arch/branch
+	if parts := strings.Split(bundle.Value, "/"); len(parts) == 4 {
+		branch = parts[3]
+	}
+	return runtime, branch
+}
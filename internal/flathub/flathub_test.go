@@ -0,0 +1,368 @@
+package flathub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/dates"
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestConvertFlathubReleasesDates(t *testing.T) {
+	want := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{
+			name:    "Date string",
+			payload: `{"version": "1.0", "date": "2024-03-15"}`,
+		},
+		{
+			name:    "RFC3339 date",
+			payload: `{"version": "1.0", "date": "2024-03-15T00:00:00Z"}`,
+		},
+		{
+			name:    "RFC3339 date with offset",
+			payload: `{"version": "1.0", "date": "2024-03-15T02:00:00+02:00"}`,
+		},
+		{
+			name:    "Epoch seconds as string",
+			payload: `{"version": "1.0", "date": "1710460800"}`,
+		},
+		{
+			name:    "Epoch seconds as number",
+			payload: `{"version": "1.0", "date": 1710460800}`,
+		},
+		{
+			name:    "Epoch millis as number",
+			payload: `{"version": "1.0", "date": 1710460800000}`,
+		},
+		{
+			name:    "Numeric timestamp takes precedence over date",
+			payload: `{"version": "1.0", "date": "2020-01-01", "timestamp": 1710460800}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var entry models.FlathubReleaseEntry
+			if err := json.Unmarshal([]byte(tt.payload), &entry); err != nil {
+				t.Fatalf("Unexpected unmarshal error: %v", err)
+			}
+
+			releases := ConvertFlathubReleases([]models.FlathubReleaseEntry{entry})
+			if len(releases) != 1 {
+				t.Fatalf("Expected 1 release, got %d", len(releases))
+			}
+			if !releases[0].Date.Equal(want) {
+				t.Errorf("Expected date %s, got %s", want, releases[0].Date)
+			}
+			if releases[0].Date.Location() != time.UTC {
+				t.Errorf("Expected date in UTC, got %s", releases[0].Date.Location())
+			}
+		})
+	}
+}
+
+func TestConvertFlathubReleasesDateFallback(t *testing.T) {
+	defer dates.SetFallback(dates.DefaultPolicy)
+	entry := models.FlathubReleaseEntry{Version: "1.0", Date: "sometime in spring"}
+
+	tests := []struct {
+		policy   dates.Policy
+		wantKept bool
+		wantNow  bool
+	}{
+		{policy: dates.PolicyZero, wantKept: true},
+		{policy: dates.PolicyNow, wantKept: true, wantNow: true},
+		{policy: dates.PolicySkip},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			dates.SetFallback(tt.policy)
+			releases := ConvertFlathubReleases([]models.FlathubReleaseEntry{entry})
+			if !tt.wantKept {
+				if len(releases) != 0 {
+					t.Errorf("Expected the undated release to be dropped, got %+v", releases)
+				}
+				return
+			}
+			if len(releases) != 1 {
+				t.Fatalf("Expected 1 release, got %d", len(releases))
+			}
+			date := releases[0].Date
+			if tt.wantNow && time.Since(date) > time.Minute {
+				t.Errorf("Expected the current time, got %s", date)
+			}
+			if !tt.wantNow && !date.IsZero() {
+				t.Errorf("Expected a zero date, got %s", date)
+			}
+		})
+	}
+}
+
+func TestFetchAllAppsMarksDelistedCuratedApps(t *testing.T) {
+	// 404 for appstream details and an empty collection otherwise
+	notFound := httpxtest.Response{Status: http.StatusNotFound, Body: `{"detail":"Not Found"}`}
+	defer httpx.SetBaseTransport(&httpxtest.Transport{Responses: map[string]httpxtest.Response{
+		"":                              {Body: `{"hits":[]}`},
+		"/appstream/org.example.Gone":   notFound,
+		"/appstream/org.example.Listed": notFound,
+	}})()
+
+	results, err := FetchAllApps("org.example.Gone")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results.Apps) != 1 {
+		t.Fatalf("Expected 1 app, got %d", len(results.Apps))
+	}
+	if !results.Apps[0].EOL {
+		t.Error("Expected curated app missing from Flathub to be marked EOL")
+	}
+
+	// Present in a collection feed but without details: still listed
+	app := enrichApp(models.FlathubApp{AppID: "org.example.Listed", Name: "Listed"})
+	if app.EOL {
+		t.Error("Expected feed app with missing details not to be marked EOL")
+	}
+}
+
+func TestFetchAllAppsFeedError(t *testing.T) {
+	defer httpx.SetBaseTransport(httpxtest.Status(http.StatusServiceUnavailable))()
+
+	results, err := FetchAllApps()
+	if err == nil {
+		t.Fatal("Expected a feed listing failure to be returned as an error")
+	}
+	if results != nil {
+		t.Errorf("Expected no results on error, got %+v", results)
+	}
+}
+
+func TestEnrichAppSanitizesDescription(t *testing.T) {
+	details := `{
+  "id": "org.gnome.Loupe",
+  "name": "Image Viewer",
+  "summary": "View images",
+  "description": "<p>Browse through images and inspect their metadata with:</p><ul><li>Fast GPU accelerated image rendering</li><li>Tiled rendering for vector graphics</li></ul><p onmouseover=\"steal()\">Supports <em>many</em> formats<script>steal()</script></p>"
+}`
+	defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{"": details}))()
+
+	app := enrichApp(models.FlathubApp{AppID: "org.gnome.Loupe"})
+
+	want := "<p>Browse through images and inspect their metadata with:</p><ul><li>Fast GPU accelerated image rendering</li><li>Tiled rendering for vector graphics</li></ul><p>Supports <em>many</em> formats</p>"
+	if app.Description != want {
+		t.Errorf("Expected sanitized description %q, got %q", want, app.Description)
+	}
+}
+
+func TestEnrichAppHomepage(t *testing.T) {
+	details := `{
+  "id": "org.gnome.Loupe",
+  "name": "Image Viewer",
+  "urls": {
+    "homepage": "https://apps.gnome.org/Loupe/",
+    "vcs_browser": "https://gitlab.gnome.org/GNOME/loupe"
+  }
+}`
+	defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{"": details}))()
+
+	app := enrichApp(models.FlathubApp{AppID: "org.gnome.Loupe"})
+
+	if app.Homepage != "https://apps.gnome.org/Loupe/" {
+		t.Errorf("Expected homepage from appstream, got %q", app.Homepage)
+	}
+	if app.SourceRepo == nil || app.SourceRepo.URL != "https://gitlab.gnome.org/GNOME/loupe" {
+		t.Errorf("Expected source repo to stay on the VCS URL, got %+v", app.SourceRepo)
+	}
+}
+
+func TestEnrichAppRuntime(t *testing.T) {
+	details := `{
+  "id": "org.gnome.Loupe",
+  "name": "Image Viewer",
+  "bundle": {
+    "type": "flatpak",
+    "value": "app/org.gnome.Loupe/x86_64/stable",
+    "runtime": "org.gnome.Platform/x86_64/48",
+    "sdk": "org.gnome.Sdk/x86_64/48"
+  }
+}`
+	defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{"": details}))()
+
+	app := enrichApp(models.FlathubApp{AppID: "org.gnome.Loupe"})
+
+	if app.Runtime != "org.gnome.Platform//48" || app.Branch != "stable" {
+		t.Errorf("Expected runtime org.gnome.Platform//48 on stable, got %q on %q", app.Runtime, app.Branch)
+	}
+}
+
+func TestEnrichAppProjectGroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		details string
+		want    string
+	}{
+		{name: "with project group", details: `{"id": "org.gnome.Loupe", "name": "Image Viewer", "project_group": "GNOME"}`, want: "GNOME"},
+		{name: "without project group", details: `{"id": "com.example.App", "name": "App"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{"": tt.details}))()
+
+			app := enrichApp(models.FlathubApp{AppID: "org.gnome.Loupe"})
+			if app.ProjectGroup != tt.want {
+				t.Errorf("Expected project group %q, got %q", tt.want, app.ProjectGroup)
+			}
+		})
+	}
+}
+
+func TestParseBundle(t *testing.T) {
+	tests := []struct {
+		name        string
+		bundle      *models.FlathubBundle
+		wantRuntime string
+		wantBranch  string
+	}{
+		{name: "no bundle"},
+		{name: "beta branch", bundle: &models.FlathubBundle{Value: "app/org.mozilla.firefox/aarch64/beta", Runtime: "org.freedesktop.Platform/aarch64/24.08"}, wantRuntime: "org.freedesktop.Platform//24.08", wantBranch: "beta"},
+		{name: "runtime missing", bundle: &models.FlathubBundle{Value: "app/com.example.App/x86_64/stable"}, wantBranch: "stable"},
+		{name: "malformed refs", bundle: &models.FlathubBundle{Value: "com.example.App", Runtime: "org.kde.Platform"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime, branch := parseBundle(tt.bundle)
+			if runtime != tt.wantRuntime || branch != tt.wantBranch {
+				t.Errorf("Expected (%q, %q), got (%q, %q)", tt.wantRuntime, tt.wantBranch, runtime, branch)
+			}
+		})
+	}
+}
+
+func TestFetchAllAppsHonorsBudget(t *testing.T) {
+	httpx.Configure(httpx.Limits{})
+	defer httpx.Configure(httpx.DefaultLimits)
+	defer Configure(Options{})
+
+	appIDs := make([]string, 16)
+	for i := range appIDs {
+		appIDs[i] = fmt.Sprintf("org.example.App%d", i)
+	}
+
+	t.Run("slow upstream is cut off", func(t *testing.T) {
+		defer httpx.SetBaseTransport(&httpxtest.Transport{Responses: map[string]httpxtest.Response{"": {Body: `{"name": "Slow App"}`, Delay: 2 * time.Second}}})()
+		budget := 300 * time.Millisecond
+		Configure(Options{Budget: budget})
+
+		start := time.Now()
+		results, err := FetchAllApps(appIDs...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > budget+200*time.Millisecond {
+			t.Errorf("Expected to finish within the %s budget, took %s", budget, elapsed)
+		}
+		if len(results.Apps) != len(appIDs) {
+			t.Fatalf("Expected every app to be returned, got %d", len(results.Apps))
+		}
+		for _, app := range results.Apps {
+			if !app.TimedOut || app.EOL {
+				t.Errorf("Expected %s to be flagged as timed out (not EOL), got %+v", app.ID, app)
+			}
+		}
+	})
+
+	t.Run("fast upstream fits", func(t *testing.T) {
+		defer httpx.SetBaseTransport(&httpxtest.Transport{Responses: map[string]httpxtest.Response{"": {Body: `{"name": "Slow App"}`, Delay: 10 * time.Millisecond}}})()
+		Configure(Options{Budget: 5 * time.Second})
+
+		results, err := FetchAllApps(appIDs...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, app := range results.Apps {
+			if app.TimedOut || app.Name != "Slow App" {
+				t.Errorf("Expected %s to be enriched, got %+v", app.ID, app)
+			}
+		}
+	})
+}
+
+func TestAppDeadline(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	deadline := now.Add(60 * time.Second)
+
+	tests := []struct {
+		pending int
+		want    time.Duration
+	}{
+		{pending: 24, want: 20 * time.Second}, // Three rounds of 8 left
+		{pending: 9, want: 30 * time.Second},
+		{pending: 3, want: 60 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := appDeadline(now, deadline, tt.pending, 8).Sub(now); got != tt.want {
+			t.Errorf("Expected %s for %d pending apps, got %s", tt.want, tt.pending, got)
+		}
+	}
+	if got := appDeadline(deadline.Add(time.Second), deadline, 5, 8); !got.Equal(deadline.Add(time.Second)) {
+		t.Errorf("Expected an expired budget to give no time, got %s", got)
+	}
+}
+
+func TestExtractSourceRepoGitLabHosts(t *testing.T) {
+	tests := []struct {
+		name     string
+		homepage string
+		want     models.SourceRepo
+	}{
+		{
+			name:     "KDE project",
+			homepage: "https://invent.kde.org/utilities/kate",
+			want:     models.SourceRepo{Type: "gitlab", Host: "invent.kde.org", Owner: "utilities", Repo: "kate"},
+		},
+		{
+			name:     "KDE nested group with page suffix",
+			homepage: "https://invent.kde.org/plasma-mobile/apps/angelfish/-/releases",
+			want:     models.SourceRepo{Type: "gitlab", Host: "invent.kde.org", Owner: "plasma-mobile/apps", Repo: "angelfish"},
+		},
+		{
+			name:     "freedesktop project",
+			homepage: "https://gitlab.freedesktop.org/pipewire/helvum",
+			want:     models.SourceRepo{Type: "gitlab", Host: "gitlab.freedesktop.org", Owner: "pipewire", Repo: "helvum"},
+		},
+		{
+			name:     "freedesktop nested group with .git suffix",
+			homepage: "https://gitlab.freedesktop.org/xorg/app/xeyes.git",
+			want:     models.SourceRepo{Type: "gitlab", Host: "gitlab.freedesktop.org", Owner: "xorg/app", Repo: "xeyes"},
+		},
+		{
+			name:     "KDE host without a project",
+			homepage: "https://invent.kde.org/",
+			want:     models.SourceRepo{Type: "gitlab", Host: "invent.kde.org"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := &models.FlathubAppDetails{URLs: map[string]string{"homepage": tt.homepage}}
+			got := ExtractSourceRepo("org.example.Test", details)
+			tt.want.URL = tt.homepage
+			if got == nil || *got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
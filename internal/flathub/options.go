@@ -0,0 +1,30 @@
+package flathub
+
+import (
+	"sync"
+	"time"
+)
+
+// Options controls which apps the Flathub fetcher lists and how long it may take
+type Options struct {
+	Feed   string        // Collection used when no app IDs are given; empty means DefaultFeed
+	Budget time.Duration // Wall-clock cap for FetchAllApps, split across apps; 0 means no cap
+}
+
+var (
+	optionsMu sync.RWMutex
+	options   Options
+)
+
+// Configure sets the options used by subsequent fetches
+func Configure(o Options) {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	options = o
+}
+
+func currentOptions() Options {
+	optionsMu.RLock()
+	defer optionsMu.RUnlock()
+	return options
+}
@@ -0,0 +1,140 @@
+package flathub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Reasons a curated app ID fails validation
+const (
+	ReasonMalformed = "malformed" // Not a valid Flatpak application ID
+	ReasonTypo      = "typo"      // Close to an ID Flathub lists; see Suggestion
+	ReasonDelisted  = "delisted"  // Well-formed but unlisted: removed, EOL, or a typo too far off to guess
+)
+
+// maxTypoDistance is the largest edit distance at which an unlisted ID is
+// reported as a typo of a listed one
+const maxTypoDistance = 2
+
+// appIDRe matches a Flatpak application ID: three or more dot-separated
+// elements of letters, digits, underscores and dashes, not starting with a digit
+var appIDRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*(\.[A-Za-z_][A-Za-z0-9_-]*){2,}$`)
+
+// InvalidAppID is a curated app ID that Flathub doesn't list
+type InvalidAppID struct {
+	ID         string
+	Reason     string // One of the Reason constants
+	Suggestion string // Closest listed ID, when one is near enough
+}
+
+func (i InvalidAppID) String() string {
+	if i.Suggestion != "" {
+		return fmt.Sprintf("%s (%s, did you mean %s?)", i.ID, i.Reason, i.Suggestion)
+	}
+	return fmt.Sprintf("%s (%s)", i.ID, i.Reason)
+}
+
+// ValidateAppIDs checks curated app IDs against the list of every app on
+// Flathub, a single request, so curation mistakes surface before the
+// per-app enrichment. It returns the IDs Flathub doesn't list, in input order.
+func ValidateAppIDs(appIDs []string) ([]InvalidAppID, error) {
+	listed, err := fetchAppIDs(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return validateAppIDs(appIDs, listed), nil
+}
+
+// validateAppIDs classifies the app IDs missing from listed
+func validateAppIDs(appIDs, listed []string) []InvalidAppID {
+	known := make(map[string]bool, len(listed))
+	for _, id := range listed {
+		known[id] = true
+	}
+
+	var invalid []InvalidAppID
+	for _, id := range appIDs {
+		if known[id] {
+			continue
+		}
+		result := InvalidAppID{ID: id, Reason: ReasonDelisted}
+		if suggestion, ok := closestAppID(id, listed); ok {
+			result.Reason = ReasonTypo
+			result.Suggestion = suggestion
+		}
+		if !appIDRe.MatchString(id) {
+			result.Reason = ReasonMalformed
+		}
+		invalid = append(invalid, result)
+	}
+	return invalid
+}
+
+// closestAppID finds the listed ID nearest to id, ignoring case, within
+// maxTypoDistance edits
+func closestAppID(id string, listed []string) (string, bool) {
+	lower := strings.ToLower(id)
+	best, bestDistance := "", maxTypoDistance+1
+	for _, candidate := range listed {
+		if d := editDistance(lower, strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// fetchAppIDs lists the ID of every app on Flathub
+func fetchAppIDs(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", FlathubAPIBase+"/appstream", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch app IDs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	var ids []string
+	if err := json.Unmarshal(body, &ids); err != nil {
+		return nil, fmt.Errorf("unmarshal app IDs: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,46 @@
+package flathub
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+)
+
+func TestValidateAppIDs(t *testing.T) {
+	listing := `["org.gnome.Loupe", "org.mozilla.firefox", "com.github.tchx84.Flatseal", "io.github.flattool.Warehouse"]`
+	defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{"": listing}))()
+
+	invalid, err := ValidateAppIDs([]string{
+		"org.gnome.Loupe",
+		"org.mozilla.firefox",
+		"com.github.tchx84.FlatSeal",  // Wrong case
+		"io.github.flattool.Warehous", // Missing letter
+		"org.example.RemovedLongAgo",  // Nothing close
+		"flatseal",                    // Not an app ID
+		"com.github.tchx84.Flatseal",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []InvalidAppID{
+		{ID: "com.github.tchx84.FlatSeal", Reason: ReasonTypo, Suggestion: "com.github.tchx84.Flatseal"},
+		{ID: "io.github.flattool.Warehous", Reason: ReasonTypo, Suggestion: "io.github.flattool.Warehouse"},
+		{ID: "org.example.RemovedLongAgo", Reason: ReasonDelisted},
+		{ID: "flatseal", Reason: ReasonMalformed},
+	}
+	if !reflect.DeepEqual(invalid, want) {
+		t.Errorf("Expected %+v, got %+v", want, invalid)
+	}
+}
+
+func TestValidateAppIDsListingError(t *testing.T) {
+	defer httpx.SetBaseTransport(httpxtest.Status(http.StatusBadGateway))()
+
+	if _, err := ValidateAppIDs([]string{"org.gnome.Loupe"}); err == nil {
+		t.Error("Expected an error when the app listing fails")
+	}
+}
@@ -4,16 +4,52 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/castrojo/bluefin-releases/internal/assets"
+	"github.com/castrojo/bluefin-releases/internal/dates"
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/lastcommit"
 	"github.com/castrojo/bluefin-releases/internal/markdown"
 	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/rss"
+	relversion "github.com/castrojo/bluefin-releases/internal/version"
 	"github.com/google/go-github/v57/github"
 	"golang.org/x/oauth2"
 )
 
+// Options controls optional GitHub data collection
+type Options struct {
+	Reactions           bool // Capture total reaction counts per release
+	TagMessages         bool // Fill empty release notes from annotated tag messages (up to two extra requests per release)
+	LatestOnly          bool // Fetch only the newest release via /releases/latest, falling back to the list for prerelease-only repos
+	LastCommit          bool // Record the default branch's last commit date (one extra request per repo)
+	ConserveQuota       bool // Use the repos' Atom feeds, which don't count against the REST quota, when /rate_limit shows too few requests left
+	KeepNewContributors bool // Leave the autogenerated "New Contributors" section in rendered notes (its logins go to Release.Contributors either way)
+}
+
+var options Options
+
+// Configure sets the options used by subsequent enrichment runs
+func Configure(o Options) {
+	options = o
+}
+
+// reactionsAccept is the media type that makes the releases API include reaction rollups
+const reactionsAccept = "application/vnd.github.squirrel-girl-preview+json"
+
+// githubRelease extends the go-github release with the reactions rollup,
+// which go-github v57 doesn't model for releases
+type githubRelease struct {
+	github.RepositoryRelease
+	Reactions *github.Reactions `json:"reactions,omitempty"`
+}
+
 // EnrichWithGitHubReleases fetches GitHub releases for apps with GitHub repos
 // and adds them to the app's release list (prioritizing actual source changelogs)
 func EnrichWithGitHubReleases(apps []models.App) []models.App {
@@ -21,27 +57,52 @@ func EnrichWithGitHubReleases(apps []models.App) []models.App {
 	token := os.Getenv("GITHUB_TOKEN")
 	if token == "" {
 		log.Println("⚠️  No GITHUB_TOKEN found, skipping GitHub release fetching")
+		for i := range apps {
+			apps[i].Debug.Record(models.DebugStep{
+				Stage:   "github",
+				Matched: isGitHubApp(&apps[i]),
+				Note:    "skipped: no GITHUB_TOKEN",
+			})
+		}
 		return apps
 	}
 
 	// Create GitHub client
-	ctx := context.Background()
+	// Route go-github through the shared governed transport
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpx.NewClientWith(httpx.ClientOptions{CacheSource: httpx.SourceGitHubReleases}))
 	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
 	tc := oauth2.NewClient(ctx, ts)
 	client := github.NewClient(tc)
 
+	enrichedApps := make([]models.App, len(apps))
+	copy(enrichedApps, apps)
+
+	// The quota check, last commit dates and tag lookups must see live data,
+	// not releases cached for SourceGitHubReleases' TTL, so they bypass the
+	// response cache
+	liveCtx := context.WithValue(context.Background(), oauth2.HTTPClient, httpx.NewClient(0))
+	live := github.NewClient(oauth2.NewClient(liveCtx, ts))
+	useFeeds := checkQuota(ctx, live, countRepos(enrichedApps)*requestsPerRepo())
+	var feeds *rss.Parser
+	if useFeeds {
+		feeds = rss.NewParser(10 * time.Second)
+		if ignored := feedIgnoredOptions(); len(ignored) > 0 {
+			log.Printf("⚠️  Atom feeds don't carry this data, ignoring %s", strings.Join(ignored, ", "))
+		}
+	}
+
 	var (
 		wg sync.WaitGroup
 		mu sync.Mutex
 	)
 
-	enrichedApps := make([]models.App, len(apps))
-	copy(enrichedApps, apps)
+	commits := lastcommit.NewCache()
 
 	// Process apps with GitHub repos in parallel
 	for i := range enrichedApps {
 		app := &enrichedApps[i]
-		if app.SourceRepo == nil || app.SourceRepo.Type != "github" || app.SourceRepo.Owner == "" || app.SourceRepo.Repo == "" {
+		if !isGitHubApp(app) {
+			app.Debug.Record(models.DebugStep{Stage: "github", Matched: false})
 			continue
 		}
 
@@ -49,10 +110,32 @@ func EnrichWithGitHubReleases(apps []models.App) []models.App {
 		go func(app *models.App) {
 			defer wg.Done()
 
-			releases, err := fetchGitHubReleases(ctx, client, app.SourceRepo.Owner, app.SourceRepo.Repo)
+			owner, repo := app.SourceRepo.Owner, app.SourceRepo.Repo
+			var (
+				releases []models.Release
+				status   int
+				err      error
+				note     string
+			)
+			if useFeeds {
+				releases, err = feeds.FetchGitHubReleases(ctx, owner, repo)
+				releases = filterFeedReleases(releases)
+				note = "via Atom feed to conserve REST quota"
+			} else {
+				if options.LastCommit {
+					app.LastCommitDate = commits.Date(owner+"/"+repo, func() (time.Time, error) {
+						return fetchLastCommitDate(ctx, live, owner, repo)
+					})
+				}
+				releases, status, err = fetchGitHubReleases(ctx, client, owner, repo)
+				if err == nil && options.TagMessages {
+					fillFromTagMessages(ctx, live, owner, repo, releases)
+				}
+			}
 			if err != nil {
 				log.Printf("⚠️  Failed to fetch GitHub releases for %s/%s: %v",
 					app.SourceRepo.Owner, app.SourceRepo.Repo, err)
+				app.Debug.Record(models.DebugStep{Stage: "github", Matched: true, Status: status, Note: err.Error()})
 				return
 			}
 
@@ -60,6 +143,7 @@ func EnrichWithGitHubReleases(apps []models.App) []models.App {
 			// Prepend GitHub releases (they are from actual source, so prioritize them)
 			app.Releases = append(releases, app.Releases...)
 			log.Printf("✅ Added %d GitHub releases for %s", len(releases), app.ID)
+			app.Debug.Record(models.DebugStep{Stage: "github", Matched: true, Status: status, ReleasesAdded: len(releases), Note: note})
 			mu.Unlock()
 
 			// Rate limiting: GitHub has a rate limit of 60 requests/hour for unauthenticated
@@ -73,36 +157,231 @@ func EnrichWithGitHubReleases(apps []models.App) []models.App {
 	return enrichedApps
 }
 
-// fetchGitHubReleases fetches the latest releases from a GitHub repository
-func fetchGitHubReleases(ctx context.Context, client *github.Client, owner, repo string) ([]models.Release, error) {
-	// Fetch up to 5 latest releases
-	opts := &github.ListOptions{PerPage: 5}
-	githubReleases, _, err := client.Repositories.ListReleases(ctx, owner, repo, opts)
+// countRepos counts the distinct GitHub repos among apps
+func countRepos(apps []models.App) int {
+	repos := make(map[string]bool)
+	for i := range apps {
+		if isGitHubApp(&apps[i]) {
+			repos[strings.ToLower(apps[i].SourceRepo.Owner+"/"+apps[i].SourceRepo.Repo)] = true
+		}
+	}
+	return len(repos)
+}
+
+// requestsPerRepo estimates the REST requests enriching one repo takes, not
+// counting tag message lookups, which depend on the notes returned
+func requestsPerRepo() int {
+	n := 1
+	if options.LatestOnly {
+		n++ // Prerelease-only repos fall back to the list endpoint
+	}
+	if options.LastCommit {
+		n++
+	}
+	return n
+}
+
+// checkQuota compares the REST quota left, per /rate_limit, with the requests
+// the run needs. A shortfall is logged; it returns true when ConserveQuota
+// should move the run onto Atom feeds. A failed check assumes enough quota.
+func checkQuota(ctx context.Context, client *github.Client, needed int) bool {
+	if needed == 0 {
+		return false
+	}
+	limits, _, err := client.RateLimit.Get(ctx)
+	if err != nil || limits.GetCore() == nil {
+		log.Printf("⚠️  Couldn't check GitHub rate limit, continuing: %v", err)
+		return false
+	}
+
+	remaining := limits.GetCore().Remaining
+	if remaining >= needed {
+		return false
+	}
+	if options.ConserveQuota {
+		log.Printf("⚠️  GitHub quota too low (%d requests left, ~%d needed), fetching releases from Atom feeds instead", remaining, needed)
+		return true
+	}
+	log.Printf("⚠️  GitHub quota may run out (%d requests left, ~%d needed, resets %s), continuing best-effort",
+		remaining, needed, limits.GetCore().Reset.Format(time.RFC3339))
+	return false
+}
+
+// feedIgnoredOptions lists the enabled options the Atom feed path can't honour
+func feedIgnoredOptions() []string {
+	var ignored []string
+	if options.LastCommit {
+		ignored = append(ignored, "-last-commit")
+	}
+	if options.TagMessages {
+		ignored = append(ignored, "-tag-messages")
+	}
+	if options.Reactions {
+		ignored = append(ignored, "-reactions")
+	}
+	return ignored
+}
+
+// filterFeedReleases drops prereleases from Atom feed releases and keeps only
+// the newest under LatestOnly. Feeds don't flag prereleases, so they are
+// recognized by their version.
+func filterFeedReleases(releases []models.Release) []models.Release {
+	kept := releases[:0]
+	for _, release := range releases {
+		if v, ok := relversion.Parse(release.Version); ok && v.IsPrerelease() {
+			continue
+		}
+		kept = append(kept, release)
+	}
+	if options.LatestOnly && len(kept) > 1 {
+		kept = kept[:1]
+	}
+	return kept
+}
+
+// isGitHubApp reports whether an app has a usable GitHub owner/repo
+func isGitHubApp(app *models.App) bool {
+	return app.SourceRepo != nil && app.SourceRepo.Type == "github" && app.SourceRepo.Owner != "" && app.SourceRepo.Repo != ""
+}
+
+// fetchGitHubReleases fetches the latest releases from a GitHub repository.
+// Also returns the HTTP status of the API call (0 if no response was received).
+func fetchGitHubReleases(ctx context.Context, client *github.Client, owner, repo string) ([]models.Release, int, error) {
+	var (
+		githubReleases []*githubRelease
+		status         int
+		err            error
+	)
+	if options.LatestOnly {
+		var latest *githubRelease
+		latest, status, err = getReleases[githubRelease](ctx, client, fmt.Sprintf("repos/%s/%s/releases/latest", owner, repo))
+		switch {
+		case err == nil:
+			githubReleases = []*githubRelease{latest}
+		case status == http.StatusNotFound:
+			// Repos that only publish prereleases have no "latest" release
+			githubReleases = nil
+		default:
+			return nil, status, fmt.Errorf("get latest release: %w", err)
+		}
+	}
+
+	if githubReleases == nil {
+		// Fetch up to 5 latest releases
+		var list *[]*githubRelease
+		list, status, err = getReleases[[]*githubRelease](ctx, client, fmt.Sprintf("repos/%s/%s/releases?per_page=5", owner, repo))
+		if err != nil {
+			return nil, status, fmt.Errorf("list releases: %w", err)
+		}
+		githubReleases = *list
+		if options.LatestOnly && len(githubReleases) > 1 {
+			githubReleases = githubReleases[:1]
+		}
+	}
+
+	return convertReleases(githubReleases, repo, options.Reactions), status, nil
+}
+
+// fetchLastCommitDate returns the committer date of the newest commit on the
+// repo's default branch
+func fetchLastCommitDate(ctx context.Context, client *github.Client, owner, repo string) (time.Time, error) {
+	commits, _, err := client.Repositories.ListCommits(ctx, owner, repo, &github.CommitsListOptions{ListOptions: github.ListOptions{PerPage: 1}})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("list commits: %w", err)
+	}
+	if len(commits) == 0 || commits[0].GetCommit().GetCommitter().GetDate().IsZero() {
+		return time.Time{}, fmt.Errorf("no commits")
+	}
+	return commits[0].GetCommit().GetCommitter().GetDate().Time, nil
+}
+
+// getReleases decodes a releases API response into a new T, asking for
+// reaction rollups when enabled. Also returns the HTTP status (0 if no response was received).
+func getReleases[T any](ctx context.Context, client *github.Client, path string) (*T, int, error) {
+	req, err := client.NewRequest("GET", path, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("build request: %w", err)
+	}
+	if options.Reactions {
+		req.Header.Set("Accept", reactionsAccept)
+	}
+
+	v := new(T)
+	resp, err := client.Do(ctx, req, v)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
 	if err != nil {
-		return nil, fmt.Errorf("list releases: %w", err)
+		return nil, status, err
 	}
+	return v, status, nil
+}
 
+// fillFromTagMessages uses the annotated tag message as the notes of releases
+// published without a body. Lightweight tags carry no message and are skipped.
+func fillFromTagMessages(ctx context.Context, client *github.Client, owner, repo string, releases []models.Release) {
+	for i := range releases {
+		release := &releases[i]
+		if strings.TrimSpace(release.DescriptionSource) != "" {
+			continue
+		}
+
+		ref, _, err := client.Git.GetRef(ctx, owner, repo, "tags/"+release.Version)
+		if err != nil {
+			log.Printf("⚠️  Failed to look up tag %s for %s/%s: %v", release.Version, owner, repo, err)
+			continue
+		}
+		if ref.GetObject().GetType() != "tag" {
+			continue
+		}
+
+		tag, _, err := client.Git.GetTag(ctx, owner, repo, ref.GetObject().GetSHA())
+		if err != nil {
+			log.Printf("⚠️  Failed to fetch tag %s for %s/%s: %v", release.Version, owner, repo, err)
+			continue
+		}
+		if message := strings.TrimSpace(tag.GetMessage()); message != "" {
+			release.DescriptionSource = message
+			release.Description = markdown.ToHTML(message)
+		}
+	}
+}
+
+// convertReleases maps API releases to the output model, skipping untagged entries
+func convertReleases(githubReleases []*githubRelease, repo string, withReactions bool) []models.Release {
 	var releases []models.Release
 	for _, gr := range githubReleases {
 		if gr.TagName == nil {
 			continue
 		}
 
-		date := time.Now()
+		var date time.Time
 		if gr.PublishedAt != nil {
 			date = gr.PublishedAt.Time
 		} else {
-			log.Printf("⚠️  GitHub release %s for %s has no PublishedAt date, using current time", *gr.TagName, repo)
+			var keep bool
+			if date, keep = dates.Fallback(); !keep {
+				log.Printf("⚠️  GitHub release %s for %s has no PublishedAt date, dropping it", *gr.TagName, repo)
+				continue
+			}
+			log.Printf("⚠️  GitHub release %s for %s has no PublishedAt date, using fallback date", *gr.TagName, repo)
 		}
 
-		title := *gr.TagName
-		if gr.Name != nil && *gr.Name != "" {
-			title = *gr.Name
-		}
+		// Unnamed releases are titled from the app and version later, like other sources
+		title := strings.TrimSpace(gr.GetName())
 
 		description := ""
+		descriptionSource := ""
+		var contributors []string
 		if gr.Body != nil {
-			description = markdown.ToHTML(*gr.Body)
+			descriptionSource = *gr.Body
+			notes, logins := splitNewContributors(descriptionSource)
+			contributors = logins
+			if options.KeepNewContributors {
+				notes = descriptionSource
+			}
+			description = markdown.ToHTML(notes)
 		}
 
 		url := ""
@@ -110,15 +389,88 @@ func fetchGitHubReleases(ctx context.Context, client *github.Client, owner, repo
 			url = *gr.HTMLURL
 		}
 
-		releases = append(releases, models.Release{
-			Version:     *gr.TagName,
-			Date:        date,
-			Title:       title,
-			Description: description,
-			URL:         url,
-			Type:        "github-release",
+		reactions := 0
+		if withReactions && gr.Reactions != nil {
+			reactions = gr.Reactions.GetTotalCount()
+		}
+
+		release := models.Release{
+			Version:           *gr.TagName,
+			Date:              date.UTC(),
+			Title:             title,
+			Description:       description,
+			DescriptionSource: descriptionSource,
+			URL:               url,
+			Type:              "github-release",
+			Reactions:         reactions,
+			Author:            gr.GetAuthor().GetLogin(),
+			AuthorAvatar:      gr.GetAuthor().GetAvatarURL(),
+			Contributors:      contributors,
+		}
+		assets.MarkVerification(&release, releaseAssets(gr.Assets))
+		releases = append(releases, release)
+	}
+
+	return releases
+}
+
+// releaseAssets converts go-github assets for classification
+func releaseAssets(ghAssets []*github.ReleaseAsset) []assets.Asset {
+	list := make([]assets.Asset, 0, len(ghAssets))
+	for _, a := range ghAssets {
+		list = append(list, assets.Asset{
+			Name: a.GetName(),
+			URL:  a.GetBrowserDownloadURL(),
+			Size: int64(a.GetSize()),
 		})
 	}
+	return list
+}
+
+var (
+	// newContributorsRe matches the heading of GitHub's autogenerated
+	// "New Contributors" section, e.g. "## New Contributors"
+	newContributorsRe = regexp.MustCompile(`(?i)^(#{1,6})\s*new contributors\s*:?\s*$`)
+	// headingRe matches any Markdown heading, capturing its level
+	headingRe = regexp.MustCompile(`^(#{1,6})\s`)
+	// mentionRe matches a GitHub @mention, including bot accounts
+	mentionRe = regexp.MustCompile(`@([A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?(?:\[bot\])?)`)
+)
+
+// splitNewContributors removes the "New Contributors" section from release
+// notes, returning the remaining notes and the logins it listed (first
+// mention per line, deduplicated). The section ends at the next heading of
+// the same or a higher level, or at the "**Full Changelog**" line.
+func splitNewContributors(body string) (string, []string) {
+	lines := strings.Split(body, "\n")
+	var kept []string
+	var logins []string
+	seen := make(map[string]bool)
+	level := 0 // Heading level of the section being removed; 0 outside it
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if match := newContributorsRe.FindStringSubmatch(trimmed); match != nil {
+			level = len(match[1])
+			continue
+		}
+		if level > 0 {
+			heading := headingRe.FindStringSubmatch(trimmed)
+			if (heading != nil && len(heading[1]) <= level) || strings.HasPrefix(trimmed, "**Full Changelog**") {
+				level = 0
+			} else {
+				if match := mentionRe.FindStringSubmatch(trimmed); match != nil && !seen[match[1]] {
+					seen[match[1]] = true
+					logins = append(logins, match[1])
+				}
+				continue
+			}
+		}
+		kept = append(kept, line)
+	}
 
-	return releases, nil
+	if len(kept) == len(lines) {
+		return body, nil
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n")), logins
 }
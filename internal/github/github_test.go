@@ -0,0 +1,404 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/cache"
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/google/go-github/v57/github"
+)
+
+const releasesPayload = `[
+  {
+    "tag_name": "v2.0.0",
+    "name": "Version 2",
+    "html_url": "https://github.com/example/app/releases/tag/v2.0.0",
+    "published_at": "2026-03-01T12:00:00Z",
+    "body": "Notes",
+    "author": {"login": "octocat", "avatar_url": "https://avatars.githubusercontent.com/u/583231?v=4"},
+    "reactions": {"total_count": 42, "+1": 30, "heart": 12}
+  },
+  {
+    "tag_name": "v1.0.0",
+    "published_at": "2026-01-01T12:00:00Z"
+  }
+]`
+
+func TestConvertReleasesReactions(t *testing.T) {
+	var payload []*githubRelease
+	if err := json.Unmarshal([]byte(releasesPayload), &payload); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		withReactions bool
+		want          []int
+	}{
+		{name: "enabled", withReactions: true, want: []int{42, 0}},
+		{name: "disabled", withReactions: false, want: []int{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			releases := convertReleases(payload, "app", tt.withReactions)
+			if len(releases) != len(tt.want) {
+				t.Fatalf("Expected %d releases, got %d", len(tt.want), len(releases))
+			}
+			for i, want := range tt.want {
+				if releases[i].Reactions != want {
+					t.Errorf("Expected %s to have %d reactions, got %d", releases[i].Version, want, releases[i].Reactions)
+				}
+			}
+			if releases[0].Title != "Version 2" {
+				t.Errorf("Expected title %q, got %q", "Version 2", releases[0].Title)
+			}
+		})
+	}
+}
+
+func TestConvertReleasesAuthor(t *testing.T) {
+	var payload []*githubRelease
+	if err := json.Unmarshal([]byte(releasesPayload), &payload); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+
+	releases := convertReleases(payload, "app", false)
+	if releases[0].Author != "octocat" || releases[0].AuthorAvatar != "https://avatars.githubusercontent.com/u/583231?v=4" {
+		t.Errorf("Expected octocat with avatar, got %q (%q)", releases[0].Author, releases[0].AuthorAvatar)
+	}
+	if releases[1].Author != "" || releases[1].AuthorAvatar != "" {
+		t.Errorf("Expected no author for automated release, got %q (%q)", releases[1].Author, releases[1].AuthorAvatar)
+	}
+}
+
+const newContributorsBody = `## What's Changed
+* Fix crash on startup by @alice in https://github.com/example/app/pull/10
+* Add dark mode by @bob in https://github.com/example/app/pull/11
+
+## New Contributors
+* @bob made their first contribution in https://github.com/example/app/pull/11
+* @renovate[bot] made their first contribution in https://github.com/example/app/pull/12
+
+**Full Changelog**: https://github.com/example/app/compare/v1.0.0...v2.0.0`
+
+func TestConvertReleasesNewContributors(t *testing.T) {
+	defer Configure(options)
+
+	tests := []struct {
+		name        string
+		keep        bool
+		wantSection bool
+	}{
+		{name: "stripped by default"},
+		{name: "kept", keep: true, wantSection: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Configure(Options{KeepNewContributors: tt.keep})
+			body := newContributorsBody
+			releases := convertReleases([]*githubRelease{{RepositoryRelease: github.RepositoryRelease{TagName: github.String("v2.0.0"), Body: &body}}}, "app", false)
+
+			description := releases[0].Description
+			if got := strings.Contains(description, "New Contributors"); got != tt.wantSection {
+				t.Errorf("Expected section present=%v, got %q", tt.wantSection, description)
+			}
+			if !strings.Contains(description, "Add dark mode") || !strings.Contains(description, "Full Changelog") {
+				t.Errorf("Expected the rest of the notes kept, got %q", description)
+			}
+			if releases[0].DescriptionSource != body {
+				t.Errorf("Expected the original notes as source, got %q", releases[0].DescriptionSource)
+			}
+			want := []string{"bob", "renovate[bot]"}
+			if !reflect.DeepEqual(releases[0].Contributors, want) {
+				t.Errorf("Expected contributors %v, got %v", want, releases[0].Contributors)
+			}
+		})
+	}
+}
+
+func TestSplitNewContributorsWithoutSection(t *testing.T) {
+	body := "## What's Changed\n* Thanks to @alice for the fix"
+	notes, logins := splitNewContributors(body)
+	if notes != body || logins != nil {
+		t.Errorf("Expected notes untouched and no contributors, got %q %v", notes, logins)
+	}
+}
+
+func TestFillFromTagMessages(t *testing.T) {
+	// The git ref and annotated tag object for v1.0.0 and a lightweight tag for v0.9.0
+	defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{
+		"/repos/example/app/git/ref/tags/v1.0.0": `{"ref": "refs/tags/v1.0.0", "object": {"type": "tag", "sha": "abc123"}}`,
+		"/repos/example/app/git/tags/abc123":     `{"tag": "v1.0.0", "sha": "abc123", "message": "Release 1.0\n\n- First **stable** release\n"}`,
+		"/repos/example/app/git/ref/tags/v0.9.0": `{"ref": "refs/tags/v0.9.0", "object": {"type": "commit", "sha": "def456"}}`,
+	}))()
+	client := github.NewClient(httpx.NewClient(0))
+
+	releases := []models.Release{
+		{Version: "v1.1.0", DescriptionSource: "Has notes", Description: "<p>Has notes</p>"},
+		{Version: "v1.0.0"},
+		{Version: "v0.9.0"},
+	}
+	fillFromTagMessages(context.Background(), client, "example", "app", releases)
+
+	if releases[0].DescriptionSource != "Has notes" {
+		t.Errorf("Expected existing notes to be kept, got %q", releases[0].DescriptionSource)
+	}
+	if releases[1].DescriptionSource != "Release 1.0\n\n- First **stable** release" {
+		t.Errorf("Expected annotated tag message, got %q", releases[1].DescriptionSource)
+	}
+	if !strings.Contains(releases[1].Description, "<strong>stable</strong>") {
+		t.Errorf("Expected rendered tag message, got %q", releases[1].Description)
+	}
+	if releases[2].Description != "" {
+		t.Errorf("Expected lightweight tag to leave notes empty, got %q", releases[2].Description)
+	}
+}
+
+func TestFetchGitHubReleasesLatestOnly(t *testing.T) {
+	// example/beta only has prereleases, so its /releases/latest 404s
+	transport := httpxtest.Bodies(map[string]string{
+		"/repos/example/app/releases/latest": `{"tag_name": "v2.0.0", "name": "Version 2", "published_at": "2026-03-01T12:00:00Z", "body": "Notes"}`,
+		"/repos/example/beta/releases": `[
+  {"tag_name": "v3.0.0-rc2", "prerelease": true, "published_at": "2026-04-02T12:00:00Z"},
+  {"tag_name": "v3.0.0-rc1", "prerelease": true, "published_at": "2026-04-01T12:00:00Z"}
+]`,
+	})
+	defer httpx.SetBaseTransport(transport)()
+	defer Configure(options)
+	Configure(Options{LatestOnly: true})
+	client := github.NewClient(httpx.NewClient(0))
+
+	tests := []struct {
+		repo      string
+		wantPaths []string
+		want      string
+	}{
+		{
+			repo:      "app",
+			wantPaths: []string{"/repos/example/app/releases/latest"},
+			want:      "v2.0.0",
+		},
+		{
+			repo:      "beta",
+			wantPaths: []string{"/repos/example/beta/releases/latest", "/repos/example/beta/releases"},
+			want:      "v3.0.0-rc2",
+		},
+	}
+
+	for _, tt := range tests {
+		seen := len(transport.Paths())
+		releases, status, err := fetchGitHubReleases(context.Background(), client, "example", tt.repo)
+		if err != nil {
+			t.Fatalf("fetchGitHubReleases(%s) failed: %v", tt.repo, err)
+		}
+		if status != http.StatusOK {
+			t.Errorf("Expected status 200 for %s, got %d", tt.repo, status)
+		}
+		if len(releases) != 1 || releases[0].Version != tt.want {
+			t.Errorf("Expected only %s for %s, got %+v", tt.want, tt.repo, releases)
+		}
+		if paths := transport.Paths()[seen:]; strings.Join(paths, " ") != strings.Join(tt.wantPaths, " ") {
+			t.Errorf("Expected requests %v for %s, got %v", tt.wantPaths, tt.repo, paths)
+		}
+	}
+}
+
+func TestEnrichLastCommitDate(t *testing.T) {
+	// One commit for example/app, a failure for example/gone, and empty release lists
+	transport := &httpxtest.Transport{Responses: map[string]httpxtest.Response{
+		"":                           {Body: `[]`},
+		"/commits":                   {Status: http.StatusNotFound, Body: `{"message": "Not Found"}`},
+		"/repos/example/app/commits": {Body: `[{"sha": "abc123", "commit": {"committer": {"date": "2026-02-03T10:00:00+01:00"}}}]`},
+	}}
+	defer httpx.SetBaseTransport(transport)()
+	defer Configure(options)
+	Configure(Options{LastCommit: true})
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	apps := EnrichWithGitHubReleases([]models.App{
+		{ID: "org.example.App", SourceRepo: &models.SourceRepo{Type: "github", Owner: "example", Repo: "app"}},
+		{ID: "org.example.AppPlugin", SourceRepo: &models.SourceRepo{Type: "github", Owner: "example", Repo: "app"}},
+		{ID: "org.example.Gone", SourceRepo: &models.SourceRepo{Type: "github", Owner: "example", Repo: "gone"}},
+	})
+
+	for _, app := range apps[:2] {
+		if app.LastCommitDate != "2026-02-03T09:00:00Z" {
+			t.Errorf("Expected last commit date in UTC for %s, got %q", app.ID, app.LastCommitDate)
+		}
+	}
+	if apps[2].LastCommitDate != "" {
+		t.Errorf("Expected no last commit date when the call fails, got %q", apps[2].LastCommitDate)
+	}
+	if got := transport.Count("/repos/example/app/commits"); got != 1 {
+		t.Errorf("Expected one commits request for the shared repo, got %d", got)
+	}
+}
+
+func TestEnrichBypassesReleaseCacheForCommits(t *testing.T) {
+	transport := httpxtest.Bodies(map[string]string{
+		"":                           `[]`,
+		"/repos/example/app/commits": `[{"sha": "abc123", "commit": {"committer": {"date": "2026-02-03T10:00:00Z"}}}]`,
+	})
+	defer httpx.SetBaseTransport(transport)()
+	httpx.SetResponseCache(cache.NewMemory(), time.Hour)
+	defer httpx.SetResponseCache(nil, 0)
+	defer Configure(options)
+	Configure(Options{LastCommit: true})
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	// A second run within the releases TTL reuses the cached release list but
+	// asks for the last commit again
+	for run := 0; run < 2; run++ {
+		EnrichWithGitHubReleases([]models.App{
+			{ID: "org.example.App", SourceRepo: &models.SourceRepo{Type: "github", Owner: "example", Repo: "app"}},
+		})
+	}
+
+	if got := transport.Count("/repos/example/app/releases?per_page=5"); got != 1 {
+		t.Errorf("Expected the release list to be served from the cache on the second run, got %d requests", got)
+	}
+	if got := transport.Count("/repos/example/app/commits?per_page=1"); got != 2 {
+		t.Errorf("Expected a live commits request on each run, got %d", got)
+	}
+}
+
+func TestEnrichConservesLowQuota(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	defer Configure(options)
+
+	apps := []models.App{
+		{ID: "org.example.App", SourceRepo: &models.SourceRepo{Type: "github", Owner: "example", Repo: "app"}},
+		{ID: "org.example.Other", SourceRepo: &models.SourceRepo{Type: "github", Owner: "example", Repo: "other"}},
+	}
+
+	tests := []struct {
+		name      string
+		remaining int
+		conserve  bool
+		want      []string
+	}{
+		{
+			name:      "enough quota uses the REST API",
+			remaining: 100,
+			conserve:  true,
+			want:      []string{"api.github.com/repos/example/app/releases", "api.github.com/repos/example/other/releases"},
+		},
+		{
+			name:      "low quota without -conserve-quota continues best-effort",
+			remaining: 1,
+			want:      []string{"api.github.com/repos/example/app/releases", "api.github.com/repos/example/other/releases"},
+		},
+		{
+			name:      "low quota switches to Atom feeds",
+			remaining: 1,
+			conserve:  true,
+			want:      []string{"github.com/example/app/releases.atom", "github.com/example/other/releases.atom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Report the quota from /rate_limit and answer everything else with empty lists and feeds
+			transport := httpxtest.Bodies(map[string]string{
+				"":            `[]`,
+				"/rate_limit": fmt.Sprintf(`{"resources": {"core": {"limit": 5000, "remaining": %d, "reset": 1770000000}}}`, tt.remaining),
+				".atom":       `<?xml version="1.0" encoding="UTF-8"?><feed xmlns="http://www.w3.org/2005/Atom"></feed>`,
+			})
+			defer httpx.SetBaseTransport(transport)()
+			Configure(Options{ConserveQuota: tt.conserve})
+
+			EnrichWithGitHubReleases(apps)
+
+			var paths []string
+			for _, requested := range transport.URLs() {
+				u, err := url.Parse(requested)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if u.Path != "/rate_limit" {
+					paths = append(paths, u.Host+u.Path)
+				}
+			}
+
+			sort.Strings(paths)
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("Expected requests %v, got %v", tt.want, paths)
+			}
+		})
+	}
+}
+
+func TestEnrichFromAtomFeed(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	defer Configure(options)
+
+	// Trimmed from https://github.com/cli/cli/releases.atom: notes are only in <content>
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/" xml:lang="en-US">
+  <id>tag:github.com,2008:https://github.com/example/app/releases</id>
+  <link type="text/html" rel="alternate" href="https://github.com/example/app/releases"/>
+  <link type="application/atom+xml" rel="self" href="https://github.com/example/app/releases.atom"/>
+  <title>Release notes from app</title>
+  <updated>2026-04-02T12:00:00Z</updated>
+  <entry>
+    <id>tag:github.com,2008:Repository/212613049/v2.1.0-rc1</id>
+    <updated>2026-04-02T12:00:00Z</updated>
+    <link rel="alternate" type="text/html" href="https://github.com/example/app/releases/tag/v2.1.0-rc1"/>
+    <title>v2.1.0-rc1</title>
+    <content type="html">&lt;p&gt;Release candidate&lt;/p&gt;</content>
+    <author>
+      <name>octocat</name>
+    </author>
+    <media:thumbnail height="30" width="30" url="https://avatars.githubusercontent.com/u/583231?s=60&amp;v=4"/>
+  </entry>
+  <entry>
+    <id>tag:github.com,2008:Repository/212613049/v2.0.0</id>
+    <updated>2026-04-01T12:00:00Z</updated>
+    <link rel="alternate" type="text/html" href="https://github.com/example/app/releases/tag/v2.0.0"/>
+    <title>App 2.0.0</title>
+    <content type="html">&lt;h2&gt;What&amp;#39;s Changed&lt;/h2&gt;
+&lt;ul&gt;
+&lt;li&gt;Fix the thing&lt;/li&gt;
+&lt;/ul&gt;</content>
+    <author>
+      <name>octocat</name>
+    </author>
+    <media:thumbnail height="30" width="30" url="https://avatars.githubusercontent.com/u/583231?s=60&amp;v=4"/>
+  </entry>
+</feed>`
+
+	transport := httpxtest.Bodies(map[string]string{
+		"/rate_limit":                `{"resources": {"core": {"limit": 5000, "remaining": 0, "reset": 1770000000}}}`,
+		"/example/app/releases.atom": feed,
+	})
+	defer httpx.SetBaseTransport(transport)()
+	Configure(Options{ConserveQuota: true, LastCommit: true})
+
+	apps := EnrichWithGitHubReleases([]models.App{
+		{ID: "org.example.App", SourceRepo: &models.SourceRepo{Type: "github", Owner: "example", Repo: "app"}},
+	})
+
+	releases := apps[0].Releases
+	if len(releases) != 1 || releases[0].Version != "v2.0.0" {
+		t.Fatalf("Expected only the v2.0.0 release without the prerelease, got %+v", releases)
+	}
+	if !strings.Contains(releases[0].Description, "Fix the thing") {
+		t.Errorf("Expected notes from the entry's content, got %q", releases[0].Description)
+	}
+	if got := transport.Count("/commits"); got != 0 {
+		t.Errorf("Expected no commits requests on the feed path, got %d", got)
+	}
+}
@@ -13,6 +13,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/castrojo/bluefin-releases/internal/dates"
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/lastcommit"
 	"github.com/castrojo/bluefin-releases/internal/markdown"
 	"github.com/castrojo/bluefin-releases/internal/models"
 )
@@ -29,6 +32,30 @@ type GitLabRelease struct {
 	} `json:"_links"`
 }
 
+// Options controls optional GitLab data collection
+type Options struct {
+	TagMessages bool // Fill empty release notes from annotated tag messages (one extra request per release)
+	LastCommit  bool // Record the default branch's last commit date (one extra request per repo)
+}
+
+var options Options
+
+// Configure sets the options used by subsequent enrichment runs
+func Configure(o Options) {
+	options = o
+}
+
+// gitlabCommit is the subset of the GitLab commits API response we use
+type gitlabCommit struct {
+	CommittedDate time.Time `json:"committed_date"`
+}
+
+// gitlabTag is the subset of the GitLab tags API response we use
+type gitlabTag struct {
+	Name    string `json:"name"`
+	Message string `json:"message"` // Empty for lightweight tags
+}
+
 // EnrichWithGitLabReleases fetches GitLab releases for apps with GitLab repos
 // and adds them to the app's release list (prioritizing actual source changelogs)
 func EnrichWithGitLabReleases(apps []models.App) []models.App {
@@ -48,10 +75,13 @@ func EnrichWithGitLabReleases(apps []models.App) []models.App {
 	enrichedApps := make([]models.App, len(apps))
 	copy(enrichedApps, apps)
 
+	commits := lastcommit.NewCache()
+
 	// Process apps with GitLab repos in parallel
 	for i := range enrichedApps {
 		app := &enrichedApps[i]
 		if app.SourceRepo == nil || app.SourceRepo.Type != "gitlab" || app.SourceRepo.URL == "" {
+			app.Debug.Record(models.DebugStep{Stage: "gitlab", Matched: false})
 			continue
 		}
 
@@ -59,10 +89,18 @@ func EnrichWithGitLabReleases(apps []models.App) []models.App {
 		go func(app *models.App) {
 			defer wg.Done()
 
-			releases, err := fetchGitLabReleases(ctx, token, app.SourceRepo.URL, app.SourceRepo.Owner, app.SourceRepo.Repo)
+			if options.LastCommit {
+				repo := app.SourceRepo
+				app.LastCommitDate = commits.Date(repo.URL, func() (time.Time, error) {
+					return fetchLastCommitDate(ctx, token, repo)
+				})
+			}
+
+			releases, status, err := fetchGitLabReleases(ctx, token, app.SourceRepo)
 			if err != nil {
 				log.Printf("⚠️  Failed to fetch GitLab releases for %s: %v",
 					app.SourceRepo.URL, err)
+				app.Debug.Record(models.DebugStep{Stage: "gitlab", Matched: true, Status: status, Note: err.Error()})
 				return
 			}
 
@@ -70,6 +108,7 @@ func EnrichWithGitLabReleases(apps []models.App) []models.App {
 			// Prepend GitLab releases (they are from actual source, so prioritize them)
 			app.Releases = append(releases, app.Releases...)
 			log.Printf("✅ Added %d GitLab releases for %s", len(releases), app.ID)
+			app.Debug.Record(models.DebugStep{Stage: "gitlab", Matched: true, Status: status, ReleasesAdded: len(releases)})
 			mu.Unlock()
 
 			// Rate limiting: GitLab has a rate limit of 600 requests/15 minutes for unauthenticated
@@ -83,73 +122,189 @@ func EnrichWithGitLabReleases(apps []models.App) []models.App {
 }
 
 // fetchGitLabReleases fetches the latest releases from a GitLab repository
-// Supports both gitlab.com and self-hosted GitLab instances (like gitlab.gnome.org)
-func fetchGitLabReleases(ctx context.Context, token, repoURL, owner, repo string) ([]models.Release, error) {
+// Supports both gitlab.com and self-hosted GitLab instances (like gitlab.gnome.org).
+// Also returns the HTTP status of the API call (0 if no response was received).
+func fetchGitLabReleases(ctx context.Context, token string, repo *models.SourceRepo) ([]models.Release, int, error) {
+	projectAPI, err := projectAPIURL(repo)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Build the API URL
+	apiURL := projectAPI + "/releases?per_page=5"
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("create request: %w", err)
+	}
+
+	// Add authorization header if token is provided
+	if token != "" {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+
+	// Make the request
+	client := httpx.NewClientWith(httpx.ClientOptions{
+		Timeout:   10 * time.Second,
+		UserAgent: httpx.DefaultUserAgent,
+	})
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("fetch releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check response status
+	if resp.StatusCode == 404 {
+		// No releases found, not an error
+		return []models.Release{}, resp.StatusCode, nil
+	}
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, resp.StatusCode, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// Parse response
+	var gitlabReleases []GitLabRelease
+	if err := json.NewDecoder(resp.Body).Decode(&gitlabReleases); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("decode response: %w", err)
+	}
+
+	releases := convertGitLabReleases(gitlabReleases, httpx.WithScheme(repo.URL))
+	if options.TagMessages {
+		fillFromTagMessages(ctx, client, token, projectAPI, releases)
+	}
+	return releases, resp.StatusCode, nil
+}
+
+// projectAPIURL builds the API v4 URL of a project. Supports both gitlab.com
+// and self-hosted GitLab instances (like gitlab.gnome.org), preferring the
+// host recorded on the repo over the one in its URL.
+func projectAPIURL(repo *models.SourceRepo) (string, error) {
+	repoURL := repo.URL
+
 	// Parse the repository URL to extract the GitLab host and project path
-	parsedURL, err := url.Parse(repoURL)
+	parsedURL, err := url.Parse(httpx.WithScheme(repoURL))
 	if err != nil {
-		return nil, fmt.Errorf("parse repo URL: %w", err)
+		return "", fmt.Errorf("parse repo URL: %w", err)
 	}
 
-	gitlabHost := parsedURL.Host
+	gitlabHost := repo.Host
+	if gitlabHost == "" {
+		gitlabHost = parsedURL.Host
+	}
 	if gitlabHost == "" {
 		gitlabHost = "gitlab.com"
 	}
 
 	// Build the project path (owner/repo)
-	projectPath := owner + "/" + repo
-	if owner == "" || repo == "" {
+	projectPath := repo.Owner + "/" + repo.Repo
+	if repo.Owner == "" || repo.Repo == "" {
 		// Try to extract from URL path
 		pathParts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
 		if len(pathParts) >= 2 {
 			projectPath = strings.Join(pathParts, "/")
 		} else {
-			return nil, fmt.Errorf("invalid project path in URL: %s", repoURL)
+			return "", fmt.Errorf("invalid project path in URL: %s", repoURL)
 		}
 	}
 
 	// URL-encode the project path (GitLab requires this)
-	encodedPath := url.PathEscape(projectPath)
-
-	// Build the API URL
-	apiURL := fmt.Sprintf("https://%s/api/v4/projects/%s/releases?per_page=5", gitlabHost, encodedPath)
+	return fmt.Sprintf("https://%s/api/v4/projects/%s", gitlabHost, url.PathEscape(projectPath)), nil
+}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+// fetchLastCommitDate returns the commit date of the newest commit on the
+// project's default branch
+func fetchLastCommitDate(ctx context.Context, token string, repo *models.SourceRepo) (time.Time, error) {
+	projectAPI, err := projectAPIURL(repo)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return time.Time{}, err
 	}
 
-	// Add authorization header if token is provided
+	req, err := http.NewRequestWithContext(ctx, "GET", projectAPI+"/repository/commits?per_page=1", nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("create request: %w", err)
+	}
 	if token != "" {
 		req.Header.Set("PRIVATE-TOKEN", token)
 	}
 
-	// Make the request
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpx.NewClientWith(httpx.ClientOptions{
+		Timeout:   10 * time.Second,
+		UserAgent: httpx.DefaultUserAgent,
+	})
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("fetch releases: %w", err)
+		return time.Time{}, fmt.Errorf("fetch commits: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode == 404 {
-		// No releases found, not an error
-		return []models.Release{}, nil
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+
+	var commits []gitlabCommit
+	if err := json.NewDecoder(resp.Body).Decode(&commits); err != nil {
+		return time.Time{}, fmt.Errorf("decode response: %w", err)
+	}
+	if len(commits) == 0 || commits[0].CommittedDate.IsZero() {
+		return time.Time{}, fmt.Errorf("no commits")
 	}
+	return commits[0].CommittedDate, nil
+}
 
-	// Parse response
-	var gitlabReleases []GitLabRelease
-	if err := json.NewDecoder(resp.Body).Decode(&gitlabReleases); err != nil {
+// fillFromTagMessages uses the annotated tag message as the notes of releases
+// published without a description
+func fillFromTagMessages(ctx context.Context, client *http.Client, token, projectAPI string, releases []models.Release) {
+	for i := range releases {
+		release := &releases[i]
+		if strings.TrimSpace(release.DescriptionSource) != "" {
+			continue
+		}
+
+		tag, err := fetchTag(ctx, client, token, projectAPI, release.Version)
+		if err != nil {
+			log.Printf("⚠️  Failed to fetch tag %s from %s: %v", release.Version, projectAPI, err)
+			continue
+		}
+		if message := strings.TrimSpace(tag.Message); message != "" {
+			release.DescriptionSource = message
+			release.Description = markdown.ToHTML(message)
+		}
+	}
+}
+
+// fetchTag fetches a single tag from the GitLab tags API
+func fetchTag(ctx context.Context, client *http.Client, token, projectAPI, name string) (*gitlabTag, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", projectAPI+"/repository/tags/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch tag: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	var tag gitlabTag
+	if err := json.NewDecoder(resp.Body).Decode(&tag); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
+	return &tag, nil
+}
 
-	// Convert to models.Release
+// convertGitLabReleases maps GitLab API releases to the output model,
+// rendering markdown descriptions to HTML like the GitHub path does
+func convertGitLabReleases(gitlabReleases []GitLabRelease, repoURL string) []models.Release {
 	var releases []models.Release
 	for _, gr := range gitlabReleases {
 		if gr.TagName == "" {
@@ -161,29 +316,35 @@ func fetchGitLabReleases(ctx context.Context, token, repoURL, owner, repo string
 			date = gr.CreatedAt
 		}
 		if date.IsZero() {
-			date = time.Now()
-			log.Printf("⚠️  GitLab release %s for %s has no released_at or created_at date, using current time", gr.TagName, repoURL)
+			var keep bool
+			if date, keep = dates.Fallback(); !keep {
+				log.Printf("⚠️  GitLab release %s for %s has no released_at or created_at date, dropping it", gr.TagName, repoURL)
+				continue
+			}
+			log.Printf("⚠️  GitLab release %s for %s has no released_at or created_at date, using fallback date", gr.TagName, repoURL)
 		}
 
-		title := gr.TagName
-		if gr.Name != "" {
-			title = gr.Name
-		}
+		// Unnamed releases are titled from the app and version later, like other sources
+		title := strings.TrimSpace(gr.Name)
 
 		description := markdown.ToHTML(gr.Description)
 
-		// Build release URL
-		releaseURL := fmt.Sprintf("%s/-/releases/%s", strings.TrimSuffix(repoURL, ".git"), gr.TagName)
+		// Prefer the API's web link, building one only for older instances that omit it
+		releaseURL := gr.Links.Self
+		if releaseURL == "" {
+			releaseURL = fmt.Sprintf("%s/-/releases/%s", strings.TrimSuffix(repoURL, ".git"), gr.TagName)
+		}
 
 		releases = append(releases, models.Release{
-			Version:     gr.TagName,
-			Date:        date,
-			Title:       title,
-			Description: description,
-			URL:         releaseURL,
-			Type:        "gitlab-release",
+			Version:           gr.TagName,
+			Date:              date.UTC(),
+			Title:             title,
+			Description:       description,
+			DescriptionSource: gr.Description,
+			URL:               releaseURL,
+			Type:              "gitlab-release",
 		})
 	}
 
-	return releases, nil
+	return releases
 }
@@ -2,8 +2,12 @@ package gitlab
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
 	"github.com/castrojo/bluefin-releases/internal/models"
 )
 
@@ -47,7 +51,7 @@ func TestFetchGitLabReleases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			releases, err := fetchGitLabReleases(ctx, token, tt.repoURL, tt.owner, tt.repo)
+			releases, _, err := fetchGitLabReleases(ctx, token, &models.SourceRepo{URL: tt.repoURL, Owner: tt.owner, Repo: tt.repo})
 
 			if tt.wantError {
 				if err == nil {
@@ -81,6 +85,52 @@ func TestFetchGitLabReleases(t *testing.T) {
 	}
 }
 
+func TestProjectAPIURLNestedGroups(t *testing.T) {
+	tests := []struct {
+		repoURL string
+		host    string
+		owner   string
+		repo    string
+		want    string
+	}{
+		{
+			repoURL: "https://invent.kde.org/plasma-mobile/apps/angelfish",
+			owner:   "plasma-mobile/apps",
+			repo:    "angelfish",
+			want:    "https://invent.kde.org/api/v4/projects/plasma-mobile%2Fapps%2Fangelfish",
+		},
+		{
+			repoURL: "https://gitlab.freedesktop.org/xorg/app/xeyes",
+			owner:   "xorg/app",
+			repo:    "xeyes",
+			want:    "https://gitlab.freedesktop.org/api/v4/projects/xorg%2Fapp%2Fxeyes",
+		},
+		{
+			repoURL: "invent.kde.org/utilities/kate",
+			owner:   "utilities",
+			repo:    "kate",
+			want:    "https://invent.kde.org/api/v4/projects/utilities%2Fkate",
+		},
+		{
+			repoURL: "invent.kde.org/utilities/kate",
+			host:    "invent.kde.org",
+			owner:   "utilities",
+			repo:    "kate",
+			want:    "https://invent.kde.org/api/v4/projects/utilities%2Fkate",
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := projectAPIURL(&models.SourceRepo{URL: tt.repoURL, Host: tt.host, Owner: tt.owner, Repo: tt.repo})
+		if err != nil {
+			t.Fatalf("projectAPIURL(%s) failed: %v", tt.repoURL, err)
+		}
+		if got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+	}
+}
+
 func TestEnrichWithGitLabReleases(t *testing.T) {
 	apps := []models.App{
 		{
@@ -147,3 +197,91 @@ func TestEnrichWithGitLabReleases(t *testing.T) {
 		t.Error("Original release was lost during enrichment")
 	}
 }
+
+func TestEnrichWithGitLabReleasesRendersHTML(t *testing.T) {
+	payload := `[{
+  "tag_name": "46.1",
+  "name": "File Roller 46.1",
+  "description": "## Changes\n\n- Fixed **extraction** of large archives",
+  "released_at": "2026-02-01T10:00:00Z",
+  "_links": {"self": "https://gitlab.gnome.org/GNOME/file-roller/-/releases/46.1"}
+}]`
+	defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{"": payload}))()
+
+	apps := EnrichWithGitLabReleases([]models.App{{
+		ID: "org.gnome.FileRoller",
+		SourceRepo: &models.SourceRepo{
+			Type:  "gitlab",
+			URL:   "https://gitlab.gnome.org/GNOME/file-roller",
+			Owner: "GNOME",
+			Repo:  "file-roller",
+		},
+	}})
+
+	if len(apps[0].Releases) != 1 {
+		t.Fatalf("Expected 1 release, got %d", len(apps[0].Releases))
+	}
+	release := apps[0].Releases[0]
+
+	if !strings.Contains(release.Description, "<strong>extraction</strong>") || strings.Contains(release.Description, "**") {
+		t.Errorf("Expected HTML description, got %q", release.Description)
+	}
+	if release.DescriptionSource != "## Changes\n\n- Fixed **extraction** of large archives" {
+		t.Errorf("Expected markdown source preserved, got %q", release.DescriptionSource)
+	}
+	if release.Version != "46.1" || release.Title != "File Roller 46.1" {
+		t.Errorf("Expected version 46.1 titled File Roller 46.1, got %s / %s", release.Version, release.Title)
+	}
+	if !release.Date.Equal(time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected released_at date, got %s", release.Date)
+	}
+	if release.URL != "https://gitlab.gnome.org/GNOME/file-roller/-/releases/46.1" {
+		t.Errorf("Expected _links.self URL, got %s", release.URL)
+	}
+	if release.Type != "gitlab-release" {
+		t.Errorf("Expected type gitlab-release, got %s", release.Type)
+	}
+}
+
+func TestEnrichWithGitLabReleasesTagMessages(t *testing.T) {
+	// A release without notes and its annotated tag
+	defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{
+		"":                       `[{"tag_name": "3.2.0", "name": "3.2.0", "description": "", "released_at": "2026-02-01T10:00:00Z"}]`,
+		"/repository/tags/3.2.0": `{"name": "3.2.0", "message": "Version 3.2.0\n\n- Faster **startup**"}`,
+	}))()
+	app := models.App{
+		ID:         "org.example.Tagged",
+		SourceRepo: &models.SourceRepo{Type: "gitlab", URL: "https://gitlab.com/example/tagged", Owner: "example", Repo: "tagged"},
+	}
+
+	apps := EnrichWithGitLabReleases([]models.App{app})
+	if got := apps[0].Releases[0].Description; got != "" {
+		t.Errorf("Expected no tag lookup by default, got %q", got)
+	}
+
+	Configure(Options{TagMessages: true})
+	defer Configure(Options{})
+
+	apps = EnrichWithGitLabReleases([]models.App{app})
+	release := apps[0].Releases[0]
+	if release.DescriptionSource != "Version 3.2.0\n\n- Faster **startup**" {
+		t.Errorf("Expected tag message as notes, got %q", release.DescriptionSource)
+	}
+	if !strings.Contains(release.Description, "<strong>startup</strong>") {
+		t.Errorf("Expected rendered tag message, got %q", release.Description)
+	}
+}
+
+func TestEnrichWithGitLabReleasesSelfHostedWithoutScheme(t *testing.T) {
+	transport := httpxtest.Bodies(map[string]string{"": "[]"})
+	defer httpx.SetBaseTransport(transport)()
+
+	EnrichWithGitLabReleases([]models.App{{
+		ID:         "org.kde.kate",
+		SourceRepo: &models.SourceRepo{Type: "gitlab", URL: "invent.kde.org/utilities/kate", Host: "invent.kde.org", Owner: "utilities", Repo: "kate"},
+	}})
+
+	if urls := transport.URLs(); len(urls) != 1 || !strings.HasPrefix(urls[0], "https://invent.kde.org/") {
+		t.Errorf("Expected one request to invent.kde.org, got %v", urls)
+	}
+}
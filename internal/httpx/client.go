@@ -0,0 +1,86 @@
+package httpx
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultUserAgent identifies the pipeline to upstream APIs (GitHub rejects
+// requests without one)
+const DefaultUserAgent = "bluefin-releases"
+
+// ClientOptions configures a client built by NewClientWith. Every client shares
+// the governed transport, so request limits (Configure), the response cache and
+// offline mode, and test transports (SetBaseTransport) apply regardless of the
+// options chosen here. Proxies come from HTTP_PROXY/HTTPS_PROXY/NO_PROXY via
+// the default base transport.
+type ClientOptions struct {
+	Timeout   time.Duration // Whole-request timeout including retries; zero means none
+	UserAgent string        // Sent when a request doesn't set its own; empty leaves Go's default
+	Retry     *RetryPolicy  // Retry transient failures transparently (see Do); nil disables
+
+	// GitHubTokenFallback retries an anonymous request that GitHub rate
+	// limited once with GITHUB_TOKEN, when it's set (see githubTokenTransport)
+	GitHubTokenFallback bool
+
+	// CacheSource declares which freshness policy (see SourceTTL) applies to
+	// this client's GETs; empty means responses are only recorded for -offline
+	CacheSource string
+}
+
+// NewClientWith returns an HTTP client configured by opts
+func NewClientWith(opts ClientOptions) *http.Client {
+	var transport http.RoundTripper = sharedTransport
+	if opts.CacheSource != "" {
+		transport = &sourceTransport{next: transport, source: opts.CacheSource}
+	}
+	if opts.GitHubTokenFallback {
+		transport = &githubTokenTransport{next: transport}
+	}
+	if opts.Retry != nil {
+		transport = &retryTransport{next: transport, policy: *opts.Retry}
+	}
+	if opts.UserAgent != "" {
+		transport = &userAgentTransport{next: transport, userAgent: opts.UserAgent}
+	}
+
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: transport,
+	}
+}
+
+// userAgentTransport sets a User-Agent on requests that don't carry one
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") != "" {
+		return t.next.RoundTrip(req)
+	}
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
+
+// retryTransport applies Do's retry policy to every request
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Redirects are followed by the outer client, not per attempt
+	client := &http.Client{
+		Transport: t.next,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return Do(client, req, t.policy)
+}
@@ -0,0 +1,90 @@
+package httpx
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+)
+
+func TestNewClientWithUserAgent(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      ClientOptions
+		requestUA string
+		want      string
+	}{
+		{name: "sets default", opts: ClientOptions{UserAgent: DefaultUserAgent}, want: DefaultUserAgent},
+		{name: "keeps request's own", opts: ClientOptions{UserAgent: DefaultUserAgent}, requestUA: "custom/1.0", want: "custom/1.0"},
+		{name: "unset leaves request alone", opts: ClientOptions{}, requestUA: "custom/1.0", want: "custom/1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := httpxtest.Bodies(map[string]string{"": "ok"})
+			defer SetBaseTransport(transport)()
+
+			req, _ := http.NewRequest("GET", "https://flathub.org/api/v2/appstream/org.gnome.Maps", nil)
+			if tt.requestUA != "" {
+				req.Header.Set("User-Agent", tt.requestUA)
+			}
+			resp, err := NewClientWith(tt.opts).Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			if userAgents := transport.Headers("User-Agent"); len(userAgents) != 1 || userAgents[0] != tt.want {
+				t.Errorf("Expected User-Agent %q, got %v", tt.want, userAgents)
+			}
+			if got := req.Header.Get("User-Agent"); got != tt.requestUA {
+				t.Errorf("Expected caller's request to be left unmodified, got User-Agent %q", got)
+			}
+		})
+	}
+}
+
+func TestNewClientWithRetry(t *testing.T) {
+	policy := RetryPolicy{Attempts: 3, BaseDelay: time.Millisecond}
+
+	tests := []struct {
+		name       string
+		retry      *RetryPolicy
+		wantStatus int
+		wantCalls  int
+	}{
+		{name: "retries when configured", retry: &policy, wantStatus: 200, wantCalls: 2},
+		{name: "single attempt by default", wantStatus: 503, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &httpxtest.Script{Statuses: []int{503, 200}}
+			defer SetBaseTransport(transport)()
+
+			resp, err := NewClientWith(ClientOptions{Retry: tt.retry}).Get("https://formulae.brew.sh/api/formula/git.json")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			if got := transport.Calls(); got != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestNewClientWithTimeout(t *testing.T) {
+	client := NewClientWith(ClientOptions{Timeout: 5 * time.Second})
+	if client.Timeout != 5*time.Second {
+		t.Errorf("Expected timeout 5s, got %v", client.Timeout)
+	}
+	if NewClient(0).Transport != sharedTransport {
+		t.Error("Expected NewClient to use the shared governed transport directly")
+	}
+}
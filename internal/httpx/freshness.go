@@ -0,0 +1,153 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/cache"
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// Cache sources a client can declare with ClientOptions.CacheSource. GETs
+// from a declared source are answered from the response cache while fresh.
+const (
+	SourceBrewfile       = "brewfile"
+	SourceFlathub        = "flathub"
+	SourceGitHubReleases = "github-releases"
+	SourceHomebrew       = "homebrew"
+)
+
+// sourceTTLs is how long each source's cached responses stay fresh. Brewfiles
+// change with every app set edit; Flathub metadata barely moves within a day.
+var sourceTTLs = map[string]time.Duration{
+	SourceBrewfile:       time.Hour,
+	SourceFlathub:        24 * time.Hour,
+	SourceGitHubReleases: 6 * time.Hour,
+}
+
+// defaultTTLSources are served while fresh like the sources above, but stay
+// fresh for the response cache default (-cache-ttl) instead of a fixed TTL
+var defaultTTLSources = map[string]bool{
+	SourceHomebrew: true,
+}
+
+// SourceTTL returns how long responses from source stay fresh, or zero for
+// sources that are only recorded (for -offline) or use the cache default
+func SourceTTL(source string) time.Duration {
+	return sourceTTLs[source]
+}
+
+// servedFresh reports whether fresh responses from source are answered from the cache
+func servedFresh(source string) bool {
+	return SourceTTL(source) > 0 || defaultTTLSources[source]
+}
+
+type sourceKey struct{}
+
+// withSource tags a request with the cache source of the client sending it
+func withSource(req *http.Request, source string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), sourceKey{}, source))
+}
+
+func requestSource(req *http.Request) string {
+	source, _ := req.Context().Value(sourceKey{}).(string)
+	return source
+}
+
+// sourceTransport tags requests with a cache source
+type sourceTransport struct {
+	next   http.RoundTripper
+	source string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *sourceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(withSource(req, t.source))
+}
+
+var (
+	freshnessMu  sync.Mutex
+	refreshCache bool
+	cacheStats   = make(map[string]*models.CacheStat)
+)
+
+// SetRefreshCache makes every source bypass fresh cache entries and fetch
+// again; responses are still recorded for later runs
+func SetRefreshCache(enabled bool) {
+	freshnessMu.Lock()
+	defer freshnessMu.Unlock()
+	refreshCache = enabled
+}
+
+// CacheStats returns per-source cache outcomes recorded so far this run
+func CacheStats() map[string]models.CacheStat {
+	freshnessMu.Lock()
+	defer freshnessMu.Unlock()
+
+	result := make(map[string]models.CacheStat, len(cacheStats))
+	for source, stats := range cacheStats {
+		result[source] = *stats
+	}
+	return result
+}
+
+// ResetCacheStats discards all recorded cache outcomes
+func ResetCacheStats() {
+	freshnessMu.Lock()
+	defer freshnessMu.Unlock()
+	cacheStats = make(map[string]*models.CacheStat)
+}
+
+// lookupFresh serves a GET from the response cache when its source's entry
+// is still fresh, counting the outcome. ok is false when the request must go
+// to the network.
+func lookupFresh(c cache.Cache, req *http.Request) (*http.Response, bool) {
+	source := requestSource(req)
+	if req.Method != http.MethodGet || !servedFresh(source) {
+		return nil, false
+	}
+
+	freshnessMu.Lock()
+	refresh := refreshCache
+	stats, ok := cacheStats[source]
+	if !ok {
+		stats = &models.CacheStat{}
+		cacheStats[source] = stats
+	}
+	freshnessMu.Unlock()
+
+	count := func(field *int) {
+		freshnessMu.Lock()
+		*field++
+		freshnessMu.Unlock()
+	}
+
+	key := responseKey(req)
+	if refresh {
+		count(&stats.Bypassed)
+		return nil, false
+	}
+	if data, ok := c.Get(key); ok {
+		if resp, ok := decodeCached(data, req); ok {
+			count(&stats.Hits)
+			return resp, true
+		}
+	}
+	if _, ok := c.GetStale(key); ok {
+		count(&stats.Refreshes)
+	} else {
+		count(&stats.Misses)
+	}
+	return nil, false
+}
+
+// recordTTL is how long a response to req is kept: its source's freshness
+// when declared, otherwise the response cache default
+func recordTTL(req *http.Request, fallback time.Duration) time.Duration {
+	if ttl := SourceTTL(requestSource(req)); ttl > 0 {
+		return ttl
+	}
+	return fallback
+}
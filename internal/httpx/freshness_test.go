@@ -0,0 +1,105 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/cache"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestSourceFreshness(t *testing.T) {
+	const url = "https://raw.githubusercontent.com/ublue-os/bluefin/main/Brewfile"
+
+	tests := []struct {
+		name      string
+		seedTTL   time.Duration // Zero leaves the cache empty
+		refresh   bool
+		source    string
+		wantCalls int
+		wantStats models.CacheStat
+	}{
+		{name: "miss fetches", source: SourceBrewfile, wantCalls: 1, wantStats: models.CacheStat{Misses: 1}},
+		{name: "fresh hit skips network", seedTTL: time.Hour, source: SourceBrewfile, wantCalls: 0, wantStats: models.CacheStat{Hits: 1}},
+		{name: "stale entry refreshes", seedTTL: -time.Second, source: SourceBrewfile, wantCalls: 1, wantStats: models.CacheStat{Refreshes: 1}},
+		{name: "refresh flag bypasses fresh entry", seedTTL: time.Hour, refresh: true, source: SourceBrewfile, wantCalls: 1, wantStats: models.CacheStat{Bypassed: 1}},
+		{name: "default-TTL source fresh hit skips network", seedTTL: time.Hour, source: SourceHomebrew, wantCalls: 0, wantStats: models.CacheStat{Hits: 1}},
+		{name: "undeclared source is never served online", seedTTL: time.Hour, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := cache.NewDisk(t.TempDir())
+			SetResponseCache(responses, 24*time.Hour)
+			defer SetResponseCache(nil, 0)
+			SetRefreshCache(tt.refresh)
+			defer SetRefreshCache(false)
+			ResetCacheStats()
+			defer ResetCacheStats()
+
+			if tt.seedTTL != 0 {
+				req, _ := http.NewRequest("GET", url, nil)
+				data, _ := encodeCached(http.StatusOK, nil, []byte("cached"))
+				if err := responses.Set(responseKey(req), data, tt.seedTTL); err != nil {
+					t.Fatalf("Failed to seed cache: %v", err)
+				}
+			}
+
+			transport := &httpxtest.Script{Statuses: []int{200}}
+			defer SetBaseTransport(transport)()
+
+			resp, err := NewClientWith(ClientOptions{CacheSource: tt.source}).Get(url)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if got := transport.Calls(); got != tt.wantCalls {
+				t.Errorf("Expected %d network calls, got %d", tt.wantCalls, got)
+			}
+			wantBody := "body"
+			if tt.wantCalls == 0 {
+				wantBody = "cached"
+			}
+			if string(body) != wantBody {
+				t.Errorf("Expected body %q, got %q", wantBody, body)
+			}
+			if got := CacheStats()[tt.source]; tt.source != "" && got != tt.wantStats {
+				t.Errorf("Expected stats %+v, got %+v", tt.wantStats, got)
+			}
+			if tt.source == "" && len(CacheStats()) != 0 {
+				t.Errorf("Expected no stats for undeclared source, got %v", CacheStats())
+			}
+
+			// The network response is recorded for the next run
+			if tt.wantCalls > 0 {
+				req, _ := http.NewRequest("GET", url, nil)
+				if _, ok := responses.Get(responseKey(req)); !ok {
+					t.Error("Expected fetched response to be cached fresh")
+				}
+			}
+		})
+	}
+}
+
+func TestSourceTTLs(t *testing.T) {
+	tests := []struct {
+		source string
+		want   time.Duration
+	}{
+		{SourceBrewfile, time.Hour},
+		{SourceFlathub, 24 * time.Hour},
+		{SourceGitHubReleases, 6 * time.Hour},
+		{SourceHomebrew, 0}, // Follows the response cache default
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := SourceTTL(tt.source); got != tt.want {
+			t.Errorf("SourceTTL(%q): expected %v, got %v", tt.source, tt.want, got)
+		}
+	}
+}
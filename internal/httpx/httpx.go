@@ -0,0 +1,303 @@
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits controls the request governor shared by every client created by this package.
+// A zero value for any field means "unlimited".
+type Limits struct {
+	MaxInFlight              int     // Maximum concurrent requests across all hosts
+	RequestsPerSecond        float64 // Maximum request rate across all hosts
+	PerHostMaxInFlight       int     // Maximum concurrent requests to a single host
+	PerHostRequestsPerSecond float64 // Maximum request rate to a single host
+}
+
+// DefaultLimits keeps the whole pipeline well below what any single upstream tolerates
+var DefaultLimits = Limits{
+	MaxInFlight:        32,
+	PerHostMaxInFlight: 10,
+}
+
+var (
+	governorMu sync.RWMutex
+	current    = newGovernor(DefaultLimits)
+
+	// sharedTransport is used by every client so limits apply pipeline-wide,
+	// regardless of which package created the client
+	sharedTransport = &governedTransport{}
+
+	// baseTransport performs requests once the governor admits them
+	baseTransport http.RoundTripper = http.DefaultTransport
+
+	// runCtx bounds every request; once done, in-flight requests are
+	// cancelled and new ones fail immediately
+	runCtx = context.Background()
+)
+
+// Configure replaces the global limits. Requests already in flight keep the
+// limits they were admitted under.
+func Configure(limits Limits) {
+	governorMu.Lock()
+	defer governorMu.Unlock()
+	current = newGovernor(limits)
+}
+
+// SetBaseTransport replaces the transport used underneath the governor and
+// returns a function restoring the previous one. Intended for tests that need
+// to serve fixtures to fetchers without touching the network.
+func SetBaseTransport(rt http.RoundTripper) func() {
+	governorMu.Lock()
+	defer governorMu.Unlock()
+
+	previous := baseTransport
+	baseTransport = rt
+	return func() {
+		governorMu.Lock()
+		defer governorMu.Unlock()
+		baseTransport = previous
+	}
+}
+
+// SetRunContext bounds every request made through this package's clients by
+// ctx, on top of each request's own context, and returns a function restoring
+// the previous one. Used to enforce an absolute ceiling on a run's network work.
+func SetRunContext(ctx context.Context) func() {
+	governorMu.Lock()
+	defer governorMu.Unlock()
+
+	previous := runCtx
+	runCtx = ctx
+	return func() {
+		governorMu.Lock()
+		defer governorMu.Unlock()
+		runCtx = previous
+	}
+}
+
+// runContext returns the context bounding all requests
+func runContext() context.Context {
+	governorMu.RLock()
+	defer governorMu.RUnlock()
+	return runCtx
+}
+
+// NewClient returns an HTTP client whose requests go through the shared governor.
+// A zero timeout means no timeout, matching http.Client semantics.
+func NewClient(timeout time.Duration) *http.Client {
+	return NewClientWith(ClientOptions{Timeout: timeout})
+}
+
+// Transport returns the shared governed transport for clients built elsewhere
+// (e.g. go-github via oauth2)
+func Transport() http.RoundTripper {
+	return sharedTransport
+}
+
+// WithScheme adds https:// to scheme-less URLs like "gitlab.com/owner/repo",
+// as upstream metadata often lists repos
+func WithScheme(rawURL string) string {
+	if !strings.Contains(rawURL, "://") {
+		return "https://" + rawURL
+	}
+	return rawURL
+}
+
+func currentGovernor() (*governor, http.RoundTripper) {
+	governorMu.RLock()
+	defer governorMu.RUnlock()
+	return current, baseTransport
+}
+
+// governedTransport admits each request through the global and per-host limiters
+type governedTransport struct{}
+
+// RoundTrip implements http.RoundTripper
+func (t *governedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	responses, ttl, offline := replaySettings()
+	if offline {
+		return replay(responses, req)
+	}
+	if responses != nil {
+		if resp, ok := lookupFresh(responses, req); ok {
+			return resp, nil
+		}
+	}
+
+	run := runContext()
+	if err := run.Err(); err != nil {
+		return nil, fmt.Errorf("run aborted: %w", err)
+	}
+	ctx, cancel := context.WithCancel(req.Context())
+	stop := context.AfterFunc(run, cancel)
+	req = req.WithContext(ctx)
+	done := func() {
+		stop()
+		cancel()
+	}
+
+	gov, base := currentGovernor()
+	acquired, err := gov.acquire(ctx, req.URL.Host)
+	if err != nil {
+		done()
+		return nil, err
+	}
+	release := func() {
+		acquired()
+		done()
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	recordTiming(req.URL.Host, time.Since(start))
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	// Keep the slot until the caller has finished reading the body
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	if responses != nil {
+		return record(responses, ttl, req, resp)
+	}
+	return resp, nil
+}
+
+// releasingBody frees the governor slot once the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close implements io.Closer
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// governor combines a global limiter with lazily created per-host limiters
+type governor struct {
+	limits Limits
+	global *limiter
+
+	mu    sync.Mutex
+	hosts map[string]*limiter
+}
+
+func newGovernor(limits Limits) *governor {
+	return &governor{
+		limits: limits,
+		global: newLimiter(limits.MaxInFlight, limits.RequestsPerSecond),
+		hosts:  make(map[string]*limiter),
+	}
+}
+
+func (g *governor) host(name string) *limiter {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	l, ok := g.hosts[name]
+	if !ok {
+		l = newLimiter(g.limits.PerHostMaxInFlight, g.limits.PerHostRequestsPerSecond)
+		g.hosts[name] = l
+	}
+	return l
+}
+
+// acquire blocks until the request may proceed and returns a release function
+func (g *governor) acquire(ctx context.Context, host string) (func(), error) {
+	hostLimiter := g.host(host)
+
+	// Take the per-host slot first so a slow host can't hog global slots while waiting
+	if err := hostLimiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	if err := g.global.acquire(ctx); err != nil {
+		hostLimiter.release()
+		return nil, err
+	}
+
+	return func() {
+		g.global.release()
+		hostLimiter.release()
+	}, nil
+}
+
+// limiter is a concurrency semaphore combined with a simple request spacer
+type limiter struct {
+	slots    chan struct{} // nil when concurrency is unlimited
+	interval time.Duration // zero when rate is unlimited
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newLimiter(maxInFlight int, rps float64) *limiter {
+	l := &limiter{}
+	if maxInFlight > 0 {
+		l.slots = make(chan struct{}, maxInFlight)
+	}
+	if rps > 0 {
+		l.interval = time.Duration(float64(time.Second) / rps)
+	}
+	return l
+}
+
+func (l *limiter) acquire(ctx context.Context) error {
+	if err := l.wait(ctx); err != nil {
+		return err
+	}
+	if l.slots == nil {
+		return nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *limiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// wait reserves the next send time and sleeps until it arrives
+func (l *limiter) wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
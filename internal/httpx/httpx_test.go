@@ -0,0 +1,171 @@
+package httpx
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// concurrencyTransport holds each request briefly and records how many were
+// in flight, overall and per host, and when each one started
+type concurrencyTransport struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	hosts       map[string]int
+	maxPerHost  map[string]int
+	starts      map[string][]time.Time
+}
+
+func newConcurrencyTransport() *concurrencyTransport {
+	return &concurrencyTransport{
+		hosts:      make(map[string]int),
+		maxPerHost: make(map[string]int),
+		starts:     make(map[string][]time.Time),
+	}
+}
+
+func (t *concurrencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+
+	t.mu.Lock()
+	t.inFlight++
+	t.hosts[host]++
+	t.maxInFlight = max(t.maxInFlight, t.inFlight)
+	t.maxPerHost[host] = max(t.maxPerHost[host], t.hosts[host])
+	t.starts[host] = append(t.starts[host], time.Now())
+	t.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	t.mu.Lock()
+	t.inFlight--
+	t.hosts[host]--
+	t.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("ok")),
+	}, nil
+}
+
+// fetchAll requests perHost URLs on each host concurrently through a governed client
+func fetchAll(t *testing.T, hosts []string, perHost int) {
+	t.Helper()
+	client := NewClient(0)
+
+	var wg sync.WaitGroup
+	for _, host := range hosts {
+		for i := 0; i < perHost; i++ {
+			wg.Add(1)
+			go func(url string) {
+				defer wg.Done()
+				resp, err := client.Get(url)
+				if err != nil {
+					t.Errorf("Request to %s failed: %v", url, err)
+					return
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}(fmt.Sprintf("https://%s/%d", host, i))
+		}
+	}
+	wg.Wait()
+}
+
+// minGap returns the smallest spacing between consecutive start times
+func minGap(starts []time.Time) time.Duration {
+	sorted := append([]time.Time(nil), starts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	gap := time.Duration(-1)
+	for i := 1; i < len(sorted); i++ {
+		if d := sorted[i].Sub(sorted[i-1]); gap < 0 || d < gap {
+			gap = d
+		}
+	}
+	return gap
+}
+
+func TestGovernorLimits(t *testing.T) {
+	hosts := []string{"a.test", "b.test", "c.test"}
+	// Timers can fire slightly early, so spacing is checked with some slack
+	const slack = 5 * time.Millisecond
+
+	t.Run("MaxInFlight", func(t *testing.T) {
+		transport := newConcurrencyTransport()
+		defer SetBaseTransport(transport)()
+		Configure(Limits{MaxInFlight: 3})
+		defer Configure(DefaultLimits)
+
+		fetchAll(t, hosts, 4)
+
+		if transport.maxInFlight > 3 {
+			t.Errorf("Expected at most 3 concurrent requests, got %d", transport.maxInFlight)
+		}
+		if transport.maxInFlight < 2 {
+			t.Errorf("Expected requests to run in parallel, got max %d", transport.maxInFlight)
+		}
+	})
+
+	t.Run("PerHostMaxInFlight", func(t *testing.T) {
+		transport := newConcurrencyTransport()
+		defer SetBaseTransport(transport)()
+		Configure(Limits{PerHostMaxInFlight: 2})
+		defer Configure(DefaultLimits)
+
+		fetchAll(t, hosts, 4)
+
+		for _, host := range hosts {
+			if got := transport.maxPerHost[host]; got > 2 {
+				t.Errorf("Expected at most 2 concurrent requests to %s, got %d", host, got)
+			}
+		}
+		if transport.maxInFlight <= 2 {
+			t.Errorf("Expected different hosts to run in parallel, got max %d overall", transport.maxInFlight)
+		}
+	})
+
+	t.Run("RequestsPerSecond", func(t *testing.T) {
+		transport := newConcurrencyTransport()
+		defer SetBaseTransport(transport)()
+		Configure(Limits{RequestsPerSecond: 20})
+		defer Configure(DefaultLimits)
+
+		fetchAll(t, hosts, 2)
+
+		var starts []time.Time
+		for _, host := range hosts {
+			starts = append(starts, transport.starts[host]...)
+		}
+		if gap := minGap(starts); gap < 50*time.Millisecond-slack {
+			t.Errorf("Expected requests spaced at least 50ms apart, got %s", gap)
+		}
+	})
+
+	t.Run("PerHostRequestsPerSecond", func(t *testing.T) {
+		transport := newConcurrencyTransport()
+		defer SetBaseTransport(transport)()
+		Configure(Limits{PerHostRequestsPerSecond: 20})
+		defer Configure(DefaultLimits)
+
+		fetchAll(t, hosts, 3)
+
+		var all []time.Time
+		for _, host := range hosts {
+			if gap := minGap(transport.starts[host]); gap < 50*time.Millisecond-slack {
+				t.Errorf("Expected requests to %s spaced at least 50ms apart, got %s", host, gap)
+			}
+			all = append(all, transport.starts[host]...)
+		}
+		if gap := minGap(all); gap >= 50*time.Millisecond-slack {
+			t.Errorf("Expected different hosts not to wait on each other, got min gap %s", gap)
+		}
+	})
+}
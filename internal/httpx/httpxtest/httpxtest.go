@@ -0,0 +1,222 @@
+// Package httpxtest provides stub HTTP transports for tests of the fetchers
+package httpxtest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Response is a canned reply. A zero Status means 200 OK.
+type Response struct {
+	Status int
+	Body   string
+	Header http.Header
+	Delay  time.Duration // Wait before answering, unless the request's context ends first
+}
+
+// Reply builds the *http.Response for req with status and body
+func Reply(req *http.Request, status int, body string) *http.Response {
+	return Response{Status: status, Body: body}.For(req)
+}
+
+// For builds the *http.Response answering req
+func (r Response) For(req *http.Request) *http.Response {
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(r.Body)),
+		Request:    req,
+	}
+}
+
+// Func adapts a function to an http.RoundTripper, for stubs whose reply
+// depends on more than the URL
+type Func func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req)
+func (f Func) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Hang never answers; requests only end when their context does
+func Hang() http.RoundTripper {
+	return Func(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+}
+
+// Status answers every request with status and an empty body
+func Status(status int) http.RoundTripper {
+	return Func(func(req *http.Request) (*http.Response, error) {
+		return Reply(req, status, ""), nil
+	})
+}
+
+// Transport serves canned responses keyed by URL. A key matches a request
+// whose full URL or escaped path ends with it, so a test can key on a whole
+// URL or just a path suffix, and the empty key matches everything; the
+// longest matching key wins. Unmatched requests get NotFound, or an empty 404
+// if that's unset. Transport records every request and, per key, the most
+// requests it answered at once, and is safe for concurrent use.
+type Transport struct {
+	Responses map[string]Response
+	NotFound  *Response
+
+	mu          sync.Mutex
+	requests    []*http.Request
+	inFlight    map[string]int
+	maxInFlight map[string]int
+}
+
+// Bodies returns a Transport answering each key with 200 OK and its body
+func Bodies(bodies map[string]string) *Transport {
+	responses := make(map[string]Response, len(bodies))
+	for key, body := range bodies {
+		responses[key] = Response{Body: body}
+	}
+	return &Transport{Responses: responses}
+}
+
+// RoundTrip answers req from Responses
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, response, ok := t.match(req)
+	if !ok {
+		response = Response{Status: http.StatusNotFound}
+		if t.NotFound != nil {
+			response = *t.NotFound
+		}
+	}
+
+	t.mu.Lock()
+	if t.inFlight == nil {
+		t.inFlight, t.maxInFlight = make(map[string]int), make(map[string]int)
+	}
+	t.requests = append(t.requests, req)
+	if ok {
+		t.inFlight[key]++
+		t.maxInFlight[key] = max(t.maxInFlight[key], t.inFlight[key])
+	}
+	t.mu.Unlock()
+	if ok {
+		defer func() {
+			t.mu.Lock()
+			t.inFlight[key]--
+			t.mu.Unlock()
+		}()
+	}
+
+	if response.Delay > 0 {
+		select {
+		case <-time.After(response.Delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	return response.For(req), nil
+}
+
+// match finds the longest key matching req and its response
+func (t *Transport) match(req *http.Request) (string, Response, bool) {
+	best, found := "", false
+	for key := range t.Responses {
+		if matches(req.URL, key) && (!found || len(key) > len(best)) {
+			best, found = key, true
+		}
+	}
+	return best, t.Responses[best], found
+}
+
+// matches reports whether u's full URL or escaped path ends with key
+func matches(u *url.URL, key string) bool {
+	return strings.HasSuffix(u.String(), key) || strings.HasSuffix(u.EscapedPath(), key)
+}
+
+// URLs returns the URLs requested so far, in order
+func (t *Transport) URLs() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	urls := make([]string, len(t.requests))
+	for i, req := range t.requests {
+		urls[i] = req.URL.String()
+	}
+	return urls
+}
+
+// Paths returns the paths requested so far, in order
+func (t *Transport) Paths() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	paths := make([]string, len(t.requests))
+	for i, req := range t.requests {
+		paths[i] = req.URL.Path
+	}
+	return paths
+}
+
+// Headers returns the named header of each request so far, in order
+func (t *Transport) Headers(name string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	values := make([]string, len(t.requests))
+	for i, req := range t.requests {
+		values[i] = req.Header.Get(name)
+	}
+	return values
+}
+
+// MaxInFlight returns the most requests key answered at once
+func (t *Transport) MaxInFlight(key string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.maxInFlight[key]
+}
+
+// Count returns how many requests so far matched key
+func (t *Transport) Count(key string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	count := 0
+	for _, req := range t.requests {
+		if matches(req.URL, key) {
+			count++
+		}
+	}
+	return count
+}
+
+// Script answers each request with the next status in turn, repeating the
+// last one; a 0 status is a network error
+type Script struct {
+	Statuses []int
+	calls    atomic.Int32
+}
+
+// RoundTrip answers req with the next scripted status
+func (s *Script) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := int(s.calls.Add(1)) - 1
+	status := s.Statuses[min(n, len(s.Statuses)-1)]
+	if status == 0 {
+		return nil, errors.New("connection reset")
+	}
+	return Reply(req, status, "body"), nil
+}
+
+// Calls returns how many requests the script has answered
+func (s *Script) Calls() int {
+	return int(s.calls.Load())
+}
@@ -0,0 +1,111 @@
+package httpxtest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTransport(t *testing.T) {
+	transport := &Transport{
+		Responses: map[string]Response{
+			"/releases":                               {Body: "any"},
+			"/repos/example/app/releases":             {Body: "app"},
+			"https://example.test/data.json":          {Body: "data", Header: http.Header{"Content-Type": []string{"application/json"}}},
+			"/projects/gnome%2Floupe/releases/latest": {Status: http.StatusGone},
+		},
+	}
+	client := &http.Client{Transport: transport}
+
+	tests := []struct {
+		url        string
+		wantStatus int
+		wantBody   string
+	}{
+		{url: "https://api.test/repos/example/app/releases", wantStatus: http.StatusOK, wantBody: "app"},
+		{url: "https://api.test/repos/other/tool/releases", wantStatus: http.StatusOK, wantBody: "any"},
+		{url: "https://example.test/data.json", wantStatus: http.StatusOK, wantBody: "data"},
+		{url: "https://gitlab.test/api/v4/projects/gnome%2Floupe/releases/latest", wantStatus: http.StatusGone},
+		{url: "https://api.test/missing", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		resp, err := client.Get(tt.url)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.url, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.wantStatus || string(body) != tt.wantBody {
+			t.Errorf("%s: expected %d %q, got %d %q", tt.url, tt.wantStatus, tt.wantBody, resp.StatusCode, body)
+		}
+	}
+
+	if got := transport.Count("/releases"); got != 2 {
+		t.Errorf("Expected 2 requests for /releases, got %d", got)
+	}
+	if got := len(transport.URLs()); got != len(tests) {
+		t.Errorf("Expected %d recorded URLs, got %d", len(tests), got)
+	}
+}
+
+func TestTransportRecordsRequests(t *testing.T) {
+	transport := &Transport{Responses: map[string]Response{"/slow": {Delay: 20 * time.Millisecond}}}
+	client := &http.Client{Transport: transport}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", "https://example.test/slow", nil)
+			req.Header.Set("User-Agent", fmt.Sprintf("agent/%d", i))
+			if resp, err := client.Do(req); err == nil {
+				resp.Body.Close()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := transport.MaxInFlight("/slow"); got < 2 {
+		t.Errorf("Expected delayed requests to overlap, got max %d", got)
+	}
+	agents := transport.Headers("User-Agent")
+	sort.Strings(agents)
+	if want := []string{"agent/0", "agent/1", "agent/2"}; !reflect.DeepEqual(agents, want) {
+		t.Errorf("Expected User-Agents %v, got %v", want, agents)
+	}
+	if paths := transport.Paths(); len(paths) != 3 || paths[0] != "/slow" {
+		t.Errorf("Expected three requests for /slow, got %v", paths)
+	}
+}
+
+func TestScript(t *testing.T) {
+	script := &Script{Statuses: []int{http.StatusServiceUnavailable, 0, http.StatusOK}}
+	client := &http.Client{Transport: script}
+
+	want := []int{http.StatusServiceUnavailable, 0, http.StatusOK, http.StatusOK}
+	for i, status := range want {
+		resp, err := client.Get("https://example.test/")
+		if status == 0 {
+			if err == nil {
+				t.Errorf("Call %d: expected a network error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Call %d: unexpected error: %v", i, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("Call %d: expected status %d, got %d", i, status, resp.StatusCode)
+		}
+	}
+	if got := script.Calls(); got != len(want) {
+		t.Errorf("Expected %d calls, got %d", len(want), got)
+	}
+}
@@ -0,0 +1,158 @@
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/cache"
+)
+
+// ErrOffline is returned for requests that can't be served from the cache in offline mode
+var ErrOffline = errors.New("offline: no cached response")
+
+var (
+	replayMu    sync.RWMutex
+	replayCache cache.Cache
+	replayTTL   time.Duration
+	offline     bool
+
+	missesMu sync.Mutex
+	misses   []string
+)
+
+// cachedResponse is the metadata of a recorded GET response. An entry is
+// this as one JSON line followed by the raw body, so large bodies (such as
+// Homebrew's bulk formula.json) aren't base64-encoded on top of whatever
+// encoding the cache backend applies.
+type cachedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+}
+
+// encodeCached serializes a response for the response cache
+func encodeCached(status int, header http.Header, body []byte) ([]byte, error) {
+	meta, err := json.Marshal(cachedResponse{StatusCode: status, Header: header})
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 0, len(meta)+1+len(body))
+	data = append(append(append(data, meta...), '\n'), body...)
+	return data, nil
+}
+
+// SetResponseCache records successful (200) and not-found (404) GET responses
+// into c so a later offline run can replay them. A nil or disabled cache
+// disables recording.
+func SetResponseCache(c cache.Cache, ttl time.Duration) {
+	if !cache.Enabled(c) {
+		c = nil
+	}
+	replayMu.Lock()
+	defer replayMu.Unlock()
+	replayCache = c
+	replayTTL = ttl
+}
+
+// ResponseCache returns the cache set by SetResponseCache, or cache.None when
+// caching is disabled, so fetchers with their own keys can share the backend
+func ResponseCache() cache.Cache {
+	replayMu.RLock()
+	defer replayMu.RUnlock()
+	if replayCache == nil {
+		return cache.None{}
+	}
+	return replayCache
+}
+
+// SetOffline forbids network access. GETs are answered from the response
+// cache regardless of age; anything else fails with ErrOffline.
+func SetOffline(enabled bool) {
+	replayMu.Lock()
+	defer replayMu.Unlock()
+	offline = enabled
+}
+
+// OfflineMisses returns the URLs that offline mode couldn't serve this run
+func OfflineMisses() []string {
+	missesMu.Lock()
+	defer missesMu.Unlock()
+	return append([]string(nil), misses...)
+}
+
+func replaySettings() (cache.Cache, time.Duration, bool) {
+	replayMu.RLock()
+	defer replayMu.RUnlock()
+	return replayCache, replayTTL, offline
+}
+
+// responseKey identifies a cached response by method and URL
+func responseKey(req *http.Request) string {
+	return "http/" + req.Method + " " + req.URL.String()
+}
+
+// replay serves req from the response cache in offline mode
+func replay(c cache.Cache, req *http.Request) (*http.Response, error) {
+	if c != nil && req.Method == http.MethodGet {
+		if data, ok := c.GetStale(responseKey(req)); ok {
+			if resp, ok := decodeCached(data, req); ok {
+				return resp, nil
+			}
+		}
+	}
+
+	missesMu.Lock()
+	misses = append(misses, req.URL.String())
+	missesMu.Unlock()
+	return nil, fmt.Errorf("%w for %s %s", ErrOffline, req.Method, req.URL)
+}
+
+// decodeCached rebuilds a response to req from a recorded entry
+func decodeCached(data []byte, req *http.Request) (*http.Response, bool) {
+	meta, body, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return nil, false
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(meta, &cached); err != nil {
+		return nil, false
+	}
+	return &http.Response{
+		StatusCode: cached.StatusCode,
+		Status:     fmt.Sprintf("%d %s", cached.StatusCode, http.StatusText(cached.StatusCode)),
+		Header:     cached.Header,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, true
+}
+
+// record stores resp in the response cache and returns an equivalent response
+// whose body can still be read by the caller
+func record(c cache.Cache, ttl time.Duration, req *http.Request, resp *http.Response) (*http.Response, error) {
+	if req.Method != http.MethodGet || (resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	data, err := encodeCached(resp.StatusCode, resp.Header, body)
+	if err == nil {
+		err = c.Set(responseKey(req), data, recordTTL(req, ttl))
+	}
+	if err != nil {
+		log.Printf("⚠️  Failed to record response for %s: %v", req.URL, err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,64 @@
+package httpx
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/cache"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+)
+
+func TestOfflineReplaysRecordedResponses(t *testing.T) {
+	responses := cache.NewDisk(t.TempDir())
+	defer SetResponseCache(nil, 0)
+	defer SetOffline(false)
+
+	fetch := func(url string) (int, string, error) {
+		resp, err := NewClient(0).Get(url)
+		if err != nil {
+			return 0, "", err
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body), nil
+	}
+
+	// Online run records the response
+	restore := SetBaseTransport(&httpxtest.Script{Statuses: []int{200}})
+	SetResponseCache(responses, time.Hour)
+	onlineStatus, onlineBody, err := fetch("https://flathub.org/api/v2/appstream/org.example.App")
+	restore()
+	if err != nil {
+		t.Fatalf("Unexpected online error: %v", err)
+	}
+
+	// The body is stored as is, not base64-encoded inside the entry
+	req, _ := http.NewRequest("GET", "https://flathub.org/api/v2/appstream/org.example.App", nil)
+	if data, ok := responses.Get(responseKey(req)); !ok || !bytes.HasSuffix(data, []byte("\n"+onlineBody)) {
+		t.Errorf("Expected the recorded entry to end with the raw body, got %q", data)
+	}
+
+	// Offline run must not touch the network
+	defer SetBaseTransport(&httpxtest.Script{Statuses: []int{0}})()
+	SetOffline(true)
+
+	status, body, err := fetch("https://flathub.org/api/v2/appstream/org.example.App")
+	if err != nil {
+		t.Fatalf("Unexpected offline error: %v", err)
+	}
+	if status != onlineStatus || body != onlineBody {
+		t.Errorf("Expected offline response %d %q to match online %d %q", status, body, onlineStatus, onlineBody)
+	}
+
+	_, _, err = fetch("https://flathub.org/api/v2/appstream/org.example.Missing")
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("Expected ErrOffline for uncached URL, got %v", err)
+	}
+	if misses := OfflineMisses(); len(misses) != 1 {
+		t.Errorf("Expected 1 offline miss, got %v", misses)
+	}
+}
@@ -0,0 +1,102 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrRateLimited marks errors caused by an upstream API rate limit, so callers
+// can tell them apart from other upstream failures
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RetryPolicy controls how Do retries transient failures
+type RetryPolicy struct {
+	Attempts  int           // Total attempts including the first; values below 1 mean 1
+	BaseDelay time.Duration // Delay before the first retry, doubled for each subsequent one
+	MaxDelay  time.Duration // Upper bound for any single delay, including Retry-After
+}
+
+// DefaultRetry is suitable for idempotent GETs against public APIs
+var DefaultRetry = RetryPolicy{
+	Attempts:  3,
+	BaseDelay: 500 * time.Millisecond,
+	MaxDelay:  10 * time.Second,
+}
+
+// Do sends req with client, retrying network errors, 429s, and 5xx responses
+// with exponential backoff. Other statuses (including 404) are returned as-is
+// for the caller to handle. req must not have a body.
+func Do(client *http.Client, req *http.Request, policy RetryPolicy) (*http.Response, error) {
+	attempts := policy.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var (
+		lastErr    error
+		retryAfter time.Duration
+	)
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			delay := policy.BaseDelay << (attempt - 1)
+			if retryAfter > delay {
+				delay = retryAfter
+			}
+			if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+				delay = policy.MaxDelay
+			}
+			if err := sleep(req.Context(), delay); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := client.Do(req.Clone(req.Context()))
+		if err != nil {
+			// The caller gave up; retrying can't succeed
+			if req.Context().Err() != nil || runContext().Err() != nil {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+
+		if !retryable(resp.StatusCode) || attempt == attempts-1 {
+			return resp, nil
+		}
+
+		// Honor Retry-After (seconds form) when the server provides it
+		retryAfter = 0
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		resp.Body.Close()
+		lastErr = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil, fmt.Errorf("after %d attempts: %w", attempts, lastErr)
+}
+
+// retryable reports whether a status code indicates a transient failure
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-runContext().Done():
+		return fmt.Errorf("run aborted: %w", runContext().Err())
+	}
+}
@@ -0,0 +1,53 @@
+package httpx
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+)
+
+func TestDoRetries(t *testing.T) {
+	policy := RetryPolicy{Attempts: 3, BaseDelay: time.Millisecond}
+
+	tests := []struct {
+		name       string
+		statuses   []int
+		wantStatus int
+		wantErr    bool
+		wantCalls  int
+	}{
+		{name: "success first try", statuses: []int{200}, wantStatus: 200, wantCalls: 1},
+		{name: "recovers from 5xx and network error", statuses: []int{503, 0, 200}, wantStatus: 200, wantCalls: 3},
+		{name: "404 is not retried", statuses: []int{404}, wantStatus: 404, wantCalls: 1},
+		{name: "gives up returning last response", statuses: []int{500}, wantStatus: 500, wantCalls: 3},
+		{name: "gives up on persistent network errors", statuses: []int{0}, wantErr: true, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &httpxtest.Script{Statuses: tt.statuses}
+			defer SetBaseTransport(transport)()
+
+			req, _ := http.NewRequest("GET", "https://formulae.brew.sh/api/formula/git.json", nil)
+			resp, err := Do(NewClient(0), req, policy)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+				}
+			}
+			if got := transport.Calls(); got != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
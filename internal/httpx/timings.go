@@ -0,0 +1,66 @@
+package httpx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// hostStats accumulates response times for one host. Durations are measured
+// from admission to response headers, so time spent queued in the governor
+// doesn't count against the upstream.
+type hostStats struct {
+	count int
+	total time.Duration
+	min   time.Duration
+	max   time.Duration
+}
+
+var (
+	timingsMu sync.Mutex
+	timings   = make(map[string]*hostStats)
+)
+
+func recordTiming(host string, elapsed time.Duration) {
+	timingsMu.Lock()
+	defer timingsMu.Unlock()
+
+	stats, ok := timings[host]
+	if !ok {
+		stats = &hostStats{min: elapsed}
+		timings[host] = stats
+	}
+	stats.count++
+	stats.total += elapsed
+	if elapsed < stats.min {
+		stats.min = elapsed
+	}
+	if elapsed > stats.max {
+		stats.max = elapsed
+	}
+}
+
+// HostTimings returns min/avg/max response times per host recorded so far this run
+func HostTimings() map[string]models.HostTiming {
+	timingsMu.Lock()
+	defer timingsMu.Unlock()
+
+	result := make(map[string]models.HostTiming, len(timings))
+	for host, stats := range timings {
+		result[host] = models.HostTiming{
+			Count: stats.count,
+			Min:   stats.min.String(),
+			Avg:   (stats.total / time.Duration(stats.count)).String(),
+			Max:   stats.max.String(),
+		}
+	}
+	return result
+}
+
+// ResetTimings discards all recorded host timings
+func ResetTimings() {
+	timingsMu.Lock()
+	defer timingsMu.Unlock()
+	timings = make(map[string]*hostStats)
+}
@@ -0,0 +1,47 @@
+package httpx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+)
+
+func TestHostTimings(t *testing.T) {
+	defer SetBaseTransport(&httpxtest.Transport{Responses: map[string]httpxtest.Response{"": {Body: "ok", Delay: 5 * time.Millisecond}}})()
+	ResetTimings()
+	defer ResetTimings()
+
+	client := NewClient(0)
+	urls := []string{
+		"https://flathub.org/api/v2/a",
+		"https://flathub.org/api/v2/b",
+		"https://api.github.com/repos/x/y",
+	}
+	for _, url := range urls {
+		resp, err := client.Get(url)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	got := HostTimings()
+	if len(got) != 2 {
+		t.Fatalf("Expected timings for 2 hosts, got %d", len(got))
+	}
+	if got["flathub.org"].Count != 2 {
+		t.Errorf("Expected 2 requests to flathub.org, got %d", got["flathub.org"].Count)
+	}
+	if got["api.github.com"].Count != 1 {
+		t.Errorf("Expected 1 request to api.github.com, got %d", got["api.github.com"].Count)
+	}
+
+	min, err := time.ParseDuration(got["flathub.org"].Min)
+	if err != nil {
+		t.Fatalf("Failed to parse min duration: %v", err)
+	}
+	if min < 5*time.Millisecond {
+		t.Errorf("Expected min of at least 5ms, got %s", min)
+	}
+}
@@ -0,0 +1,57 @@
+package httpx
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"os"
+)
+
+// githubTokenTransport retries an anonymous GitHub request that hit the rate
+// limit once with GITHUB_TOKEN, so a call site that forgot to authenticate
+// doesn't fail while a token is available
+type githubTokenTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *githubTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !anonymousRateLimited(req, resp) {
+		return resp, err
+	}
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	retry.Header.Set("Authorization", "token "+token)
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	log.Printf("🔑 Anonymous request to %s%s was rate limited; retrying with GITHUB_TOKEN", req.URL.Host, req.URL.Path)
+	return t.next.RoundTrip(retry)
+}
+
+// anonymousRateLimited reports whether resp is GitHub's rate limit response
+// to a request sent without credentials
+func anonymousRateLimited(req *http.Request, resp *http.Response) bool {
+	if req.Header.Get("Authorization") != "" {
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		// GitHub also answers 403 for permission problems, which a token
+		// wouldn't necessarily fix; only the exhausted quota is escalated
+		return resp.Header.Get("X-RateLimit-Remaining") == "0"
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+)
+
+func TestGitHubTokenFallback(t *testing.T) {
+	const url = "https://api.github.com/repos/ublue-os/bluefin/releases"
+
+	tests := []struct {
+		name       string
+		token      string
+		header     string // Authorization sent by the caller
+		wantStatus int
+		wantAuths  []string
+	}{
+		{name: "anonymous 403 retried with token", token: "secret", wantStatus: http.StatusOK, wantAuths: []string{"", "token secret"}},
+		{name: "no token to escalate to", wantStatus: http.StatusForbidden, wantAuths: []string{""}},
+		{name: "authenticated requests are left alone", token: "secret", header: "token other", wantStatus: http.StatusOK, wantAuths: []string{"token other"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", tt.token)
+			// Rate limit anonymous requests the way GitHub does and serve authenticated ones
+			transport := &httpxtest.Transport{NotFound: &httpxtest.Response{Body: "releases"}}
+			defer SetBaseTransport(httpxtest.Func(func(req *http.Request) (*http.Response, error) {
+				resp, err := transport.RoundTrip(req)
+				if req.Header.Get("Authorization") == "" {
+					resp = httpxtest.Response{
+						Status: http.StatusForbidden,
+						Body:   `{"message":"API rate limit exceeded"}`,
+						Header: http.Header{"X-Ratelimit-Remaining": []string{"0"}},
+					}.For(req)
+				}
+				return resp, err
+			}))()
+
+			req, _ := http.NewRequest("GET", url, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp, err := NewClientWith(ClientOptions{GitHubTokenFallback: true}).Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d (%s)", tt.wantStatus, resp.StatusCode, body)
+			}
+			auths := transport.Headers("Authorization")
+			if len(auths) != len(tt.wantAuths) {
+				t.Fatalf("Expected %d requests, got %d: %q", len(tt.wantAuths), len(auths), auths)
+			}
+			for i, want := range tt.wantAuths {
+				if auths[i] != want {
+					t.Errorf("Request %d: expected Authorization %q, got %q", i, want, auths[i])
+				}
+			}
+			if req.Header.Get("Authorization") != tt.header {
+				t.Error("Expected the caller's request to be left unmodified")
+			}
+		})
+	}
+
+	t.Run("permission 403 is not escalated", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "secret")
+		transport := &httpxtest.Script{Statuses: []int{http.StatusForbidden}}
+		defer SetBaseTransport(transport)()
+
+		resp, err := NewClientWith(ClientOptions{GitHubTokenFallback: true}).Get(url)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if calls := transport.Calls(); calls != 1 {
+			t.Errorf("Expected 1 request, got %d", calls)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package icons
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// manifestName maps icon URLs to their stored filenames so reruns skip known icons
+const manifestName = "icons.json"
+
+// maxConcurrentDownloads bounds parallel icon downloads
+const maxConcurrentDownloads = 8
+
+var httpClient = httpx.NewClientWith(httpx.ClientOptions{
+	Timeout:   15 * time.Second,
+	UserAgent: httpx.DefaultUserAgent,
+})
+
+// Download stores each app's icon in dir under a content-hash filename and
+// rewrites App.Icon to a path relative to the output (e.g. "icons/<hash>.png").
+// Icons that fail to download keep their original URL.
+func Download(apps []models.App, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create icon dir: %w", err)
+	}
+
+	manifest := loadManifest(dir)
+	prefix := filepath.Base(dir)
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		downloaded int
+		failed     int
+	)
+	semaphore := make(chan struct{}, maxConcurrentDownloads)
+
+	for i := range apps {
+		app := &apps[i]
+		if !strings.HasPrefix(app.Icon, "http://") && !strings.HasPrefix(app.Icon, "https://") {
+			continue
+		}
+
+		mu.Lock()
+		name, known := manifest[app.Icon]
+		mu.Unlock()
+		if known {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				app.Icon = path.Join(prefix, name)
+				continue
+			}
+		}
+
+		wg.Add(1)
+		go func(app *models.App) {
+			defer wg.Done()
+			semaphore <- struct{}{}        // Acquire semaphore
+			defer func() { <-semaphore }() // Release semaphore
+
+			name, err := fetchIcon(app.Icon, dir)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("⚠️  Failed to download icon for %s: %v", app.ID, err)
+				failed++
+				return
+			}
+			manifest[app.Icon] = name
+			app.Icon = path.Join(prefix, name)
+			downloaded++
+		}(app)
+	}
+
+	wg.Wait()
+	log.Printf("🖼️  Icons: %d downloaded, %d failed", downloaded, failed)
+
+	return saveManifest(dir, manifest)
+}
+
+// fetchIcon downloads url into dir and returns the content-hash filename
+func fetchIcon(url, dir string) (string, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read body: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:16]) + iconExtension(url, resp.Header.Get("Content-Type"))
+
+	target := filepath.Join(dir, name)
+	if _, err := os.Stat(target); err == nil {
+		return name, nil // Same content already stored under another URL
+	}
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		return "", fmt.Errorf("write icon: %w", err)
+	}
+
+	return name, nil
+}
+
+// iconExtension picks a file extension from the URL, falling back to the content type
+func iconExtension(url, contentType string) string {
+	ext := strings.ToLower(path.Ext(strings.SplitN(url, "?", 2)[0]))
+	switch ext {
+	case ".png", ".svg", ".jpg", ".jpeg", ".webp", ".ico":
+		return ext
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "image/svg"):
+		return ".svg"
+	case strings.HasPrefix(contentType, "image/jpeg"):
+		return ".jpg"
+	case strings.HasPrefix(contentType, "image/webp"):
+		return ".webp"
+	}
+	return ".png"
+}
+
+func loadManifest(dir string) map[string]string {
+	manifest := make(map[string]string)
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err != nil {
+		return manifest
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		log.Printf("⚠️  Ignoring unreadable icon manifest: %v", err)
+		return make(map[string]string)
+	}
+	return manifest
+}
+
+func saveManifest(dir string, manifest map[string]string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestName), data, 0644); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package icons
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestDownload(t *testing.T) {
+	// A fixed PNG body, and 404 for the missing icon
+	png := http.Header{"Content-Type": []string{"image/png"}}
+	transport := &httpxtest.Transport{Responses: map[string]httpxtest.Response{
+		"":             {Body: "fake-png-bytes", Header: png},
+		"/missing.png": {Status: http.StatusNotFound, Header: png},
+	}}
+	defer httpx.SetBaseTransport(transport)()
+
+	dir := filepath.Join(t.TempDir(), "icons")
+	apps := []models.App{
+		{ID: "a", Icon: "https://dl.flathub.org/media/a/icon.png"},
+		{ID: "b", Icon: "https://dl.flathub.org/media/missing.png"},
+		{ID: "c", Icon: "icons/already-local.png"},
+	}
+
+	if err := Download(apps, dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(apps[0].Icon, "icons/") || !strings.HasSuffix(apps[0].Icon, ".png") {
+		t.Errorf("Expected rewritten relative icon path, got %q", apps[0].Icon)
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.Base(apps[0].Icon))); err != nil {
+		t.Errorf("Expected icon file to exist: %v", err)
+	}
+	if apps[1].Icon != "https://dl.flathub.org/media/missing.png" {
+		t.Errorf("Expected failed icon to keep URL, got %q", apps[1].Icon)
+	}
+	if apps[2].Icon != "icons/already-local.png" {
+		t.Errorf("Expected local icon untouched, got %q", apps[2].Icon)
+	}
+
+	// A second run reuses the stored file without downloading again
+	before := len(transport.URLs())
+	rerun := []models.App{{ID: "a", Icon: "https://dl.flathub.org/media/a/icon.png"}}
+	if err := Download(rerun, dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rerun[0].Icon != apps[0].Icon {
+		t.Errorf("Expected %q, got %q", apps[0].Icon, rerun[0].Icon)
+	}
+	if len(transport.URLs()) != before {
+		t.Errorf("Expected no new requests, got %d", len(transport.URLs())-before)
+	}
+}
@@ -0,0 +1,48 @@
+// Package lastcommit records when each source repository was last committed
+// to, fetching each repo's date at most once per run
+package lastcommit
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Cache shares each repo's last commit date between the apps built from it.
+// It is safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+type entry struct {
+	once sync.Once
+	date string
+}
+
+// NewCache returns an empty Cache
+func NewCache() *Cache {
+	return &Cache{entries: make(map[string]*entry)}
+}
+
+// Date returns the RFC3339 date fetch returns for repo, calling fetch only
+// the first time repo is asked for, or "" if that fetch failed
+func (c *Cache) Date(repo string, fetch func() (time.Time, error)) string {
+	c.mu.Lock()
+	e, ok := c.entries[repo]
+	if !ok {
+		e = &entry{}
+		c.entries[repo] = e
+	}
+	c.mu.Unlock()
+
+	e.once.Do(func() {
+		date, err := fetch()
+		if err != nil {
+			log.Printf("⚠️  Failed to fetch last commit for %s: %v", repo, err)
+			return
+		}
+		e.date = date.UTC().Format(time.RFC3339)
+	})
+	return e.date
+}
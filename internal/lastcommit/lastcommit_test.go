@@ -0,0 +1,38 @@
+package lastcommit
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheFetchesOncePerRepo(t *testing.T) {
+	cache := NewCache()
+	var calls atomic.Int32
+	fetch := func() (time.Time, error) {
+		calls.Add(1)
+		return time.Date(2026, 2, 3, 10, 0, 0, 0, time.FixedZone("CET", 60*60)), nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := cache.Date("example/app", fetch); got != "2026-02-03T09:00:00Z" {
+				t.Errorf("Expected the date in UTC, got %q", got)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected one fetch for the shared repo, got %d", got)
+	}
+
+	failing := func() (time.Time, error) { return time.Time{}, errors.New("boom") }
+	if got := cache.Date("example/broken", failing); got != "" {
+		t.Errorf("Expected no date when the fetch fails, got %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// imgSrcRe matches the src attribute of an <img> tag in rendered release notes
+var imgSrcRe = regexp.MustCompile(`(?i)<img\b[^>]*?\bsrc\s*=\s*["']([^"']+)["']`)
+
+// FirstImage returns the first http(s) image in rendered release notes as an
+// absolute URL, or "" when there is none. Repo-relative paths point at the
+// raw file in the source repository; anything else is resolved against the
+// release page.
+func FirstImage(s, releaseURL string, repo *models.SourceRepo) string {
+	for _, match := range imgSrcRe.FindAllStringSubmatch(s, -1) {
+		if src := absoluteImageURL(html.UnescapeString(match[1]), releaseURL, repo); src != "" {
+			return src
+		}
+	}
+	return ""
+}
+
+// absoluteImageURL resolves an image src from release notes, returning ""
+// for anything that doesn't resolve to an http or https URL, such as
+// javascript: and data: URIs
+func absoluteImageURL(src, releaseURL string, repo *models.SourceRepo) string {
+	ref, err := url.Parse(strings.TrimSpace(src))
+	if err != nil {
+		return ""
+	}
+	if ref.IsAbs() {
+		return httpURL(ref)
+	}
+	if strings.HasPrefix(ref.String(), "//") {
+		ref.Scheme = "https"
+		return httpURL(ref)
+	}
+
+	if !strings.HasPrefix(ref.String(), "/") && repo != nil && repo.URL != "" {
+		path := strings.TrimPrefix(ref.String(), "./")
+		switch repo.Type {
+		case "github":
+			return strings.TrimSuffix(repo.URL, "/") + "/raw/HEAD/" + path
+		case "gitlab":
+			return strings.TrimSuffix(repo.URL, "/") + "/-/raw/HEAD/" + path
+		}
+	}
+
+	base := releaseURL
+	if base == "" && repo != nil {
+		base = repo.URL
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil || !baseURL.IsAbs() {
+		return ""
+	}
+	return httpURL(baseURL.ResolveReference(ref))
+}
+
+// httpURL returns u as a string if it is an http or https URL with a host
+func httpURL(u *url.URL) string {
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		if u.Host != "" {
+			return u.String()
+		}
+	}
+	return ""
+}
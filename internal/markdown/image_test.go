@@ -0,0 +1,56 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestFirstImage(t *testing.T) {
+	repo := &models.SourceRepo{Type: "github", URL: "https://github.com/example/app", Owner: "example", Repo: "app"}
+
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{
+			name:        "first of several images",
+			description: `<p>New look</p><img alt="hero" src="https://example.com/hero.png"><img src="https://example.com/second.png">`,
+			want:        "https://example.com/hero.png",
+		},
+		{
+			name:        "repo-relative path",
+			description: `<p><img src="./docs/screenshot.png?raw=1&amp;v=2" /></p>`,
+			want:        "https://github.com/example/app/raw/HEAD/docs/screenshot.png?raw=1&v=2",
+		},
+		{
+			name:        "host-relative path",
+			description: `<img src='/example/app/assets/1.png'>`,
+			want:        "https://github.com/example/app/assets/1.png",
+		},
+		{
+			name:        "script and data URIs are skipped",
+			description: `<img src="javascript:alert(1)"><img src="data:image/png;base64,AAAA"><img src="https://example.com/safe.png">`,
+			want:        "https://example.com/safe.png",
+		},
+		{
+			name:        "protocol-relative URL",
+			description: `<img src="//cdn.example.com/shot.png">`,
+			want:        "https://cdn.example.com/shot.png",
+		},
+		{
+			name:        "no image",
+			description: `<p>Bug fixes</p>`,
+			want:        "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FirstImage(tt.description, "https://github.com/example/app/releases/tag/1.0", repo); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,30 +1,104 @@
 package markdown
 
 import (
+	"io"
+	"strings"
+
 	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
 	"github.com/gomarkdown/markdown/html"
 	"github.com/gomarkdown/markdown/parser"
 )
 
+// Options selects the GitHub Flavored Markdown extensions used by ToHTMLWith.
+// Release notes use them inconsistently, so each can be toggled separately.
+type Options struct {
+	Tables        bool // Pipe tables (the OS changelogs are built from these)
+	Strikethrough bool // ~~deleted~~ text
+	Autolink      bool // Bare URLs become links
+	TaskLists     bool // "- [ ]" and "- [x]" items render as checkboxes
+}
+
+// DefaultOptions enables every supported extension; ToHTML uses it
+var DefaultOptions = Options{
+	Tables:        true,
+	Strikethrough: true,
+	Autolink:      true,
+	TaskLists:     true,
+}
+
 // ToHTML converts markdown text to HTML
 // Uses GitHub Flavored Markdown extensions for compatibility
 func ToHTML(md string) string {
+	return ToHTMLWith(md, DefaultOptions)
+}
+
+// ToHTMLWith converts markdown text to HTML with the extensions in opts
+func ToHTMLWith(md string, opts Options) string {
 	// Handle empty input
 	if md == "" {
 		return ""
 	}
 
-	// Create markdown parser with GitHub Flavored Markdown extensions
-	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock
+	// Create markdown parser; the optional GFM extensions come from opts
+	extensions := parser.CommonExtensions&^(parser.Tables|parser.Strikethrough|parser.Autolink) |
+		parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock
+	if opts.Tables {
+		extensions |= parser.Tables
+	}
+	if opts.Strikethrough {
+		extensions |= parser.Strikethrough
+	}
+	if opts.Autolink {
+		extensions |= parser.Autolink
+	}
 	p := parser.NewWithExtensions(extensions)
 	doc := p.Parse([]byte(md))
 
 	// Create HTML renderer with safe options
 	htmlFlags := html.CommonFlags | html.HrefTargetBlank
-	opts := html.RendererOptions{Flags: htmlFlags}
-	renderer := html.NewRenderer(opts)
+	rendererOpts := html.RendererOptions{Flags: htmlFlags}
+	if opts.TaskLists {
+		rendererOpts.RenderNodeHook = renderTaskItem
+	}
+	renderer := html.NewRenderer(rendererOpts)
 
 	// Render markdown to HTML
 	htmlBytes := markdown.Render(doc, renderer)
 	return string(htmlBytes)
 }
+
+// renderTaskItem replaces a list item's leading "[ ] " or "[x] " with a
+// disabled checkbox. gomarkdown has no task list extension, so the marker
+// arrives as plain text at the start of the item's first paragraph.
+func renderTaskItem(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+	text, ok := node.(*ast.Text)
+	if !ok || !entering || !isFirstInListItem(text) {
+		return ast.GoToNext, false
+	}
+
+	literal := string(text.Literal)
+	var checkbox string
+	switch {
+	case strings.HasPrefix(literal, "[ ] "):
+		checkbox = `<input type="checkbox" disabled> `
+	case strings.HasPrefix(literal, "[x] "), strings.HasPrefix(literal, "[X] "):
+		checkbox = `<input type="checkbox" checked disabled> `
+	default:
+		return ast.GoToNext, false
+	}
+
+	io.WriteString(w, checkbox)
+	html.EscapeHTML(w, []byte(literal[len("[ ] "):]))
+	return ast.GoToNext, true
+}
+
+// isFirstInListItem reports whether text opens the first paragraph of a list item
+func isFirstInListItem(text *ast.Text) bool {
+	para, ok := text.Parent.(*ast.Paragraph)
+	if !ok || ast.GetFirstChild(para) != text {
+		return false
+	}
+	item, ok := para.Parent.(*ast.ListItem)
+	return ok && ast.GetFirstChild(item) == para
+}
@@ -0,0 +1,77 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToHTMLExtensions(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    Options
+		want    []string
+		notWant []string
+	}{
+		{
+			name:  "GFM table",
+			input: "| Package | Version |\n|---|---|\n| Kernel | 6.17.7 |\n",
+			opts:  DefaultOptions,
+			want:  []string{"<table>", "<th>Package</th>", "<td>Kernel</td>", "<td>6.17.7</td>"},
+		},
+		{
+			name:  "task list",
+			input: "- [ ] Ship the ISO\n- [x] Tag the **release**\n",
+			opts:  DefaultOptions,
+			want: []string{
+				`<li><input type="checkbox" disabled> Ship the ISO</li>`,
+				`<li><input type="checkbox" checked disabled> Tag the <strong>release</strong></li>`,
+			},
+		},
+		{
+			name:  "autolink",
+			input: "Details at https://example.com/notes",
+			opts:  DefaultOptions,
+			want:  []string{`<a href="https://example.com/notes" target="_blank">https://example.com/notes</a>`},
+		},
+		{
+			name:  "strikethrough",
+			input: "~~removed~~ feature",
+			opts:  DefaultOptions,
+			want:  []string{"<del>removed</del>"},
+		},
+		{
+			name:    "extensions disabled",
+			input:   "- [x] done at https://example.com ~~old~~\n",
+			opts:    Options{Tables: true},
+			want:    []string{"<li>[x] done at https://example.com ~~old~~</li>"},
+			notWant: []string{"<input", "<a ", "<del>"},
+		},
+		{
+			name:    "brackets mid-item are not a task",
+			input:   "- Fixed [x] in the parser\n",
+			opts:    DefaultOptions,
+			notWant: []string{"<input"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToHTMLWith(tt.input, tt.opts)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected output to contain %q, got %q", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Expected output without %q, got %q", notWant, got)
+				}
+			}
+		})
+	}
+
+	if ToHTML("| a |\n|---|\n| 1 |\n") != ToHTMLWith("| a |\n|---|\n| 1 |\n", DefaultOptions) {
+		t.Error("Expected ToHTML to use DefaultOptions")
+	}
+}
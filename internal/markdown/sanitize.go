@@ -0,0 +1,40 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// dangerousBlockRe matches elements whose content must be dropped entirely
+	dangerousBlockRe = regexp.MustCompile(`(?is)<script\b.*?</script\s*>|<style\b.*?</style\s*>|<!--.*?-->`)
+
+	// tagRe matches any start or end tag, capturing the slash and the tag name
+	tagRe = regexp.MustCompile(`(?i)<(/?)([a-z][a-z0-9]*)\b[^>]*>`)
+)
+
+// allowedTags is the appstream description markup plus inline formatting
+var allowedTags = map[string]bool{
+	"p": true, "ul": true, "ol": true, "li": true,
+	"em": true, "strong": true, "b": true, "i": true, "code": true, "br": true,
+}
+
+// SanitizeHTML keeps the paragraph, list, and inline-emphasis markup used by
+// appstream descriptions and strips everything else. Attributes are always
+// dropped, so event handlers and javascript: URLs can't survive; disallowed
+// tags are removed but their text is kept.
+func SanitizeHTML(s string) string {
+	s = dangerousBlockRe.ReplaceAllString(s, "")
+	s = tagRe.ReplaceAllStringFunc(s, func(tag string) string {
+		m := tagRe.FindStringSubmatch(tag)
+		name := strings.ToLower(m[2])
+		if !allowedTags[name] {
+			return ""
+		}
+		if name == "br" {
+			return "<br>"
+		}
+		return "<" + m[1] + name + ">"
+	})
+	return strings.TrimSpace(s)
+}
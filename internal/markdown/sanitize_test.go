@@ -0,0 +1,40 @@
+package markdown
+
+import "testing"
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "appstream paragraphs and lists",
+			input: "<p>Loupe is an image viewer.</p>\n<ul><li>Fast <em>zooming</em></li><li>Touchpad gestures</li></ul>",
+			want:  "<p>Loupe is an image viewer.</p>\n<ul><li>Fast <em>zooming</em></li><li>Touchpad gestures</li></ul>",
+		},
+		{
+			name:  "attributes stripped",
+			input: `<p onclick="alert(1)" class="x">Hello <code style="color:red">world</code></p>`,
+			want:  "<p>Hello <code>world</code></p>",
+		},
+		{
+			name:  "dangerous elements removed",
+			input: `<p>Safe</p><script>alert("x")</script><style>p{}</style><iframe src="https://evil"></iframe><!-- note -->`,
+			want:  "<p>Safe</p>",
+		},
+		{
+			name:  "unknown tags unwrapped",
+			input: `<p>See <a href="javascript:alert(1)">the docs</a></p>`,
+			want:  "<p>See the docs</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeHTML(tt.input); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// blockEndRe matches tags that end a line of text
+	blockEndRe = regexp.MustCompile(`(?i)</(p|li|h[1-6]|div|pre|blockquote|tr|table|ul|ol)\s*>|<br\s*/?>|<hr\s*/?>`)
+
+	// headingRe matches a whole heading element
+	headingRe = regexp.MustCompile(`(?is)<h[1-6]\b.*?</h[1-6]\s*>`)
+
+	// badgeLineRe matches lines of unrendered markdown images, optionally
+	// wrapped in links, such as CI and version badges
+	badgeLineRe = regexp.MustCompile(`^(\[?!\[[^\]]*\]\([^)]*\)(\]\([^)]*\))?\s*)+$`)
+)
+
+// ToText converts rendered HTML to plain text with one line per block
+// element. Tags are dropped, entities decoded, and blank lines removed.
+func ToText(s string) string {
+	s = dangerousBlockRe.ReplaceAllString(s, "")
+	s = blockEndRe.ReplaceAllString(s, "\n")
+	s = tagRe.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Preview returns the first n meaningful lines of rendered release notes as
+// plain text. Headings and image-only lines (badges, screenshots) are
+// skipped, so the preview starts at the first real sentence.
+func Preview(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	var lines []string
+	for _, line := range strings.Split(ToText(headingRe.ReplaceAllString(s, "")), "\n") {
+		if strings.HasPrefix(line, "#") || badgeLineRe.MatchString(line) {
+			continue
+		}
+		lines = append(lines, line)
+		if len(lines) == n {
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,34 @@
+package markdown
+
+import "testing"
+
+func TestPreview(t *testing.T) {
+	body := "# Firefox 141.0\n\n" +
+		"[![Build](https://img.shields.io/badge/build-passing-green)](https://ci.example.com) ![Downloads](https://img.shields.io/badge/dl-1M-blue)\n\n" +
+		"## What's new\n\n" +
+		"This release adds **vertical tabs** &amp; a new sidebar.\n\n" +
+		"- Faster startup\n" +
+		"- Fixed [a crash](https://example.com/1) on resume\n"
+	rendered := ToHTML(body)
+
+	tests := []struct {
+		name  string
+		input string
+		lines int
+		want  string
+	}{
+		{name: "first real sentence", input: rendered, lines: 1, want: "This release adds vertical tabs & a new sidebar."},
+		{name: "several lines", input: rendered, lines: 3, want: "This release adds vertical tabs & a new sidebar.\nFaster startup\nFixed a crash on resume"},
+		{name: "unrendered markdown badges", input: "<p>[![CI](https://ci/badge.svg)](https://ci)</p>\n<p>Plain notes</p>", lines: 1, want: "Plain notes"},
+		{name: "disabled", input: rendered, lines: 0, want: ""},
+		{name: "only headings", input: "<h2>Changes</h2>", lines: 2, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Preview(tt.input, tt.lines); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
 	"time"
 )
 
@@ -15,12 +18,31 @@ type OutputData struct {
 
 // Metadata contains build metadata and statistics
 type Metadata struct {
-	SchemaVersion string      `json:"schemaVersion"`
-	GeneratedAt   string      `json:"generatedAt"`
-	GeneratedBy   string      `json:"generatedBy"`
-	BuildDuration string      `json:"buildDuration"`
-	Stats         Stats       `json:"stats"`
-	Performance   Performance `json:"performance"`
+	SchemaVersion string                `json:"schemaVersion"`
+	GeneratedAt   string                `json:"generatedAt"`
+	GeneratedBy   string                `json:"generatedBy"`
+	BuildDuration string                `json:"buildDuration"`
+	Partial       bool                  `json:"partial,omitempty"` // Fetching was cut short by -max-runtime
+	Stats         Stats                 `json:"stats"`
+	Performance   Performance           `json:"performance"`
+	HostTimings   map[string]HostTiming `json:"hostTimings,omitempty"` // Only populated with -diagnostics
+	CacheStats    map[string]CacheStat  `json:"cacheStats,omitempty"`  // Only populated with -diagnostics
+}
+
+// CacheStat counts response cache outcomes for one source
+type CacheStat struct {
+	Hits      int `json:"hits"`      // Served from a fresh entry
+	Misses    int `json:"misses"`    // No entry; fetched
+	Refreshes int `json:"refreshes"` // Entry older than the source's TTL; fetched again
+	Bypassed  int `json:"bypassed"`  // Skipped by -refresh-cache
+}
+
+// HostTiming summarizes HTTP response times for a single upstream host
+type HostTiming struct {
+	Count int    `json:"count"`
+	Min   string `json:"min"`
+	Avg   string `json:"avg"`
+	Max   string `json:"max"`
 }
 
 // Stats contains aggregate statistics
@@ -30,6 +52,7 @@ type Stats struct {
 	AppsWithGitLabRepo int `json:"appsWithGitLabRepo"`
 	AppsWithChangelogs int `json:"appsWithChangelogs"`
 	TotalReleases      int `json:"totalReleases"`
+	AppsEOL            int `json:"appsEol"` // Curated apps no longer listed on Flathub
 }
 
 // Performance contains timing breakdown
@@ -51,23 +74,63 @@ type App struct {
 	DeveloperName     string        `json:"developerName,omitempty"`
 	Icon              string        `json:"icon,omitempty"`
 	ProjectLicense    string        `json:"projectLicense,omitempty"`
+	ProjectGroup      string        `json:"projectGroup,omitempty"` // Appstream project group, e.g. "GNOME" or "KDE"; separate from categories
 	Categories        []string      `json:"categories,omitempty"`
 	UpdatedAt         string        `json:"updatedAt,omitempty"`
+	AddedAt           string        `json:"addedAt,omitempty"` // When the app was first published on Flathub
+	NewApp            bool          `json:"newApp,omitempty"`  // AddedAt falls within the configured "new app" window
 	Version           string        `json:"currentReleaseVersion,omitempty"`
 	ReleaseDate       string        `json:"currentReleaseDate,omitempty"`
+	LastCommitDate    string        `json:"lastCommitDate,omitempty"`    // Newest commit on the source repo's default branch (only with -last-commit)
+	MaintenanceStatus string        `json:"maintenanceStatus,omitempty"` // "active" or "inactive", from release and commit recency
 	FlathubURL        string        `json:"flathubUrl"`
+	Homepage          string        `json:"homepage,omitempty"` // Project website from appstream, separate from the source repo
+	Runtime           string        `json:"runtime,omitempty"`  // Flatpak runtime and its branch, e.g. "org.gnome.Platform//48"
+	Branch            string        `json:"branch,omitempty"`   // Flatpak app branch, e.g. "stable"
 	SourceRepo        *SourceRepo   `json:"sourceRepo,omitempty"`
 	Releases          []Release     `json:"releases,omitempty"`
 	FetchedAt         time.Time     `json:"fetchedAt"`
 	InstallsLastMonth int           `json:"installsLastMonth,omitempty"`
 	FavoritesCount    int           `json:"favoritesCount,omitempty"`
+	TrendingRank      int           `json:"trendingRank,omitempty"` // Position in a ranked -feed (popular or trending), from 1
 	IsVerified        bool          `json:"isVerified"`
 	VerificationInfo  *Verification `json:"verificationInfo,omitempty"`
-	AppSet            string        `json:"appSet,omitempty"` // "core" or "dx"
-	PackageType       string        `json:"packageType"`      // "flatpak", "homebrew", or "os"
+	AppSet            string        `json:"appSet,omitempty"`          // "core" or "dx"
+	BluefinCategory   string        `json:"bluefinCategory,omitempty"` // Dashboard role, e.g. "developer", "gaming", or "other"
+	PackageType       string        `json:"packageType"`               // "flatpak", "homebrew", or "os"
 	HomebrewInfo      *HomebrewInfo `json:"homebrewInfo,omitempty"`
-	OSInfo            *OSInfo       `json:"osInfo,omitempty"`       // OS release-specific info
-	Experimental      bool          `json:"experimental,omitempty"` // Marks packages from experimental-tap as unstable
+	OSInfo            *OSInfo       `json:"osInfo,omitempty"`            // OS release-specific info
+	Experimental      bool          `json:"experimental,omitempty"`      // Marks packages from experimental-tap as unstable
+	EOL               bool          `json:"eol,omitempty"`               // Curated app is no longer listed on Flathub
+	Deprecated        bool          `json:"deprecated,omitempty"`        // Homebrew formula is deprecated (only with -keep-deprecated)
+	Disabled          bool          `json:"disabled,omitempty"`          // Homebrew formula is disabled (only with -keep-deprecated)
+	DeprecationReason string        `json:"deprecationReason,omitempty"` // Homebrew's reason, e.g. "repo_archived"
+	TimedOut          bool          `json:"timedOut,omitempty"`          // Enrichment ran out of its share of the Flathub time budget
+	Debug             *DebugInfo    `json:"debug,omitempty"`             // Enrichment trace (only populated in explain mode)
+}
+
+// DebugInfo records why an app did or didn't get releases during a run
+type DebugInfo struct {
+	SourceRepo string      `json:"sourceRepo,omitempty"` // Detected source repository URL
+	Steps      []DebugStep `json:"steps"`
+}
+
+// DebugStep is a single stage's outcome for an app
+type DebugStep struct {
+	Stage         string `json:"stage"`            // "source", "github", "gitlab", "mozilla", "dedupe"
+	Matched       bool   `json:"matched"`          // Whether the stage applied to this app
+	Status        int    `json:"status,omitempty"` // HTTP status of the stage's fetch, if any
+	ReleasesAdded int    `json:"releasesAdded"`
+	Note          string `json:"note,omitempty"`
+}
+
+// Record appends a step to the trace. Safe to call on a nil DebugInfo, which
+// is how enrichers stay silent outside explain mode.
+func (d *DebugInfo) Record(step DebugStep) {
+	if d == nil {
+		return
+	}
+	d.Steps = append(d.Steps, step)
 }
 
 // HomebrewInfo contains Homebrew-specific package information
@@ -84,6 +147,7 @@ type HomebrewInfo struct {
 // OSInfo contains Bluefin OS release-specific information
 type OSInfo struct {
 	Stream        string            `json:"stream"`                  // "stable", "gts", or "lts"
+	UnknownStream bool              `json:"unknownStream,omitempty"` // Tag didn't name a known stream; Stream is taken from it as-is
 	FedoraVersion string            `json:"fedoraVersion,omitempty"` // e.g., "43" or "42" (for stable/gts)
 	CentOSVersion string            `json:"centosVersion,omitempty"` // e.g., "10" (for LTS builds)
 	BuildNumber   string            `json:"buildNumber"`             // e.g., "20260203"
@@ -93,6 +157,17 @@ type OSInfo struct {
 	GnomeVersion  string            `json:"gnomeVersion,omitempty"`  // e.g., "49.3-2"
 	MesaVersion   string            `json:"mesaVersion,omitempty"`   // e.g., "25.3.4-1"
 	MajorPackages map[string]string `json:"majorPackages,omitempty"` // Other major packages (Podman, Nvidia, etc.)
+	Assets        []ReleaseAsset    `json:"assets,omitempty"`        // Files attached to the release, with sizes
+	ImageSize     string            `json:"imageSize,omitempty"`     // Total size of Assets, human-readable; empty when none are attached
+	ImageBytes    int64             `json:"imageBytes,omitempty"`    // Total size of Assets in bytes
+}
+
+// ReleaseAsset is a downloadable file attached to a release
+type ReleaseAsset struct {
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+	Size          int64  `json:"size"`          // Bytes
+	SizeFormatted string `json:"sizeFormatted"` // Human-readable, e.g. "2.1 GiB"
 }
 
 // Verification contains app verification details from Flathub
@@ -109,16 +184,42 @@ type SourceRepo struct {
 	URL   string `json:"url"`
 	Owner string `json:"owner,omitempty"`
 	Repo  string `json:"repo,omitempty"`
+	Host  string `json:"host,omitempty"` // GitLab instance, e.g. "gitlab.gnome.org" or "invent.kde.org"
 }
 
 // Release represents a single release/changelog entry (from GitHub, GitLab, or Flathub)
 type Release struct {
-	Version     string    `json:"version"`
-	Date        time.Time `json:"date"`
-	Title       string    `json:"title"`
-	Description string    `json:"description,omitempty"`
-	URL         string    `json:"url,omitempty"`
-	Type        string    `json:"type"` // "github-release", "gitlab-release", "appstream"
+	Version           string          `json:"version"`
+	Date              time.Time       `json:"date"`
+	Title             string          `json:"title"`
+	Description       string          `json:"description,omitempty"`       // Rendered HTML
+	DescriptionSource string          `json:"descriptionSource,omitempty"` // Original markdown/text before rendering
+	Preview           string          `json:"preview,omitempty"`           // First lines of the notes as plain text, for cards
+	URL               string          `json:"url,omitempty"`
+	Type              string          `json:"type"`                      // "github-release", "gitlab-release", "appstream"
+	Reactions         int             `json:"reactions,omitempty"`       // Total GitHub reactions (only with -reactions)
+	CompareURL        string          `json:"compareUrl,omitempty"`      // GitHub compare link from the previous release's commit (OS releases)
+	Breaking          bool            `json:"breaking,omitempty"`        // Likely breaking change (only with -detect-breaking)
+	PreviewImage      string          `json:"previewImage,omitempty"`    // First image in the release notes, as an absolute URL
+	Author            string          `json:"author,omitempty"`          // GitHub login of whoever published the release
+	AuthorAvatar      string          `json:"authorAvatar,omitempty"`    // Avatar URL for Author
+	Commits           []CommitSummary `json:"commits,omitempty"`         // Commits since the previous OS release (only with -os-commits)
+	CommitsOmitted    int             `json:"commitsOmitted,omitempty"`  // Commits in the range beyond those listed in Commits
+	Draft             bool            `json:"draft,omitempty"`           // Unpublished OS release (only with -include-drafts)
+	HasSignature      bool            `json:"hasSignature,omitempty"`    // A signature or sigstore/in-toto attestation is attached
+	SignatureURLs     []string        `json:"signatureUrls,omitempty"`   // Download URLs of the signature assets
+	HasChecksums      bool            `json:"hasChecksums,omitempty"`    // A checksum file is attached
+	ChecksumURLs      []string        `json:"checksumUrls,omitempty"`    // Download URLs of the checksum assets
+	PreviousVersion   string          `json:"previousVersion,omitempty"` // Version upgraded from, when the title says so ("from 1.2 to 1.3")
+	IsNew             bool            `json:"isNew,omitempty"`           // Not in the previous run's output (only with -mark-new)
+	Contributors      []string        `json:"contributors,omitempty"`    // Logins from the notes' "New Contributors" section
+}
+
+// CommitSummary is a single commit between two OS releases
+type CommitSummary struct {
+	SHA     string `json:"sha"`
+	Message string `json:"message"` // First line of the commit message
+	Author  string `json:"author,omitempty"`
 }
 
 // FlathubApp represents the raw structure from Flathub API collection endpoint
@@ -133,6 +234,7 @@ type FlathubApp struct {
 	MainCategories        StringOrArray `json:"main_categories"`
 	SubCategories         []string      `json:"sub_categories"`
 	UpdatedAt             int64         `json:"updated_at"`
+	AddedAt               int64         `json:"added_at"`
 	InstallsLastMonth     int           `json:"installs_last_month"`
 	FavoritesCount        int           `json:"favorites_count"`
 	VerificationVerified  bool          `json:"verification_verified"`
@@ -174,37 +276,190 @@ type FlathubCollectionResponse struct {
 
 // FlathubAppDetails represents detailed app information from Flathub API
 type FlathubAppDetails struct {
-	ID          string                `json:"id"`
-	Name        string                `json:"name"`
-	Summary     string                `json:"summary"`
-	Description string                `json:"description"`
-	Icon        string                `json:"icon"`
-	URLs        map[string]string     `json:"urls"`
-	Releases    []FlathubReleaseEntry `json:"releases"`
+	ID           string                `json:"id"`
+	Name         string                `json:"name"`
+	Summary      string                `json:"summary"`
+	Description  string                `json:"description"`
+	Icon         string                `json:"icon"`
+	URLs         map[string]string     `json:"urls"`
+	Releases     []FlathubReleaseEntry `json:"releases"`
+	Bundle       *FlathubBundle        `json:"bundle"`        // Nil when appstream has no bundle info
+	ProjectGroup string                `json:"project_group"` // e.g. "GNOME", "KDE"; empty for most apps
+}
+
+// FlathubBundle is the Flatpak bundle an app is built as
+type FlathubBundle struct {
+	Type    string `json:"type"`    // "flatpak"
+	Value   string `json:"value"`   // App ref, e.g. "app/org.gnome.Loupe/x86_64/stable"
+	Runtime string `json:"runtime"` // e.g. "org.gnome.Platform/x86_64/48"
+	SDK     string `json:"sdk"`
 }
 
 // FlathubReleaseEntry represents a release from Flathub appstream metadata
 type FlathubReleaseEntry struct {
-	Version     string `json:"version"`
-	Date        string `json:"date"`
-	Timestamp   string `json:"timestamp"`
-	Description string `json:"description"`
+	Version     string         `json:"version"`
+	Date        StringOrNumber `json:"date"`      // Date string or unix epoch (seconds or millis)
+	Timestamp   StringOrNumber `json:"timestamp"` // Unix epoch, sent as either a string or a number
+	Description string         `json:"description"`
 }
 
+// StringOrNumber handles JSON fields that can be either a string or a number,
+// keeping the value in its textual form
+type StringOrNumber string
+
+// UnmarshalJSON implements custom unmarshalling for string or number
+func (s *StringOrNumber) UnmarshalJSON(data []byte) error {
+	// Try to unmarshal as string first
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*s = StringOrNumber(str)
+		return nil
+	}
+
+	// Try to unmarshal as number (json.Number keeps large epochs exact)
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err == nil {
+		*s = StringOrNumber(num.String())
+		return nil
+	}
+
+	return fmt.Errorf("field must be string or number")
+}
+
+// JSONOptions controls how OutputData is encoded
+type JSONOptions struct {
+	// EscapeHTML escapes <, > and & as \u003c, \u003e and \u0026. Release
+	// descriptions contain HTML rendered from upstream, user-controlled
+	// markdown; consumers that inline the JSON into an HTML page (e.g. inside
+	// a <script> tag) should enable this so a "</script>" in a description
+	// can't break out of the embedding. Escaping doesn't sanitize the HTML
+	// itself: anything inserting descriptions into the DOM must still treat
+	// them as untrusted.
+	EscapeHTML bool
+
+	// MinifiedPath, when set, also writes a compact copy (no indentation) for
+	// production loading, while path keeps the reviewable pretty-printed form
+	MinifiedPath string
+
+	// Indent is the indentation of the pretty-printed file, e.g. "\t" or four
+	// spaces; empty means DefaultIndent
+	Indent string
+}
+
+// DefaultIndent is the pretty-printed output's indentation unless
+// JSONOptions.Indent says otherwise
+const DefaultIndent = "  "
+
 // WriteJSON writes OutputData to a JSON file (pretty-printed)
 func (o *OutputData) WriteJSON(path string) error {
-	file, err := os.Create(path)
+	return o.WriteJSONWithOptions(path, JSONOptions{})
+}
+
+// WriteJSONWithOptions writes OutputData to a JSON file (pretty-printed) using opts
+func (o *OutputData) WriteJSONWithOptions(path string, opts JSONOptions) error {
+	compact, err := o.encodeCompact(opts)
 	if err != nil {
-		return fmt.Errorf("create file: %w", err)
+		return err
 	}
-	defer file.Close()
+	return writeEncoded(compact, path, opts)
+}
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	encoder.SetEscapeHTML(false) // Keep URLs readable
+// WriteJSONIfChanged is WriteJSONWithOptions, except the files are left alone
+// when path already holds the same content apart from volatile run metadata
+// (timestamps, durations, diagnostics). Reports whether anything was written.
+func (o *OutputData) WriteJSONIfChanged(path string, opts JSONOptions) (bool, error) {
+	compact, err := o.encodeCompact(opts)
+	if err != nil {
+		return false, err
+	}
+
+	if existing, err := os.ReadFile(path); err == nil && sameContent(existing, compact) && minifiedExists(opts) {
+		return false, nil
+	}
+	return true, writeEncoded(compact, path, opts)
+}
+
+// minifiedExists reports whether the minified copy, if requested, is on disk
+func minifiedExists(opts JSONOptions) bool {
+	if opts.MinifiedPath == "" {
+		return true
+	}
+	_, err := os.Stat(opts.MinifiedPath)
+	return err == nil
+}
+
+// volatileMetadata are metadata fields that change on every run
+var volatileMetadata = []string{"generatedAt", "buildDuration", "performance", "hostTimings", "cacheStats"}
+
+// sameContent reports whether two encoded outputs match once volatile fields
+// are dropped and apps are put in ID order. Undecodable input never matches.
+func sameContent(a, b []byte) bool {
+	var docA, docB map[string]interface{}
+	if json.Unmarshal(a, &docA) != nil || json.Unmarshal(b, &docB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(stableContent(docA), stableContent(docB))
+}
+
+// stableContent strips the volatile parts of a decoded output in place
+func stableContent(doc map[string]interface{}) map[string]interface{} {
+	if metadata, ok := doc["metadata"].(map[string]interface{}); ok {
+		for _, field := range volatileMetadata {
+			delete(metadata, field)
+		}
+	}
+
+	apps, _ := doc["apps"].([]interface{})
+	for _, app := range apps {
+		if fields, ok := app.(map[string]interface{}); ok {
+			delete(fields, "fetchedAt") // Stamped with the run time
+		}
+	}
+	sort.SliceStable(apps, func(i, j int) bool {
+		return appID(apps[i]) < appID(apps[j])
+	})
+	return doc
+}
+
+func appID(app interface{}) string {
+	fields, _ := app.(map[string]interface{})
+	id, _ := fields["id"].(string)
+	return id
+}
+
+// encodeCompact encodes o on one line. The pretty form is reformatted from
+// these bytes rather than marshaling the whole tree a second time.
+func (o *OutputData) encodeCompact(opts JSONOptions) ([]byte, error) {
+	var compact bytes.Buffer
+	encoder := json.NewEncoder(&compact)
+	encoder.SetEscapeHTML(opts.EscapeHTML) // Unescaped by default to keep URLs readable
 
 	if err := encoder.Encode(o); err != nil {
-		return fmt.Errorf("encode JSON: %w", err)
+		return nil, fmt.Errorf("encode JSON: %w", err)
+	}
+	return compact.Bytes(), nil
+}
+
+// writeEncoded writes compact output to path pretty-printed, and as-is to
+// opts.MinifiedPath when set
+func writeEncoded(compact []byte, path string, opts JSONOptions) error {
+	if opts.MinifiedPath != "" {
+		if err := os.WriteFile(opts.MinifiedPath, compact, 0644); err != nil {
+			return fmt.Errorf("write minified file: %w", err)
+		}
+	}
+
+	indent := opts.Indent
+	if indent == "" {
+		indent = DefaultIndent
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, compact, "", indent); err != nil {
+		return fmt.Errorf("indent JSON: %w", err)
+	}
+
+	if err := os.WriteFile(path, pretty.Bytes(), 0644); err != nil {
+		return fmt.Errorf("create file: %w", err)
 	}
 
 	return nil
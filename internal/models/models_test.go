@@ -0,0 +1,233 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReleaseDescriptionSourceRoundTrip(t *testing.T) {
+	release := Release{
+		Version:           "v1.2.0",
+		Date:              time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC),
+		Title:             "v1.2.0",
+		Description:       "<h2>Changes</h2>\n<ul>\n<li>Fixed <strong>bug</strong></li>\n</ul>\n",
+		DescriptionSource: "## Changes\n\n- Fixed **bug**",
+		Type:              "github-release",
+	}
+
+	data, err := json.Marshal(release)
+	if err != nil {
+		t.Fatalf("Unexpected marshal error: %v", err)
+	}
+
+	var decoded Release
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unexpected unmarshal error: %v", err)
+	}
+
+	if decoded.DescriptionSource != release.DescriptionSource {
+		t.Errorf("Expected source %q, got %q", release.DescriptionSource, decoded.DescriptionSource)
+	}
+	if decoded.Description != release.Description {
+		t.Errorf("Expected description %q, got %q", release.Description, decoded.Description)
+	}
+
+	// Without a source the field must be omitted entirely
+	release.DescriptionSource = ""
+	data, err = json.Marshal(release)
+	if err != nil {
+		t.Fatalf("Unexpected marshal error: %v", err)
+	}
+	if strings.Contains(string(data), "descriptionSource") {
+		t.Errorf("Expected descriptionSource to be omitted, got %s", data)
+	}
+}
+
+func TestWriteJSONWithOptionsEscapeHTML(t *testing.T) {
+	output := &OutputData{
+		Apps: []App{{
+			ID:   "org.example.App",
+			Name: "Example",
+			Releases: []Release{{
+				Version:     "1.0",
+				Description: "<p>Fish & chips</script></p>",
+				URL:         "https://example.com/?a=1&b=2",
+			}},
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		escapeHTML bool
+		want       string
+		notWant    string
+	}{
+		{name: "unescaped by default", escapeHTML: false, want: "<p>Fish & chips</script></p>", notWant: `\u003c`},
+		{name: "escaped", escapeHTML: true, want: `\u003cp\u003eFish \u0026 chips\u003c/script\u003e\u003c/p\u003e`, notWant: "</script>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "apps.json")
+			if err := output.WriteJSONWithOptions(path, JSONOptions{EscapeHTML: tt.escapeHTML}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read output: %v", err)
+			}
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("Expected output to contain %q, got %s", tt.want, data)
+			}
+			if strings.Contains(string(data), tt.notWant) {
+				t.Errorf("Expected output not to contain %q", tt.notWant)
+			}
+
+			// Both modes must round-trip to the same values
+			var decoded OutputData
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Output is not valid JSON: %v", err)
+			}
+			if decoded.Apps[0].Releases[0].Description != output.Apps[0].Releases[0].Description {
+				t.Errorf("Expected description %q, got %q", output.Apps[0].Releases[0].Description, decoded.Apps[0].Releases[0].Description)
+			}
+		})
+	}
+}
+
+func TestWriteJSONWithOptionsMinified(t *testing.T) {
+	output := &OutputData{
+		Metadata: Metadata{SchemaVersion: "1.0.0"},
+		Apps: []App{{
+			ID:       "org.example.App",
+			Name:     "Example",
+			Releases: []Release{{Version: "1.0", Description: "<p>Notes</p>", URL: "https://example.com/?a=1&b=2"}},
+		}},
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "apps.json")
+	minPath := filepath.Join(dir, "apps.min.json")
+	if err := output.WriteJSONWithOptions(path, JSONOptions{MinifiedPath: minPath}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	pretty, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minified, err := os.ReadFile(minPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(pretty), "\n  \"apps\"") {
+		t.Error("Expected apps.json to be indented")
+	}
+	if strings.Count(string(minified), "\n") != 1 || len(minified) >= len(pretty) {
+		t.Errorf("Expected apps.min.json to be a single compact line, got %d bytes vs %d", len(minified), len(pretty))
+	}
+	if !strings.Contains(string(minified), "a=1&b=2") {
+		t.Error("Expected minified output to keep HTML unescaped like the pretty output")
+	}
+
+	var fromPretty, fromMinified OutputData
+	if err := json.Unmarshal(pretty, &fromPretty); err != nil {
+		t.Fatalf("apps.json is not valid JSON: %v", err)
+	}
+	if err := json.Unmarshal(minified, &fromMinified); err != nil {
+		t.Fatalf("apps.min.json is not valid JSON: %v", err)
+	}
+	a, _ := json.Marshal(fromPretty)
+	b, _ := json.Marshal(fromMinified)
+	if string(a) != string(b) {
+		t.Errorf("Expected both files to decode to equal structures\npretty:   %s\nminified: %s", a, b)
+	}
+}
+
+func TestWriteJSONWithOptionsIndent(t *testing.T) {
+	output := &OutputData{Apps: []App{{ID: "org.example.App", Name: "Example"}}}
+
+	tests := []struct {
+		name   string
+		indent string
+		want   string
+	}{
+		{name: "default", want: "\n  \"apps\": [\n    {\n      \"id\""},
+		{name: "tabs", indent: "\t", want: "\n\t\"apps\": [\n\t\t{\n\t\t\t\"id\""},
+		{name: "four spaces", indent: "    ", want: "\n    \"apps\": [\n        {\n            \"id\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "apps.json")
+			if err := output.WriteJSONWithOptions(path, JSONOptions{Indent: tt.indent}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("Expected indentation %q, got:\n%s", tt.want, data)
+			}
+		})
+	}
+}
+
+func TestWriteJSONIfChanged(t *testing.T) {
+	build := func(generatedAt string, fetchedAt time.Time, version string) *OutputData {
+		return &OutputData{
+			Metadata: Metadata{
+				SchemaVersion: "1.0.0",
+				GeneratedAt:   generatedAt,
+				BuildDuration: generatedAt,
+				Performance:   Performance{OutputDuration: generatedAt},
+			},
+			Apps: []App{{
+				ID:        "org.example.App",
+				FetchedAt: fetchedAt,
+				Releases:  []Release{{Version: version}},
+			}},
+		}
+	}
+	first := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		next        *OutputData
+		wantChanged bool
+	}{
+		{name: "only volatile fields differ", next: build("second run", first.Add(time.Hour), "1.0"), wantChanged: false},
+		{name: "content differs", next: build("second run", first.Add(time.Hour), "1.1"), wantChanged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "apps.json")
+			changed, err := build("first run", first, "1.0").WriteJSONIfChanged(path, JSONOptions{})
+			if err != nil || !changed {
+				t.Fatalf("Expected initial write, got changed=%v err=%v", changed, err)
+			}
+			before, _ := os.ReadFile(path)
+
+			changed, err = tt.next.WriteJSONIfChanged(path, JSONOptions{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("Expected changed=%v, got %v", tt.wantChanged, changed)
+			}
+
+			after, _ := os.ReadFile(path)
+			if written := string(before) != string(after); written != tt.wantChanged {
+				t.Errorf("Expected file rewritten=%v, got %v", tt.wantChanged, written)
+			}
+		})
+	}
+}
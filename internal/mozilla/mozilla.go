@@ -1,155 +1,187 @@
 package mozilla
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/castrojo/bluefin-releases/internal/dates"
+	"github.com/castrojo/bluefin-releases/internal/httpx"
 	"github.com/castrojo/bluefin-releases/internal/markdown"
 	"github.com/castrojo/bluefin-releases/internal/models"
 )
 
-// EnrichWithMozillaReleases fetches release notes for Firefox and Thunderbird
+// httpClient is shared by all Mozilla requests so they respect the global request limits
+var httpClient = httpx.NewClientWith(httpx.ClientOptions{UserAgent: httpx.DefaultUserAgent})
+
+// ProductDetailsBase is the Mozilla product-details API serving the versions JSON files
+const ProductDetailsBase = "https://product-details.mozilla.org/1.0"
+
+// product describes a Mozilla product whose release notes we track
+type product struct {
+	AppID       string              // Flatpak app ID to enrich
+	Name        string              // Display name used in titles and logs
+	VersionsURL string              // product-details versions JSON (may be shared between products)
+	VersionKey  string              // Key of the latest version in the versions JSON
+	NotesURL    string              // Release notes page, %s is replaced by the version
+	Extract     func(string) string // Converts the release notes page to HTML
+}
+
+// products lists the Mozilla products we enrich
+var products = []product{
+	{
+		AppID:       "org.mozilla.firefox",
+		Name:        "Firefox",
+		VersionsURL: ProductDetailsBase + "/firefox_versions.json",
+		VersionKey:  "LATEST_FIREFOX_VERSION",
+		NotesURL:    "https://www.mozilla.org/en-US/firefox/%s/releasenotes/",
+		Extract:     extractFirefoxReleaseNotes,
+	},
+	{
+		AppID:       "org.mozilla.Thunderbird",
+		Name:        "Thunderbird",
+		VersionsURL: ProductDetailsBase + "/thunderbird_versions.json",
+		VersionKey:  "LATEST_THUNDERBIRD_VERSION",
+		NotesURL:    "https://www.thunderbird.net/en-US/thunderbird/%s/releasenotes/",
+		Extract:     extractThunderbirdReleaseNotes,
+	},
+}
+
+// EnrichWithMozillaReleases fetches release notes for Firefox and Thunderbird.
+// Products are fetched concurrently and share a per-run cache of the versions JSON.
 func EnrichWithMozillaReleases(apps []models.App) []models.App {
 	log.Println("Enriching Mozilla products with release notes...")
 
 	enrichedApps := make([]models.App, len(apps))
 	copy(enrichedApps, apps)
 
+	byAppID := make(map[string]product, len(products))
+	for _, p := range products {
+		byAppID[p.AppID] = p
+	}
+
+	cache := newVersionCache()
+	var wg sync.WaitGroup
+
 	for i := range enrichedApps {
 		app := &enrichedApps[i]
 
-		// Check if this is Firefox or Thunderbird
-		if app.ID == "org.mozilla.firefox" {
-			if releases, err := fetchFirefoxReleases(); err == nil {
-				// Replace the single Flathub release with actual Firefox releases
-				app.Releases = releases
-				log.Printf("✅ Added %d Firefox releases", len(releases))
-			} else {
-				log.Printf("⚠️  Failed to fetch Firefox releases: %v", err)
+		p, ok := byAppID[app.ID]
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(app *models.App, p product) {
+			defer wg.Done()
+
+			releases, err := fetchProductReleases(p, cache)
+			if err != nil {
+				log.Printf("⚠️  Failed to fetch %s releases: %v", p.Name, err)
+				app.Debug.Record(models.DebugStep{Stage: "mozilla", Matched: true, Note: err.Error()})
+				return
 			}
-		} else if app.ID == "org.mozilla.Thunderbird" {
-			if releases, err := fetchThunderbirdReleases(); err == nil {
-				// Replace the single Flathub release with actual Thunderbird releases
-				app.Releases = releases
-				log.Printf("✅ Added %d Thunderbird releases", len(releases))
-			} else {
-				log.Printf("⚠️  Failed to fetch Thunderbird releases: %v", err)
+
+			// An undated release dropped by -date-fallback=skip leaves the existing releases alone
+			if len(releases) == 0 {
+				log.Printf("⚠️  Skipping %s: release notes have no date", p.Name)
+				app.Debug.Record(models.DebugStep{Stage: "mozilla", Matched: true, Note: "undated release skipped"})
+				return
 			}
-		}
+
+			// Replace the single Flathub release with actual product releases
+			app.Releases = releases
+			log.Printf("✅ Added %d %s releases", len(releases), p.Name)
+			app.Debug.Record(models.DebugStep{Stage: "mozilla", Matched: true, ReleasesAdded: len(releases), Note: "replaced existing releases"})
+		}(app, p)
 	}
 
+	wg.Wait()
 	return enrichedApps
 }
 
-// fetchFirefoxReleases fetches the latest Firefox release notes
-func fetchFirefoxReleases() ([]models.Release, error) {
-	// First, get the latest version
-	resp, err := http.Get("https://product-details.mozilla.org/1.0/firefox_versions.json")
-	if err != nil {
-		return nil, fmt.Errorf("fetch version info: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read version info: %w", err)
-	}
+// versionCache fetches each product-details versions JSON at most once per run
+type versionCache struct {
+	mu      sync.Mutex
+	entries map[string]*versionEntry
+}
 
-	// Extract version (simple regex since we just need LATEST_FIREFOX_VERSION)
-	versionRe := regexp.MustCompile(`"LATEST_FIREFOX_VERSION":\s*"([^"]+)"`)
-	matches := versionRe.FindStringSubmatch(string(body))
-	if len(matches) < 2 {
-		return nil, fmt.Errorf("could not find latest version")
-	}
+type versionEntry struct {
+	once     sync.Once
+	versions map[string]interface{}
+	err      error
+}
 
-	version := matches[1]
+func newVersionCache() *versionCache {
+	return &versionCache{entries: make(map[string]*versionEntry)}
+}
 
-	// Fetch the release notes page
-	releaseNotesURL := fmt.Sprintf("https://www.mozilla.org/en-US/firefox/%s/releasenotes/", version)
-	resp, err = http.Get(releaseNotesURL)
-	if err != nil {
-		return nil, fmt.Errorf("fetch release notes: %w", err)
+// latest resolves a product's latest version, fetching its versions JSON if needed
+func (c *versionCache) latest(versionsURL, key string) (string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[versionsURL]
+	if !ok {
+		entry = &versionEntry{}
+		c.entries[versionsURL] = entry
 	}
-	defer resp.Body.Close()
+	c.mu.Unlock()
 
-	body, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read release notes: %w", err)
+	entry.once.Do(func() {
+		entry.versions, entry.err = fetchVersions(versionsURL)
+	})
+	if entry.err != nil {
+		return "", entry.err
 	}
 
-	html := string(body)
-
-	// Extract release notes content
-	description := extractFirefoxReleaseNotes(html)
-
-	// Parse release date from page if available
-	dateStr := extractReleaseDate(html)
-	releaseDate := time.Now()
-	if dateStr != "" {
-		// Try ISO 8601 format first (from datetime attribute)
-		formats := []string{"2006-01-02", time.RFC3339, "January 2, 2006"}
-		parsed := false
-		for _, fmt := range formats {
-			if parsedDate, err := time.Parse(fmt, dateStr); err == nil {
-				releaseDate = parsedDate
-				parsed = true
-				break
-			}
-		}
-		if !parsed {
-			log.Printf("⚠️  Could not parse Firefox release date: %s", dateStr)
-		}
+	version, ok := entry.versions[key].(string)
+	if !ok || version == "" {
+		return "", fmt.Errorf("could not find latest version")
 	}
-
-	return []models.Release{
-		{
-			Version:     version,
-			Date:        releaseDate,
-			Title:       fmt.Sprintf("Firefox %s", version),
-			Description: description,
-			URL:         releaseNotesURL,
-			Type:        "mozilla-release",
-		},
-	}, nil
+	return version, nil
 }
 
-// fetchThunderbirdReleases fetches the latest Thunderbird release notes
-func fetchThunderbirdReleases() ([]models.Release, error) {
-	// Thunderbird uses a similar structure but different API
-	resp, err := http.Get("https://product-details.mozilla.org/1.0/thunderbird_versions.json")
+// fetchVersions downloads and decodes a product-details versions JSON
+func fetchVersions(versionsURL string) (map[string]interface{}, error) {
+	resp, err := httpClient.Get(versionsURL)
 	if err != nil {
 		return nil, fmt.Errorf("fetch version info: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read version info: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch version info: unexpected status code: %d", resp.StatusCode)
 	}
 
-	// Extract version
-	versionRe := regexp.MustCompile(`"LATEST_THUNDERBIRD_VERSION":\s*"([^"]+)"`)
-	matches := versionRe.FindStringSubmatch(string(body))
-	if len(matches) < 2 {
-		return nil, fmt.Errorf("could not find latest version")
+	var versions map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
+		return nil, fmt.Errorf("read version info: %w", err)
 	}
 
-	version := matches[1]
+	return versions, nil
+}
+
+// fetchProductReleases fetches the latest release notes for a Mozilla product
+func fetchProductReleases(p product, cache *versionCache) ([]models.Release, error) {
+	version, err := cache.latest(p.VersionsURL, p.VersionKey)
+	if err != nil {
+		return nil, err
+	}
 
 	// Fetch the release notes page
-	releaseNotesURL := fmt.Sprintf("https://www.thunderbird.net/en-US/thunderbird/%s/releasenotes/", version)
-	resp, err = http.Get(releaseNotesURL)
+	releaseNotesURL := fmt.Sprintf(p.NotesURL, version)
+	resp, err := httpClient.Get(releaseNotesURL)
 	if err != nil {
 		return nil, fmt.Errorf("fetch release notes: %w", err)
 	}
 	defer resp.Body.Close()
 
-	body, err = io.ReadAll(resp.Body)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("read release notes: %w", err)
 	}
@@ -157,15 +189,15 @@ func fetchThunderbirdReleases() ([]models.Release, error) {
 	html := string(body)
 
 	// Extract release notes content
-	description := extractThunderbirdReleaseNotes(html)
+	description := p.Extract(html)
 
-	// Parse release date
+	// Parse release date from page if available
 	dateStr := extractReleaseDate(html)
-	releaseDate := time.Now()
+	var releaseDate time.Time
+	parsed := false
 	if dateStr != "" {
 		// Try ISO 8601 format first (from datetime attribute)
 		formats := []string{"2006-01-02", time.RFC3339, "January 2, 2006"}
-		parsed := false
 		for _, fmt := range formats {
 			if parsedDate, err := time.Parse(fmt, dateStr); err == nil {
 				releaseDate = parsedDate
@@ -174,15 +206,20 @@ func fetchThunderbirdReleases() ([]models.Release, error) {
 			}
 		}
 		if !parsed {
-			log.Printf("⚠️  Could not parse Thunderbird release date: %s", dateStr)
+			log.Printf("⚠️  Could not parse %s release date: %s", p.Name, dateStr)
+		}
+	}
+	if !parsed {
+		var keep bool
+		if releaseDate, keep = dates.Fallback(); !keep {
+			return nil, nil
 		}
 	}
 
 	return []models.Release{
 		{
 			Version:     version,
-			Date:        releaseDate,
-			Title:       fmt.Sprintf("Thunderbird %s", version),
+			Date:        releaseDate.UTC(),
 			Description: description,
 			URL:         releaseNotesURL,
 			Type:        "mozilla-release",
@@ -190,33 +227,19 @@ func fetchThunderbirdReleases() ([]models.Release, error) {
 	}, nil
 }
 
-// extractFirefoxReleaseNotes extracts and formats release notes from Firefox HTML
+// sectionHeadingRe matches release notes section headings like <h3>New</h3>
+var sectionHeadingRe = regexp.MustCompile(`<h3[^>]*>\s*([^<]+?)\s*</h3>`)
+
+// extractFirefoxReleaseNotes extracts and formats release notes from Firefox HTML.
+// Every section heading on the page is extracted in page order, so sections
+// Mozilla adds later (beyond New/Fixed/Changed/...) are picked up automatically.
 func extractFirefoxReleaseNotes(html string) string {
 	var sections []string
 
-	// Extract "New" section
-	if newSection := extractSection(html, "New"); newSection != "" {
-		sections = append(sections, "## New\n\n"+newSection)
-	}
-
-	// Extract "Fixed" section
-	if fixedSection := extractSection(html, "Fixed"); fixedSection != "" {
-		sections = append(sections, "## Fixed\n\n"+fixedSection)
-	}
-
-	// Extract "Changed" section
-	if changedSection := extractSection(html, "Changed"); changedSection != "" {
-		sections = append(sections, "## Changed\n\n"+changedSection)
-	}
-
-	// Extract "Enterprise" section
-	if enterpriseSection := extractSection(html, "Enterprise"); enterpriseSection != "" {
-		sections = append(sections, "## Enterprise\n\n"+enterpriseSection)
-	}
-
-	// Extract "Developer" section
-	if devSection := extractSection(html, "Developer"); devSection != "" {
-		sections = append(sections, "## Developer\n\n"+devSection)
+	for _, name := range discoverSections(html) {
+		if section := extractSection(html, name); section != "" {
+			sections = append(sections, fmt.Sprintf("## %s\n\n%s", name, section))
+		}
 	}
 
 	if len(sections) == 0 {
@@ -227,6 +250,23 @@ func extractFirefoxReleaseNotes(html string) string {
 	return markdown.ToHTML(markdownContent)
 }
 
+// discoverSections returns the unique section headings in the order they appear
+func discoverSections(html string) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, match := range sectionHeadingRe.FindAllStringSubmatch(html, -1) {
+		name := strings.TrimSpace(match[1])
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return names
+}
+
 // extractThunderbirdReleaseNotes extracts and formats release notes from Thunderbird HTML
 func extractThunderbirdReleaseNotes(html string) string {
 	// Thunderbird has similar structure to Firefox
@@ -239,14 +279,20 @@ func extractSection(html, sectionName string) string {
 	// The h3 and ul are in different divs, so we need to match more flexibly
 
 	// Find the section starting from the h3
-	sectionStartRe := regexp.MustCompile(fmt.Sprintf(`<h3>%s</h3>`, regexp.QuoteMeta(sectionName)))
+	sectionStartRe := regexp.MustCompile(fmt.Sprintf(`<h3[^>]*>\s*%s\s*</h3>`, regexp.QuoteMeta(sectionName)))
 	startIdx := sectionStartRe.FindStringIndex(html)
 	if startIdx == nil {
 		return ""
 	}
 
-	// Find the next <ul> after this h3 (use (?s) to match across newlines)
+	// Only search up to the next heading so a section without a list
+	// doesn't steal the following section's items
 	remaining := html[startIdx[1]:]
+	if nextIdx := sectionHeadingRe.FindStringIndex(remaining); nextIdx != nil {
+		remaining = remaining[:nextIdx[0]]
+	}
+
+	// Find the next <ul> after this h3 (use (?s) to match across newlines)
 	ulRe := regexp.MustCompile(`(?s)<ul>(.*?)</ul>`)
 	ulMatches := ulRe.FindStringSubmatch(remaining)
 	if len(ulMatches) < 2 {
@@ -0,0 +1,138 @@
+package mozilla
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/dates"
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestEnrichSharesVersionsFetch(t *testing.T) {
+	const versionsURL = "https://product-details.test/1.0/shared_versions.json"
+
+	transport := httpxtest.Bodies(map[string]string{
+		versionsURL:                       `{"LATEST_A": "1.0", "LATEST_B": "2.0"}`,
+		"https://notes.test/a/1.0/notes/": `<p class="c-release-date">February 4, 2026</p>`,
+		"https://notes.test/b/2.0/notes/": `<p class="c-release-date">February 5, 2026</p>`,
+	})
+
+	defer httpx.SetBaseTransport(transport)()
+	origProducts := products
+	defer func() { products = origProducts }()
+
+	products = []product{
+		{AppID: "test.A", Name: "A", VersionsURL: versionsURL, VersionKey: "LATEST_A", NotesURL: "https://notes.test/a/%s/notes/", Extract: extractFirefoxReleaseNotes},
+		{AppID: "test.B", Name: "B", VersionsURL: versionsURL, VersionKey: "LATEST_B", NotesURL: "https://notes.test/b/%s/notes/", Extract: extractFirefoxReleaseNotes},
+	}
+
+	apps := []models.App{{ID: "test.A"}, {ID: "test.B"}, {ID: "test.Other"}}
+	enriched := EnrichWithMozillaReleases(apps)
+
+	if got := transport.Count(versionsURL); got != 1 {
+		t.Errorf("Expected versions JSON to be fetched once, got %d", got)
+	}
+
+	wantVersions := map[string]string{"test.A": "1.0", "test.B": "2.0"}
+	for _, app := range enriched {
+		want, ok := wantVersions[app.ID]
+		if !ok {
+			if len(app.Releases) != 0 {
+				t.Errorf("Expected no releases for %s, got %d", app.ID, len(app.Releases))
+			}
+			continue
+		}
+		if len(app.Releases) != 1 || app.Releases[0].Version != want {
+			t.Errorf("Expected %s to have release %s, got %+v", app.ID, want, app.Releases)
+		}
+	}
+}
+
+func TestDiscoverSectionsIncludesUnknownSections(t *testing.T) {
+	html := `
+<div><h3>New</h3></div>
+<div><ul>
+  <li class="release-note"><div class="release-note-content">Added a thing</div></li>
+</ul></div>
+<div><h3>Web Platform</h3></div>
+<div><ul>
+  <li class="release-note"><div class="release-note-content">Supports <code>Foo</code></div></li>
+</ul></div>
+<div><h3>Unresolved</h3></div>
+<div><h3>Fixed</h3></div>
+<div><ul>
+  <li class="release-note"><div class="release-note-content">Fixed a crash</div></li>
+</ul></div>
+`
+
+	got := discoverSections(html)
+	want := []string{"New", "Web Platform", "Unresolved", "Fixed"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected sections %v in page order, got %v", want, got)
+	}
+
+	if section := extractSection(html, "Web Platform"); section != "- Supports `Foo`" {
+		t.Errorf("Expected the unknown section to be extracted, got %q", section)
+	}
+
+	// A heading without its own list must not borrow the next section's items
+	if section := extractSection(html, "Unresolved"); section != "" {
+		t.Errorf("Expected empty section for heading without a list, got %q", section)
+	}
+}
+
+func TestFetchReleaseNotesDateIsUTC(t *testing.T) {
+	const versionsURL = "https://product-details.test/1.0/shared_versions.json"
+
+	transport := httpxtest.Bodies(map[string]string{
+		versionsURL:                       `{"LATEST_A": "1.0"}`,
+		"https://notes.test/a/1.0/notes/": `<time datetime="2026-02-04T09:00:00-08:00">February 4, 2026</time>`,
+	})
+
+	defer httpx.SetBaseTransport(transport)()
+	origProducts := products
+	defer func() { products = origProducts }()
+
+	products = []product{
+		{AppID: "test.A", Name: "A", VersionsURL: versionsURL, VersionKey: "LATEST_A", NotesURL: "https://notes.test/a/%s/notes/", Extract: extractFirefoxReleaseNotes},
+	}
+
+	enriched := EnrichWithMozillaReleases([]models.App{{ID: "test.A"}})
+	if len(enriched[0].Releases) != 1 {
+		t.Fatalf("Expected 1 release, got %+v", enriched[0].Releases)
+	}
+
+	date := enriched[0].Releases[0].Date
+	want := time.Date(2026, 2, 4, 17, 0, 0, 0, time.UTC)
+	if !date.Equal(want) || date.Location() != time.UTC {
+		t.Errorf("Expected %s, got %s", want, date)
+	}
+}
+
+func TestEnrichSkippedUndatedKeepsReleases(t *testing.T) {
+	const versionsURL = "https://product-details.test/1.0/shared_versions.json"
+
+	transport := httpxtest.Bodies(map[string]string{
+		versionsURL:                       `{"LATEST_A": "1.0"}`,
+		"https://notes.test/a/1.0/notes/": `<h3>New</h3>`,
+	})
+
+	defer httpx.SetBaseTransport(transport)()
+	origProducts := products
+	defer func() { products = origProducts }()
+	defer dates.SetFallback(dates.DefaultPolicy)
+
+	products = []product{
+		{AppID: "test.A", Name: "A", VersionsURL: versionsURL, VersionKey: "LATEST_A", NotesURL: "https://notes.test/a/%s/notes/", Extract: extractFirefoxReleaseNotes},
+	}
+	dates.SetFallback(dates.PolicySkip)
+
+	existing := []models.Release{{Version: "0.9", Type: "flathub"}}
+	enriched := EnrichWithMozillaReleases([]models.App{{ID: "test.A", Releases: existing}})
+	if len(enriched[0].Releases) != 1 || enriched[0].Releases[0].Version != "0.9" {
+		t.Errorf("Expected the existing release to be kept, got %+v", enriched[0].Releases)
+	}
+}
@@ -0,0 +1,55 @@
+// Package noise thins out release lists: CI and nightly builds matched by a
+// pattern, and bursts of releases published close together
+package noise
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// Filter drops releases whose title or version matches pattern and, when
+// window is positive, collapses releases published within window of a newer
+// kept release so only the latest of each cluster remains. The original
+// release order is preserved; releases is returned as is when nothing is dropped.
+func Filter(releases []models.Release, window time.Duration, pattern *regexp.Regexp) []models.Release {
+	var candidates []int
+	for j, release := range releases {
+		if pattern != nil && (pattern.MatchString(release.Title) || pattern.MatchString(release.Version)) {
+			continue
+		}
+		candidates = append(candidates, j)
+	}
+
+	keep := make(map[int]bool, len(candidates))
+	if window > 0 {
+		// Newest first, so each cluster keeps its latest release
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return releases[candidates[a]].Date.After(releases[candidates[b]].Date)
+		})
+		var lastKept time.Time
+		for k, j := range candidates {
+			if k == 0 || lastKept.Sub(releases[j].Date) >= window {
+				keep[j] = true
+				lastKept = releases[j].Date
+			}
+		}
+	} else {
+		for _, j := range candidates {
+			keep[j] = true
+		}
+	}
+
+	if len(keep) == len(releases) {
+		return releases
+	}
+	filtered := make([]models.Release, 0, len(keep))
+	for j, release := range releases {
+		if keep[j] {
+			filtered = append(filtered, release)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,47 @@
+package noise
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestFilter(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2026, 1, day, hour, 0, 0, 0, time.UTC) }
+	releases := []models.Release{
+		{Version: "v1.3.0", Title: "v1.3.0", Date: at(5, 18)},
+		{Version: "v1.2.2", Title: "v1.2.2", Date: at(5, 12)},
+		{Version: "v1.2.1", Title: "v1.2.1", Date: at(5, 9)},
+		{Version: "nightly-20260103", Title: "Nightly build", Date: at(3, 1)},
+		{Version: "v1.2.0", Title: "v1.2.0", Date: at(1, 10)},
+	}
+
+	tests := []struct {
+		name   string
+		window time.Duration
+		noise  *regexp.Regexp
+		want   []string
+	}{
+		{name: "off", want: []string{"v1.3.0", "v1.2.2", "v1.2.1", "nightly-20260103", "v1.2.0"}},
+		{name: "collapse same day", window: 24 * time.Hour, want: []string{"v1.3.0", "nightly-20260103", "v1.2.0"}},
+		{name: "noise pattern", noise: regexp.MustCompile(`(?i)nightly`), want: []string{"v1.3.0", "v1.2.2", "v1.2.1", "v1.2.0"}},
+		{name: "both", window: 24 * time.Hour, noise: regexp.MustCompile(`(?i)nightly`), want: []string{"v1.3.0", "v1.2.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Filter(append([]models.Release(nil), releases...), tt.window, tt.noise)
+
+			var versions []string
+			for _, release := range got {
+				versions = append(versions, release.Version)
+			}
+			if strings.Join(versions, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected %v, got %v", tt.want, versions)
+			}
+		})
+	}
+}
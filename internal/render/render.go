@@ -0,0 +1,76 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// FuncMap returns the helper functions available to output templates
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		// formatDate formats a time with a Go layout, e.g. {{formatDate .Date "Jan 2, 2006"}}
+		"formatDate": func(t time.Time, layout string) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format(layout)
+		},
+		// date formats a time as YYYY-MM-DD
+		"date": func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return t.Format("2006-01-02")
+		},
+		"truncate": truncate,
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"join":     strings.Join,
+		// json encodes a value as compact JSON
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			return string(data), nil
+		},
+	}
+}
+
+// truncate shortens s to at most n runes, adding an ellipsis when cut
+func truncate(s string, n int) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n])) + "…"
+}
+
+// WriteTemplate renders the output data through a user-provided Go text/template
+// and writes the result to path
+func WriteTemplate(data *models.OutputData, templatePath, path string) error {
+	tmpl, err := template.New(filepath.Base(templatePath)).Funcs(FuncMap()).ParseFiles(templatePath)
+	if err != nil {
+		return fmt.Errorf("parse template: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := tmpl.Execute(file, data); err != nil {
+		return fmt.Errorf("execute template: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestWriteTemplate(t *testing.T) {
+	dir := t.TempDir()
+	templatePath := filepath.Join(dir, "apps.md.tmpl")
+	outputPath := filepath.Join(dir, "apps.md")
+
+	tmpl := `# Apps ({{.Metadata.Stats.AppsTotal}})
+{{range .Apps}}- {{.Name}}{{range .Releases}} {{.Version}} ({{date .Date}}): {{truncate .Title 10}}{{end}}
+{{end}}`
+	if err := os.WriteFile(templatePath, []byte(tmpl), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	data := &models.OutputData{
+		Metadata: models.Metadata{Stats: models.Stats{AppsTotal: 2}},
+		Apps: []models.App{
+			{
+				Name: "Builder",
+				Releases: []models.Release{
+					{Version: "47.0", Date: time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC), Title: "Builder 47.0 is here"},
+				},
+			},
+			{Name: "Calculator"},
+		},
+	}
+
+	if err := WriteTemplate(data, templatePath, outputPath); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+
+	want := "# Apps (2)\n- Builder 47.0 (2026-02-03): Builder 47…\n- Calculator\n"
+	if string(got) != want {
+		t.Errorf("Unexpected output:\ngot:  %q\nwant: %q", got, want)
+	}
+}
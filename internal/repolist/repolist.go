@@ -0,0 +1,102 @@
+package repolist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// Parse reads a list of repositories, one per line, and builds minimal App entries
+// with the right SourceRepo so the release enrichers can process them.
+// Accepts lines like "github.com/owner/repo" or "https://gitlab.com/group/subgroup/project".
+// Blank lines and lines starting with "#" are ignored; malformed lines are skipped with a warning.
+func Parse(r io.Reader) ([]models.App, error) {
+	var apps []models.App
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		sourceRepo, err := parseLine(line)
+		if err != nil {
+			log.Printf("⚠️  Skipping line %d (%q): %v", lineNum, line, err)
+			continue
+		}
+
+		id := strings.TrimPrefix(sourceRepo.URL, "https://")
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		apps = append(apps, models.App{
+			ID:          id,
+			Name:        sourceRepo.Repo,
+			Summary:     fmt.Sprintf("Releases from %s", id),
+			FlathubURL:  sourceRepo.URL, // Link to the repository
+			SourceRepo:  sourceRepo,
+			FetchedAt:   time.Now().UTC(),
+			PackageType: "repo",
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read repo list: %w", err)
+	}
+
+	return apps, nil
+}
+
+// parseLine converts a single repository reference into a SourceRepo
+func parseLine(line string) (*models.SourceRepo, error) {
+	ref := strings.TrimPrefix(line, "https://")
+	ref = strings.TrimPrefix(ref, "http://")
+	ref = strings.TrimSuffix(strings.TrimSuffix(ref, "/"), ".git")
+
+	parts := strings.Split(ref, "/")
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("expected host/owner/repo")
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("empty path segment")
+		}
+	}
+
+	host := strings.ToLower(parts[0])
+	switch {
+	case host == "github.com":
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("GitHub repos must be github.com/owner/repo")
+		}
+		return &models.SourceRepo{
+			Type:  "github",
+			URL:   "https://" + strings.Join(parts, "/"),
+			Owner: parts[1],
+			Repo:  parts[2],
+		}, nil
+
+	case strings.Contains(host, "gitlab"):
+		// GitLab supports nested groups: everything but the last segment is the namespace
+		return &models.SourceRepo{
+			Type:  "gitlab",
+			URL:   "https://" + strings.Join(parts, "/"),
+			Owner: strings.Join(parts[1:len(parts)-1], "/"),
+			Repo:  parts[len(parts)-1],
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported host %q", host)
+	}
+}
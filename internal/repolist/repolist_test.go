@@ -0,0 +1,53 @@
+package repolist
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := `# Hand-picked repos
+github.com/ublue-os/bluefin
+https://github.com/cli/cli.git
+
+gitlab.com/group/subgroup/project
+https://gitlab.gnome.org/GNOME/file-roller/
+github.com/missing-repo
+codeberg.org/owner/repo
+github.com/ublue-os/bluefin
+`
+
+	apps, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		id    string
+		typ   string
+		owner string
+		repo  string
+	}{
+		{id: "github.com/ublue-os/bluefin", typ: "github", owner: "ublue-os", repo: "bluefin"},
+		{id: "github.com/cli/cli", typ: "github", owner: "cli", repo: "cli"},
+		{id: "gitlab.com/group/subgroup/project", typ: "gitlab", owner: "group/subgroup", repo: "project"},
+		{id: "gitlab.gnome.org/GNOME/file-roller", typ: "gitlab", owner: "GNOME", repo: "file-roller"},
+	}
+
+	if len(apps) != len(tests) {
+		t.Fatalf("Expected %d apps (malformed and duplicate lines skipped), got %d", len(tests), len(apps))
+	}
+
+	for i, tt := range tests {
+		app := apps[i]
+		if app.ID != tt.id {
+			t.Errorf("Expected ID %q, got %q", tt.id, app.ID)
+		}
+		if app.SourceRepo == nil {
+			t.Fatalf("Expected SourceRepo for %s", tt.id)
+		}
+		if app.SourceRepo.Type != tt.typ || app.SourceRepo.Owner != tt.owner || app.SourceRepo.Repo != tt.repo {
+			t.Errorf("Unexpected SourceRepo for %s: %+v", tt.id, app.SourceRepo)
+		}
+	}
+}
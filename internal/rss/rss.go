@@ -1,11 +1,20 @@
 package rss
 
 import (
+	"bytes"
 	"context"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/castrojo/bluefin-releases/internal/dates"
+	"github.com/castrojo/bluefin-releases/internal/httpx"
 	"github.com/castrojo/bluefin-releases/internal/models"
 	"github.com/mmcdole/gofeed"
 )
@@ -18,9 +27,10 @@ type Parser struct {
 
 // NewParser creates a new RSS parser with custom HTTP client
 func NewParser(timeout time.Duration) *Parser {
-	httpClient := &http.Client{
-		Timeout: timeout,
-	}
+	httpClient := httpx.NewClientWith(httpx.ClientOptions{
+		Timeout:   timeout,
+		UserAgent: httpx.DefaultUserAgent,
+	})
 
 	parser := gofeed.NewParser()
 	parser.Client = httpClient
@@ -31,24 +41,88 @@ func NewParser(timeout time.Duration) *Parser {
 	}
 }
 
-// FetchAndParse fetches and parses an RSS feed from the given URL
+// ErrNotAFeed is returned when a response isn't an RSS or Atom document at
+// all, such as an HTML error or login page
+var ErrNotAFeed = errors.New("not an RSS or Atom feed")
+
+// FetchAndParse fetches and parses an RSS feed from the given URL. A feed
+// with no items, or one whose root is a feed element but fails to parse, is
+// returned empty without error; anything else fails with ErrNotAFeed.
 func (p *Parser) FetchAndParse(ctx context.Context, url string) (*gofeed.Feed, error) {
-	feed, err := p.parser.ParseURLWithContext(url, ctx)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("parse RSS feed: %w", err)
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	if !isFeed(body) {
+		return nil, fmt.Errorf("parse RSS feed %s: %w", url, ErrNotAFeed)
+	}
+	feed, err := p.parser.ParseString(string(body))
+	if err != nil {
+		log.Printf("⚠️  Malformed feed at %s, treating it as empty: %v", url, err)
+		return &gofeed.Feed{}, nil
+	}
+	if feed == nil {
+		feed = &gofeed.Feed{}
 	}
 	return feed, nil
 }
 
-// ConvertToReleases converts RSS feed items to Release structs
-func ConvertToReleases(feed *gofeed.Feed, releaseType string) []models.Release {
+// isFeed reports whether a document's root element is an RSS (<rss>, RDF) or
+// Atom (<feed>) root
+func isFeed(body []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			switch strings.ToLower(start.Name.Local) {
+			case "rss", "feed", "rdf":
+				return true
+			}
+			return false
+		}
+	}
+}
+
+// VersionExtractor picks the release version out of a feed item. Feeds encode
+// versions differently, so callers pass one suited to the source.
+type VersionExtractor func(item *gofeed.Item) string
+
+// ConvertToReleases converts RSS feed items to Release structs, taking each
+// version from extract (DefaultVersion when nil)
+func ConvertToReleases(feed *gofeed.Feed, releaseType string, extract VersionExtractor) []models.Release {
+	if extract == nil {
+		extract = DefaultVersion
+	}
 	releases := make([]models.Release, 0, len(feed.Items))
 
 	for _, item := range feed.Items {
+		// Atom entries, such as GitHub's, carry their notes in <content>
+		description := item.Description
+		if description == "" {
+			description = item.Content
+		}
 		release := models.Release{
-			Version:     extractVersion(item),
+			Version:     extract(item),
 			Title:       item.Title,
-			Description: item.Description,
+			Description: description,
 			URL:         item.Link,
 			Type:        releaseType,
 		}
@@ -59,8 +133,12 @@ func ConvertToReleases(feed *gofeed.Feed, releaseType string) []models.Release {
 		} else if item.UpdatedParsed != nil {
 			release.Date = *item.UpdatedParsed
 		} else {
-			release.Date = time.Now()
+			var keep bool
+			if release.Date, keep = dates.Fallback(); !keep {
+				continue
+			}
 		}
+		release.Date = release.Date.UTC()
 
 		releases = append(releases, release)
 	}
@@ -68,8 +146,9 @@ func ConvertToReleases(feed *gofeed.Feed, releaseType string) []models.Release {
 	return releases
 }
 
-// extractVersion tries to extract version from RSS item
-func extractVersion(item *gofeed.Item) string {
+// DefaultVersion is the generic heuristic: a title starting with "v", then the
+// item's GUID
+func DefaultVersion(item *gofeed.Item) string {
 	// Try to extract version from title (e.g., "v1.2.3 - Release Title")
 	if item.Title != "" {
 		// Look for version patterns in title
@@ -89,6 +168,24 @@ func extractVersion(item *gofeed.Item) string {
 	return "unknown"
 }
 
+// GitHubTagVersion reads the tag from a GitHub releases feed entry, whose link
+// ends in /releases/tag/<tag> and whose ID ends in /<tag>. Falls back to
+// DefaultVersion for entries in neither form.
+func GitHubTagVersion(item *gofeed.Item) string {
+	if _, tag, ok := strings.Cut(item.Link, "/releases/tag/"); ok && tag != "" {
+		if unescaped, err := url.PathUnescape(tag); err == nil {
+			return unescaped
+		}
+		return tag
+	}
+	if strings.HasPrefix(item.GUID, "tag:github.com,") {
+		if i := strings.LastIndex(item.GUID, "/"); i >= 0 && i < len(item.GUID)-1 {
+			return item.GUID[i+1:]
+		}
+	}
+	return DefaultVersion(item)
+}
+
 // FetchGitHubReleases fetches releases from a GitHub repository RSS feed
 func (p *Parser) FetchGitHubReleases(ctx context.Context, owner, repo string) ([]models.Release, error) {
 	url := fmt.Sprintf("https://github.com/%s/%s/releases.atom", owner, repo)
@@ -98,7 +195,7 @@ func (p *Parser) FetchGitHubReleases(ctx context.Context, owner, repo string) ([
 		return nil, fmt.Errorf("fetch GitHub releases: %w", err)
 	}
 
-	return ConvertToReleases(feed, "github-release"), nil
+	return ConvertToReleases(feed, "github-release", GitHubTagVersion), nil
 }
 
 // FetchFlathubRSS fetches app updates from Flathub RSS feed (if available)
@@ -0,0 +1,120 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/httpx"
+	"github.com/castrojo/bluefin-releases/internal/httpx/httpxtest"
+	"github.com/mmcdole/gofeed"
+)
+
+func TestConvertToReleasesVersionExtractors(t *testing.T) {
+	published := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	// A GitHub releases feed: titles are release names, not tags
+	feed := &gofeed.Feed{Items: []*gofeed.Item{
+		{
+			Title:           "Spring Release",
+			Link:            "https://github.com/cli/cli/releases/tag/v2.70.0",
+			GUID:            "tag:github.com,2008:Repository/212613049/v2.70.0",
+			PublishedParsed: &published,
+		},
+		{
+			Title:           "v2.69.0",
+			Link:            "https://github.com/cli/cli/releases/tag/release%2F2.69",
+			GUID:            "tag:github.com,2008:Repository/212613049/release/2.69",
+			PublishedParsed: &published,
+		},
+		{
+			Title:           "Nightly",
+			GUID:            "tag:github.com,2008:Repository/212613049/nightly",
+			PublishedParsed: &published,
+		},
+	}}
+
+	tests := []struct {
+		name    string
+		extract VersionExtractor
+		want    []string
+	}{
+		{
+			name: "default heuristic",
+			want: []string{"tag:github.com,2008:Repository/212613049/v2.70.0", "v2.69.0", "tag:github.com,2008:Repository/212613049/nightly"},
+		},
+		{
+			name:    "GitHub tags",
+			extract: GitHubTagVersion,
+			want:    []string{"v2.70.0", "release/2.69", "nightly"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			releases := ConvertToReleases(feed, "github-release", tt.extract)
+			if len(releases) != len(tt.want) {
+				t.Fatalf("Expected %d releases, got %d", len(tt.want), len(releases))
+			}
+			for i, release := range releases {
+				if release.Version != tt.want[i] {
+					t.Errorf("Release %d: expected version %q, got %q", i, tt.want[i], release.Version)
+				}
+				if !release.Date.Equal(published) || release.Type != "github-release" {
+					t.Errorf("Release %d: unexpected date/type %s %q", i, release.Date, release.Type)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchGitHubReleasesEmptyAndNonFeeds(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{
+			name: "empty Atom feed",
+			body: `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xml:lang="en-US">
+  <id>tag:github.com,2008:https://github.com/example/app/releases</id>
+  <title>Release notes from app</title>
+</feed>`,
+		},
+		{
+			name: "truncated Atom feed",
+			body: `<?xml version="1.0" encoding="UTF-8"?><feed xmlns="http://www.w3.org/2005/Atom"><entry><title>v1.0`,
+		},
+		{
+			name:    "HTML page",
+			body:    "<!DOCTYPE html>\n<html><head><title>Page not found · GitHub</title></head><body></body></html>",
+			wantErr: true,
+		},
+		{
+			name:    "blank response",
+			body:    "   \n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer httpx.SetBaseTransport(httpxtest.Bodies(map[string]string{"": tt.body}))()
+
+			releases, err := NewParser(5*time.Second).FetchGitHubReleases(context.Background(), "example", "app")
+			if tt.wantErr {
+				if !errors.Is(err, ErrNotAFeed) {
+					t.Errorf("Expected ErrNotAFeed, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if releases == nil || len(releases) != 0 {
+				t.Errorf("Expected an empty release list, got %#v", releases)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Package search builds a trimmed, client-side search index of apps
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// Entry is one app in the search index. Field names match what lunr and
+// Fuse.js are usually configured with; release notes are deliberately left
+// out so the index stays small.
+type Entry struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Summary  string   `json:"summary,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+	Category string   `json:"category,omitempty"`
+}
+
+// stopWords are too common in app summaries to be useful keywords
+var stopWords = map[string]bool{
+	"and": true, "app": true, "for": true, "from": true, "the": true,
+	"with": true, "your": true, "you": true, "that": true, "this": true,
+}
+
+// BuildIndex returns one entry per app, sorted by ID
+func BuildIndex(apps []models.App) []Entry {
+	entries := make([]Entry, 0, len(apps))
+	for _, app := range apps {
+		entry := Entry{
+			ID:       app.ID,
+			Name:     app.Name,
+			Summary:  app.Summary,
+			Keywords: keywords(app),
+		}
+		if len(app.Categories) > 0 {
+			entry.Category = app.Categories[0]
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+// WriteIndex writes the search index of apps to path as compact JSON
+func WriteIndex(apps []models.App, path string) error {
+	data, err := json.Marshal(BuildIndex(apps))
+	if err != nil {
+		return fmt.Errorf("encode search index: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	return nil
+}
+
+// keywords derives lowercase search terms from an app's name, summary and
+// categories, dropping short and stop words. Sorted and unique.
+func keywords(app models.App) []string {
+	text := strings.Join(append([]string{app.Name, app.Summary}, app.Categories...), " ")
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, word := range words {
+		if len(word) < 3 || stopWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		result = append(result, word)
+	}
+	sort.Strings(result)
+	return result
+}
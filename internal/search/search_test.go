@@ -0,0 +1,66 @@
+package search
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestWriteIndex(t *testing.T) {
+	apps := []models.App{
+		{
+			ID:         "org.gnome.Loupe",
+			Name:       "Loupe",
+			Summary:    "View images with the GNOME image viewer",
+			Categories: []string{"Graphics", "Viewer"},
+			Releases:   []models.Release{{Version: "49.1", Description: "<p>Long release notes</p>"}},
+		},
+		{ID: "com.example.Bare", Name: "Bare"},
+	}
+
+	path := filepath.Join(t.TempDir(), "index.json")
+	if err := WriteIndex(apps, path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read index: %v", err)
+	}
+	var raw []map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Index is not valid JSON: %v", err)
+	}
+	if len(raw) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(raw))
+	}
+	allowed := map[string]bool{"id": true, "name": true, "summary": true, "keywords": true, "category": true}
+	for _, entry := range raw {
+		for field := range entry {
+			if !allowed[field] {
+				t.Errorf("Unexpected field %q in index entry", field)
+			}
+		}
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("Failed to decode entries: %v", err)
+	}
+	if entries[0].ID != "com.example.Bare" || entries[1].ID != "org.gnome.Loupe" {
+		t.Errorf("Expected entries sorted by ID, got %s, %s", entries[0].ID, entries[1].ID)
+	}
+
+	loupe := entries[1]
+	if loupe.Name != "Loupe" || loupe.Category != "Graphics" || loupe.Summary != "View images with the GNOME image viewer" {
+		t.Errorf("Unexpected entry: %+v", loupe)
+	}
+	wantKeywords := []string{"gnome", "graphics", "image", "images", "loupe", "view", "viewer"}
+	if !reflect.DeepEqual(loupe.Keywords, wantKeywords) {
+		t.Errorf("Expected keywords %v, got %v", wantKeywords, loupe.Keywords)
+	}
+}
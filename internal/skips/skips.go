@@ -0,0 +1,93 @@
+// Package skips collects the apps and packages excluded during a run, with
+// the reason, so they can be audited from a single report
+package skips
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Reason categories
+const (
+	ReasonDeprecated = "deprecated" // Homebrew formula is deprecated or disabled
+	ReasonNonLinux   = "non-linux"  // Homebrew formula has no Linux bottles
+	ReasonDraft      = "draft"      // Unpublished OS release
+	ReasonPrerelease = "prerelease" // OS pre-release
+	ReasonNotFound   = "not-found"  // Flathub has no details for the app (404)
+	ReasonBadTag     = "bad-tag"    // OS release tag isn't "<stream>-<build>"
+)
+
+// Skip is one excluded app, package, or release
+type Skip struct {
+	ID          string `json:"id"`
+	PackageType string `json:"packageType"` // "flatpak", "homebrew", or "os"
+	Reason      string `json:"reason"`
+	Detail      string `json:"detail,omitempty"`
+}
+
+// Report is the skip report written by WriteFile
+type Report struct {
+	Total    int            `json:"total"`
+	ByReason map[string]int `json:"byReason"`
+	Skipped  []Skip         `json:"skipped"` // Sorted by reason, then ID
+}
+
+var (
+	skippedMu sync.Mutex
+	skipped   = make(map[string]Skip)
+)
+
+// Record adds a skip to the report. Recording the same ID and reason again
+// (e.g. an OS release seen by several streams) keeps the first entry.
+func Record(id, packageType, reason, detail string) {
+	skippedMu.Lock()
+	defer skippedMu.Unlock()
+
+	key := reason + "\x00" + id
+	if _, ok := skipped[key]; !ok {
+		skipped[key] = Skip{ID: id, PackageType: packageType, Reason: reason, Detail: detail}
+	}
+}
+
+// Collect returns everything recorded so far this run
+func Collect() Report {
+	skippedMu.Lock()
+	defer skippedMu.Unlock()
+
+	report := Report{ByReason: make(map[string]int), Skipped: make([]Skip, 0, len(skipped))}
+	for _, skip := range skipped {
+		report.Skipped = append(report.Skipped, skip)
+		report.ByReason[skip.Reason]++
+	}
+	sort.Slice(report.Skipped, func(i, j int) bool {
+		a, b := report.Skipped[i], report.Skipped[j]
+		if a.Reason != b.Reason {
+			return a.Reason < b.Reason
+		}
+		return a.ID < b.ID
+	})
+	report.Total = len(report.Skipped)
+	return report
+}
+
+// Reset discards all recorded skips
+func Reset() {
+	skippedMu.Lock()
+	defer skippedMu.Unlock()
+	skipped = make(map[string]Skip)
+}
+
+// WriteFile writes the skip report to path as indented JSON
+func WriteFile(report Report, path string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal skip report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package skips
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordDedupesAndWrites(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	Record("stable-20260303", "os", ReasonDraft, "Stable 20260303")
+	Record("stable-20260303", "os", ReasonDraft, "seen again by another stream")
+	Record("org.example.Gone", "flatpak", ReasonNotFound, "no longer listed on Flathub")
+
+	path := filepath.Join(t.TempDir(), "skipped.json")
+	if err := WriteFile(Collect(), path); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read skip report: %v", err)
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Skip report is not valid JSON: %v", err)
+	}
+	if report.Total != 2 {
+		t.Errorf("Expected 2 skips, got %d", report.Total)
+	}
+	if report.Skipped[0].Detail != "Stable 20260303" {
+		t.Errorf("Expected the first recording to be kept, got %q", report.Skipped[0].Detail)
+	}
+	if report.ByReason[ReasonNotFound] != 1 {
+		t.Errorf("Expected one not-found skip, got %v", report.ByReason)
+	}
+}
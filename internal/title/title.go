@@ -0,0 +1,73 @@
+// Package title gives every release a title from one template, so feeds and
+// the dashboard name releases the same way whichever source they came from
+package title
+
+import (
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+	"github.com/castrojo/bluefin-releases/internal/render"
+)
+
+// Default keeps an upstream release name and titles releases without one,
+// such as Flathub appstream and Mozilla releases, "<app> <version>"
+const Default = `{{with .Title}}{{.}}{{else}}{{.AppName}} {{.Version}}{{end}}`
+
+// Data is what a title template can reference
+type Data struct {
+	AppName string
+	AppID   string
+	Version string
+	Title   string // The release's upstream name, empty when the source has none
+	Type    string
+	Date    time.Time
+}
+
+// Parse compiles a title template, which can use the output template functions
+func Parse(text string) (*template.Template, error) {
+	return template.New("release-title").Funcs(render.FuncMap()).Parse(text)
+}
+
+// defaultTemplate renders Default when a custom template fails or renders empty
+var defaultTemplate = template.Must(Parse(Default))
+
+// Format renders a release title through tmpl, falling back to Default when
+// the template fails or renders empty
+func Format(tmpl *template.Template, app models.App, release models.Release) string {
+	data := Data{
+		AppName: app.Name,
+		AppID:   app.ID,
+		Version: release.Version,
+		Title:   release.Title,
+		Type:    release.Type,
+		Date:    release.Date,
+	}
+	if title := execute(tmpl, data); title != "" {
+		return title
+	}
+	if title := execute(defaultTemplate, data); title != "" {
+		return title
+	}
+	return release.Version
+}
+
+// execute renders tmpl, returning "" when it fails
+func execute(tmpl *template.Template, data Data) string {
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// Apply titles every release of apps through tmpl
+func Apply(apps []models.App, tmpl *template.Template) []models.App {
+	for i := range apps {
+		for j := range apps[i].Releases {
+			apps[i].Releases[j].Title = Format(tmpl, apps[i], apps[i].Releases[j])
+		}
+	}
+	return apps
+}
@@ -0,0 +1,45 @@
+package title
+
+import (
+	"testing"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{name: "default", template: Default, want: []string{"Loupe 46.0", "Loupe 47.1 is out", "Loupe v48.0"}},
+		{name: "upstream titles", template: "{{.Title}}", want: []string{"Loupe 46.0", "Loupe 47.1 is out", "Loupe v48.0"}},
+		{name: "app and version", template: "{{.AppName}} {{.Version}}", want: []string{"Loupe 46.0", "Loupe 47.1", "Loupe v48.0"}},
+		{name: "empty falls back to default", template: `{{if eq .Type "appstream"}}{{.Version}}{{end}}`, want: []string{"46.0", "Loupe 47.1 is out", "Loupe v48.0"}},
+		{name: "custom with functions", template: `{{.Version}} ({{date .Date}})`, want: []string{"46.0 ()", "47.1 ()", "v48.0 ()"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.template)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			apps := Apply([]models.App{{
+				ID:   "org.gnome.Loupe",
+				Name: "Loupe",
+				Releases: []models.Release{
+					{Version: "46.0", Type: "appstream"},
+					{Version: "47.1", Title: "Loupe 47.1 is out", Type: "gitlab-release"},
+					{Version: "v48.0", Type: "github-release"},
+				},
+			}}, tmpl)
+
+			for i, want := range tt.want {
+				if got := apps[0].Releases[i].Title; got != want {
+					t.Errorf("Release %d: expected %q, got %q", i, want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package version
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+// breakingNoteRe matches explicit breaking-change callouts in release notes.
+// The bare word must be uppercase ("BREAKING:") to avoid prose like "fixed a breaking bug".
+var breakingNoteRe = regexp.MustCompile(`\bBREAKING\b|(?i)\bbreaking[ -]changes?\b`)
+
+// MarkBreaking flags releases that explicitly call out breaking changes or
+// bump the major version over the previous release. Calendar versions are
+// never treated as major bumps.
+func MarkBreaking(releases []models.Release) {
+	// Walk oldest to newest so each release can compare with its predecessor
+	order := make([]int, len(releases))
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return releases[order[a]].Date.Before(releases[order[b]].Date)
+	})
+
+	for k, j := range order {
+		release := &releases[j]
+		notes := release.DescriptionSource
+		if notes == "" {
+			notes = release.Description
+		}
+		if breakingNoteRe.MatchString(release.Title) || breakingNoteRe.MatchString(notes) {
+			release.Breaking = true
+			continue
+		}
+		if k == 0 {
+			continue
+		}
+
+		previous := releases[order[k-1]]
+		if IsCalendar(release.Version) || IsCalendar(previous.Version) {
+			continue
+		}
+		current, ok := Major(release.Version)
+		prev, prevOK := Major(previous.Version)
+		release.Breaking = ok && prevOK && current > prev
+	}
+}
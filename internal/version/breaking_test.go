@@ -0,0 +1,55 @@
+package version
+
+import (
+	"testing"
+	"time"
+
+	"github.com/castrojo/bluefin-releases/internal/models"
+)
+
+func TestMarkBreaking(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		releases []models.Release
+		want     map[string]bool
+	}{
+		{
+			name: "explicit BREAKING note",
+			releases: []models.Release{
+				{Version: "1.5.0", Date: day(2), DescriptionSource: "## BREAKING\n- Config moved to ~/.config"},
+				{Version: "1.4.0", Date: day(1), DescriptionSource: "Fixed a breaking bug in the parser"},
+			},
+			want: map[string]bool{"1.5.0": true, "1.4.0": false},
+		},
+		{
+			name: "major version jump",
+			releases: []models.Release{
+				{Version: "v3.0.0", Date: day(3)},
+				{Version: "v2.9.1", Date: day(2)},
+				{Version: "v2.9.0", Date: day(1)},
+			},
+			want: map[string]bool{"v3.0.0": true, "v2.9.1": false, "v2.9.0": false},
+		},
+		{
+			name: "calendar versions are not major bumps",
+			releases: []models.Release{
+				{Version: "2026.01.1", Date: day(2)},
+				{Version: "2025.12.3", Date: day(1)},
+			},
+			want: map[string]bool{"2026.01.1": false, "2025.12.3": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			MarkBreaking(tt.releases)
+			for _, release := range tt.releases {
+				if release.Breaking != tt.want[release.Version] {
+					t.Errorf("Expected %s breaking=%v, got %v", release.Version, tt.want[release.Version], release.Breaking)
+				}
+			}
+		})
+	}
+}
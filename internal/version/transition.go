@@ -0,0 +1,30 @@
+package version
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// arrowTransitionRe matches "X ➡️ Y", "X → Y" and "X -> Y"
+	arrowTransitionRe = regexp.MustCompile(`(v?\d[\w.+~:-]*)\s*(?:➡️|➡|→|->)\s*(v?\d[\w.+~:-]*)`)
+	// textTransitionRe matches "from X to Y" and bare "X to Y"; both sides need
+	// a dot so prose like "3 to 5 times" isn't taken for versions
+	textTransitionRe = regexp.MustCompile(`(?i)(?:\bfrom\s+)?\b(v?\d+(?:\.[\w+~-]+)+)\s+to\s+(v?\d+(?:\.[\w+~-]+)+)`)
+)
+
+// ParseTransition extracts an upgrade such as "25.2.7 ➡️ 25.2.8" or
+// "updated from 1.2 to 1.3" from s, returning the old and new versions
+func ParseTransition(s string) (from, to string, ok bool) {
+	for _, re := range []*regexp.Regexp{arrowTransitionRe, textTransitionRe} {
+		if match := re.FindStringSubmatch(s); match != nil {
+			return trimVersion(match[1]), trimVersion(match[2]), true
+		}
+	}
+	return "", "", false
+}
+
+// trimVersion drops sentence punctuation caught at the end of a version
+func trimVersion(v string) string {
+	return strings.TrimRight(v, ".:-")
+}
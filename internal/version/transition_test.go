@@ -0,0 +1,29 @@
+package version
+
+import "testing"
+
+func TestParseTransition(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantFrom string
+		wantTo   string
+		wantOK   bool
+	}{
+		{"25.2.7-1 ➡️ 25.2.8-1", "25.2.7-1", "25.2.8-1", true},
+		{"Kernel 6.17.11-300 → 6.17.12-300", "6.17.11-300", "6.17.12-300", true},
+		{"v1.4.0 -> v1.5.0", "v1.4.0", "v1.5.0", true},
+		{"Updated from 1.2.3 to 1.3.0.", "1.2.3", "1.3.0", true},
+		{"Bump foo from v2.0 to v2.1", "v2.0", "v2.1", true},
+		{"48.1 to 48.2", "48.1", "48.2", true},
+		{"Runs 3 to 5 times faster", "", "", false},
+		{"Release 1.2.3", "", "", false},
+		{"", "", "", false},
+	}
+
+	for _, tt := range tests {
+		from, to, ok := ParseTransition(tt.input)
+		if from != tt.wantFrom || to != tt.wantTo || ok != tt.wantOK {
+			t.Errorf("ParseTransition(%q): expected (%q, %q, %v), got (%q, %q, %v)", tt.input, tt.wantFrom, tt.wantTo, tt.wantOK, from, to, ok)
+		}
+	}
+}
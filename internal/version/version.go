@@ -0,0 +1,174 @@
+package version
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// numericRe matches the dotted numeric core of a version string
+var numericRe = regexp.MustCompile(`\d+(?:\.\d+)*`)
+
+// Normalize extracts the dotted numeric core of a version tag, dropping
+// prefixes and suffixes ("v1.2.3" → "1.2.3", "release-2.0-rc1" → "2.0").
+// Returns "" when the tag has no numeric part.
+func Normalize(v string) string {
+	return numericRe.FindString(v)
+}
+
+// Key matches versions across sources ("v1.2.0" and "1.2.0"), falling back
+// to the tag itself when it has no numeric part
+func Key(v string) string {
+	if normalized := Normalize(v); normalized != "" {
+		return normalized
+	}
+	return v
+}
+
+// Major returns the major component of a version tag
+func Major(v string) (int, bool) {
+	normalized := Normalize(v)
+	if normalized == "" {
+		return 0, false
+	}
+	major, err := strconv.Atoi(strings.SplitN(normalized, ".", 2)[0])
+	if err != nil {
+		return 0, false
+	}
+	return major, true
+}
+
+// IsCalendar reports whether a version looks date-based (e.g. "2026.02.1" or
+// "20260203"), where a bump in the first component carries no compatibility meaning
+func IsCalendar(v string) bool {
+	major, ok := Major(v)
+	return ok && major >= 1000
+}
+
+// Version is a release version split into its numeric core and any
+// prerelease qualifier
+type Version struct {
+	Core       []int    // Dotted numeric core, e.g. [1 2 3]
+	Prerelease []string // Prerelease identifiers, e.g. ["rc", "1"]; empty for final releases
+}
+
+// prereleaseRe matches the qualifiers that mark a suffix as a prerelease
+// ("-rc1", "-beta.2", ".dev0", "b1"), as opposed to packaging suffixes like "-linux"
+var prereleaseRe = regexp.MustCompile(`^(?i)(alpha|beta|rc|pre|preview|dev|nightly|snapshot|canary|[ab]\d)`)
+
+// identifierRe splits a prerelease suffix into alternating word and number
+// identifiers, so "rc10" orders after "rc2"
+var identifierRe = regexp.MustCompile(`[a-z]+|\d+`)
+
+// Parse splits a version tag into its numeric core and prerelease qualifier
+// ("v1.2.3-rc1" → [1 2 3] + ["rc" "1"]). Build metadata after "+" is ignored.
+// Returns false when the tag has no numeric part.
+func Parse(v string) (Version, bool) {
+	loc := numericRe.FindStringIndex(v)
+	if loc == nil {
+		return Version{}, false
+	}
+
+	var parsed Version
+	for _, part := range strings.Split(v[loc[0]:loc[1]], ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, false
+		}
+		parsed.Core = append(parsed.Core, n)
+	}
+
+	suffix := v[loc[1]:]
+	if i := strings.IndexByte(suffix, '+'); i >= 0 {
+		suffix = suffix[:i]
+	}
+	suffix = strings.TrimLeft(suffix, "-._~")
+	if prereleaseRe.MatchString(suffix) {
+		parsed.Prerelease = identifierRe.FindAllString(strings.ToLower(suffix), -1)
+	}
+	return parsed, true
+}
+
+// IsPrerelease reports whether the version carries a prerelease qualifier
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare orders two version tags by semver precedence: numeric cores first
+// (missing components count as zero), then a final release ranks above its
+// prereleases, then prerelease identifiers compare pairwise. It returns -1, 0
+// or 1; tags without a numeric part compare equal to everything.
+func Compare(a, b string) int {
+	va, okA := Parse(a)
+	vb, okB := Parse(b)
+	if !okA || !okB {
+		return 0
+	}
+
+	for i := 0; i < len(va.Core) || i < len(vb.Core); i++ {
+		var x, y int
+		if i < len(va.Core) {
+			x = va.Core[i]
+		}
+		if i < len(vb.Core) {
+			y = vb.Core[i]
+		}
+		if x != y {
+			return compareInts(x, y)
+		}
+	}
+
+	switch {
+	case !va.IsPrerelease() && !vb.IsPrerelease():
+		return 0
+	case !va.IsPrerelease():
+		return 1
+	case !vb.IsPrerelease():
+		return -1
+	}
+
+	for i := 0; i < len(va.Prerelease) && i < len(vb.Prerelease); i++ {
+		if c := compareIdentifiers(va.Prerelease[i], vb.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(va.Prerelease), len(vb.Prerelease))
+}
+
+// compareIdentifiers compares prerelease identifiers per semver: numbers
+// numerically, words lexically, and numbers below words
+func compareIdentifiers(a, b string) int {
+	aNum, bNum := isDigits(a), isDigits(b)
+	switch {
+	case aNum && bNum:
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			return compareInts(len(a), len(b))
+		}
+		return strings.Compare(a, b)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,93 @@
+package version
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"v1.2.3", "1.2.3"},
+		{"1.2", "1.2"},
+		{"release-2.0-rc1", "2.0"},
+		{"stable-20260203", "20260203"},
+		{"nightly", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := Normalize(tt.input); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMajor(t *testing.T) {
+	if major, ok := Major("v12.0.1"); !ok || major != 12 {
+		t.Errorf("Expected 12, got %d (ok=%v)", major, ok)
+	}
+	if _, ok := Major("latest"); ok {
+		t.Error("Expected no major for non-numeric tag")
+	}
+	if !IsCalendar("2026.02.1") || IsCalendar("3.1.0") {
+		t.Error("Expected only date-based versions to be calendar versions")
+	}
+}
+
+func TestParsePrerelease(t *testing.T) {
+	tests := []struct {
+		input      string
+		prerelease bool
+	}{
+		{"v1.2.3", false},
+		{"1.2.3-rc1", true},
+		{"2.0.0-beta.2", true},
+		{"0.9.dev0", true},
+		{"3.12.0b1", true},
+		{"1.4.0-linux", false},
+		{"1.0.0+build.5", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, ok := Parse(tt.input)
+			if !ok {
+				t.Fatalf("Expected %q to parse", tt.input)
+			}
+			if v.IsPrerelease() != tt.prerelease {
+				t.Errorf("Expected prerelease=%v, got %v (%v)", tt.prerelease, v.IsPrerelease(), v.Prerelease)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// Each sequence is in ascending precedence order
+	sequences := [][]string{
+		{"1.2.3-rc1", "1.2.3-rc2", "1.2.3-rc10", "1.2.3"},
+		{"2.0.0-alpha", "2.0.0-alpha.1", "2.0.0-beta", "2.0.0-beta.2", "2.0.0-rc.1", "2.0.0"},
+		{"0.9.dev0", "0.9.dev1", "0.9", "0.9.1"},
+		{"v1.9", "v1.10.0-beta", "v1.10.0"},
+	}
+
+	for _, seq := range sequences {
+		t.Run(strings.Join(seq, "<"), func(t *testing.T) {
+			for i := 1; i < len(seq); i++ {
+				if got := Compare(seq[i-1], seq[i]); got != -1 {
+					t.Errorf("Expected %s < %s, got %d", seq[i-1], seq[i], got)
+				}
+				if got := Compare(seq[i], seq[i-1]); got != 1 {
+					t.Errorf("Expected %s > %s, got %d", seq[i], seq[i-1], got)
+				}
+			}
+		})
+	}
+
+	if got := Compare("v1.2.0", "1.2"); got != 0 {
+		t.Errorf("Expected v1.2.0 == 1.2, got %d", got)
+	}
+}